@@ -1,57 +1,122 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/go-chi/chi"
-	"github.com/go-chi/chi/middleware"
-
-	"avi/internal/api/bid"
-	"avi/internal/api/tender"
+	"avi/internal/api/health"
+	"avi/internal/api/openapi"
+	"avi/internal/api/router"
+	"avi/internal/buildinfo"
+	"avi/internal/config"
+	"avi/internal/database"
+	"avi/internal/idempotency"
+	"avi/internal/logging"
+	"avi/internal/metrics"
+	"avi/internal/ratelimit"
+	"avi/internal/schema"
+	"avi/internal/server"
+	"avi/internal/service/attachment"
+	"avi/internal/service/bid"
+	"avi/internal/service/servicetype"
+	"avi/internal/storage"
+	"avi/internal/tracing"
 )
 
 func main() {
-	r := chi.NewRouter()
-	r.Use(middleware.Logger)
-	r.Route("/api", func(r chi.Router) {
-		r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
-			res, _ := json.Marshal("ok")
-			w.Write(res)
-		})
-		r.Route("/tenders", func(r chi.Router) {
-			r.Get("/", tender.GetTendersHandler)
-			r.Post("/new", tender.CreateTenderHandler)
-			r.Get("/my", tender.GetMyTendersHandler)
-			r.Patch("/{tenderId}/edit", tender.EditTenderHandler)
-			r.Get("/{tenderId}/status", tender.GetTenderStatusHandler)
-			r.Put("/{tenderId}/status", tender.UpdateTenderStatusHandler)
-			r.Put("/{tenderId}/rollback/{version}", tender.RollbackTenderHandler)
-		})
-		r.Route("/bids", func(r chi.Router) {
-			r.Post("/new", bid.CreateBidHandler)
-			r.Get("/my", bid.GetMyBidsHandler)
-			r.Get("/{tenderId}/list", bid.GetBidsHandler)
-			r.Get("/{bidId}/status", bid.GetBidStatusHandler)
-			r.Put("/{bidId}/status", bid.UpdateBidStatusHandler)
-			r.Patch("/{bidId}/edit", bid.EditBidHandler)
-			r.Put("/{bidId}/submit_decision", bid.SumbitDecisionHandler)
-			r.Put("/{bidId}/feedback", bid.FeedbackHandler)
-			r.Put("/{bidId}/rollback/{version}", bid.RollbackHandler)
-			r.Get("/{tenderId}/reviews", bid.GetReviewsHandler)
-		})
-	})
-
-	serverAdd, ok := os.LookupEnv("SERVER_ADDRESS")
-	if !ok || serverAdd == "" {
-		errMsg := "no SERVER_ADDRESS environment variable or empty"
-		slog.Error(errMsg)
-		return
-	}
-	err := http.ListenAndServe(serverAdd, r)
+	err := run()
 	if err != nil {
 		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	configPath := flag.String("config", os.Getenv("CONFIG_PATH"), "path to YAML config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
 	}
+
+	_, err = logging.Setup(cfg.Logging, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	info := buildinfo.Get()
+	slog.Info("starting avi", "version", info.Version, "commit", info.Commit)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		err := shutdownTracing(ctx)
+		if err != nil {
+			slog.Error("tracing shutdown: " + err.Error())
+		}
+	}()
+
+	db, err := database.Open(ctx, cfg.Postgres)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	err = metrics.RegisterDB(db)
+	if err != nil {
+		return err
+	}
+
+	err = schema.Apply()
+	if err != nil {
+		return err
+	}
+
+	var workers []server.Worker
+	limiter, err := ratelimit.Setup(ctx, cfg.RateLimit, db)
+	if err != nil {
+		return err
+	}
+	if limiter != nil {
+		workers = append(workers, limiter)
+	}
+	guard, err := idempotency.Setup(cfg.Idempotency, cfg.Attachments)
+	if err != nil {
+		return err
+	}
+	if guard != nil {
+		workers = append(workers, guard)
+	}
+
+	blobStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		return err
+	}
+	attachment.Setup(cfg.Attachments, blobStorage)
+	bid.Setup(cfg.RateLimit)
+	servicetype.Setup(cfg.Admin)
+
+	r := router.New()
+	err = openapi.CheckRoutes(r)
+	if err != nil {
+		return err
+	}
+
+	srv := server.New(cfg.Server, r, workers...)
+	health.Register(r, health.NewChecker(db, srv))
+	r.Method(http.MethodGet, "/metrics", metrics.Handler())
+	return srv.Run(ctx)
 }
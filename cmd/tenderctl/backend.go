@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"avi/internal/config"
+	"avi/internal/database"
+	"avi/internal/model"
+	"avi/internal/repository/bid"
+	"avi/internal/repository/organization"
+	"avi/internal/repository/tender"
+	"avi/internal/repository/user"
+	"avi/internal/schema"
+	"avi/pkg/client"
+)
+
+var errAdminOnly = errors.New("command is available only in admin mode")
+
+type backend interface {
+	Tenders(ctx context.Context, serviceType model.TenderServiceType, my bool, page client.Page) ([]*model.Tender, error)
+	SetTenderStatus(ctx context.Context, id uuid.UUID, status model.TenderStatus) (*model.Tender, error)
+	RollbackTender(ctx context.Context, id uuid.UUID, version int32) (*model.Tender, error)
+	TenderHistory(ctx context.Context, id uuid.UUID) ([]*model.Tender, error)
+	Bids(ctx context.Context, tenderId uuid.UUID, page client.Page) ([]*model.Bid, error)
+	SetBidStatus(ctx context.Context, id uuid.UUID, status model.BidStatus) (*model.Bid, error)
+	RollbackBid(ctx context.Context, id uuid.UUID, version int32) (*model.Bid, error)
+	BidHistory(ctx context.Context, id uuid.UUID) ([]*model.Bid, error)
+	Responsibles(ctx context.Context, orgId uuid.UUID) ([]*model.User, error)
+	AddResponsible(ctx context.Context, orgId uuid.UUID, username string) error
+	RemoveResponsible(ctx context.Context, orgId uuid.UUID, username string) error
+	Close() error
+}
+
+type apiBackend struct {
+	client   *client.Client
+	username string
+}
+
+func newAPIBackend(cfg Config) (*apiBackend, error) {
+	c, err := client.New(cfg.Server)
+	if err != nil {
+		return nil, err
+	}
+	return &apiBackend{client: c, username: cfg.Username}, nil
+}
+
+func (b *apiBackend) Close() error {
+	return nil
+}
+
+func (b *apiBackend) Tenders(
+	ctx context.Context, serviceType model.TenderServiceType, my bool, page client.Page,
+) ([]*model.Tender, error) {
+	if my {
+		return b.client.GetMyTenders(ctx, b.username, page)
+	}
+	return b.client.GetTenders(ctx, serviceType, page)
+}
+
+func (b *apiBackend) SetTenderStatus(
+	ctx context.Context, id uuid.UUID, status model.TenderStatus,
+) (*model.Tender, error) {
+	return b.client.UpdateTenderStatus(ctx, id, status, b.username)
+}
+
+func (b *apiBackend) RollbackTender(
+	ctx context.Context, id uuid.UUID, version int32,
+) (*model.Tender, error) {
+	return b.client.RollbackTender(ctx, id, version, b.username)
+}
+
+func (b *apiBackend) TenderHistory(ctx context.Context, id uuid.UUID) ([]*model.Tender, error) {
+	return b.client.GetTenderHistory(ctx, id, b.username)
+}
+
+func (b *apiBackend) Bids(
+	ctx context.Context, tenderId uuid.UUID, page client.Page,
+) ([]*model.Bid, error) {
+	return b.client.GetBidsForTender(ctx, tenderId, b.username, page)
+}
+
+func (b *apiBackend) SetBidStatus(
+	ctx context.Context, id uuid.UUID, status model.BidStatus,
+) (*model.Bid, error) {
+	return b.client.UpdateBidStatus(ctx, id, status, b.username)
+}
+
+func (b *apiBackend) RollbackBid(
+	ctx context.Context, id uuid.UUID, version int32,
+) (*model.Bid, error) {
+	return b.client.RollbackBid(ctx, id, version, b.username)
+}
+
+func (b *apiBackend) BidHistory(ctx context.Context, id uuid.UUID) ([]*model.Bid, error) {
+	return b.client.GetBidHistory(ctx, id, b.username)
+}
+
+func (b *apiBackend) Responsibles(ctx context.Context, orgId uuid.UUID) ([]*model.User, error) {
+	return nil, errAdminOnly
+}
+
+func (b *apiBackend) AddResponsible(ctx context.Context, orgId uuid.UUID, username string) error {
+	return errAdminOnly
+}
+
+func (b *apiBackend) RemoveResponsible(ctx context.Context, orgId uuid.UUID, username string) error {
+	return errAdminOnly
+}
+
+type adminBackend struct {
+	tenderRepo *tender.TenderRepo
+	bidRepo    *bid.BidRepo
+	orgRepo    *organization.OrganizationRepo
+	userRepo   *user.UserRepo
+}
+
+func newAdminBackend(ctx context.Context, cfg Config) (backend *adminBackend, err error) {
+	path := cfg.ServerConfig
+	if path == "" {
+		path = os.Getenv("CONFIG_PATH")
+	}
+	serverCfg, err := config.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.PostgresConn != "" {
+		serverCfg.Postgres.Conn = cfg.PostgresConn
+	}
+	err = serverCfg.Postgres.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = database.Open(ctx, serverCfg.Postgres)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			database.Close()
+		}
+	}()
+
+	err = schema.Apply()
+	if err != nil {
+		return nil, err
+	}
+
+	backend = &adminBackend{}
+	backend.tenderRepo, err = tender.NewRepo()
+	if err != nil {
+		return nil, err
+	}
+	backend.bidRepo, err = bid.NewRepo()
+	if err != nil {
+		return nil, err
+	}
+	backend.orgRepo, err = organization.NewRepo()
+	if err != nil {
+		return nil, err
+	}
+	backend.userRepo, err = user.NewRepo()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (b *adminBackend) Close() error {
+	return database.Close()
+}
+
+func (b *adminBackend) Tenders(
+	ctx context.Context, serviceType model.TenderServiceType, my bool, page client.Page,
+) ([]*model.Tender, error) {
+	if my {
+		return nil, errors.New("--my is not supported in admin mode")
+	}
+	var offset, limit string
+	if page.Offset > 0 {
+		offset = strconv.Itoa(page.Offset)
+	}
+	if page.Limit > 0 {
+		limit = strconv.Itoa(page.Limit)
+	}
+	var serviceTypes []string
+	if serviceType != "" {
+		serviceTypes = []string{string(serviceType)}
+	}
+	return b.tenderRepo.GetTenders(ctx, serviceTypes, offset, limit, nil, nil)
+}
+
+func (b *adminBackend) SetTenderStatus(
+	ctx context.Context, id uuid.UUID, status model.TenderStatus,
+) (*model.Tender, error) {
+	tender, err := b.tenderRepo.GetTenderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tender.Status = status
+	return b.tenderRepo.UpdateTender(ctx, tender)
+}
+
+func (b *adminBackend) RollbackTender(
+	ctx context.Context, id uuid.UUID, version int32,
+) (*model.Tender, error) {
+	return b.tenderRepo.RollBackTender(ctx, id, version)
+}
+
+func (b *adminBackend) TenderHistory(ctx context.Context, id uuid.UUID) ([]*model.Tender, error) {
+	tender, err := b.tenderRepo.GetTenderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	history, err := b.tenderRepo.GetTenderHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return append(history, tender), nil
+}
+
+func (b *adminBackend) Bids(
+	ctx context.Context, tenderId uuid.UUID, page client.Page,
+) ([]*model.Bid, error) {
+	return b.bidRepo.GetBidsByTenderId(ctx, page.Offset, page.Limit, tenderId, "")
+}
+
+func (b *adminBackend) SetBidStatus(
+	ctx context.Context, id uuid.UUID, status model.BidStatus,
+) (*model.Bid, error) {
+	return b.bidRepo.UpdateBidStatusById(ctx, id, status)
+}
+
+func (b *adminBackend) RollbackBid(
+	ctx context.Context, id uuid.UUID, version int32,
+) (*model.Bid, error) {
+	return b.bidRepo.RollbackById(ctx, id, version)
+}
+
+func (b *adminBackend) BidHistory(ctx context.Context, id uuid.UUID) ([]*model.Bid, error) {
+	bid, err := b.bidRepo.GetBidById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	history, err := b.bidRepo.GetBidHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return append(history, bid), nil
+}
+
+func (b *adminBackend) Responsibles(ctx context.Context, orgId uuid.UUID) ([]*model.User, error) {
+	_, err := b.orgRepo.GetOrganizationById(ctx, orgId)
+	if err != nil {
+		return nil, errors.New("organization does not exist")
+	}
+	usersId, err := b.orgRepo.GetResponsibleUsersId(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*model.User, 0, len(usersId))
+	for _, userId := range usersId {
+		user, err := b.userRepo.GetUserById(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (b *adminBackend) AddResponsible(ctx context.Context, orgId uuid.UUID, username string) error {
+	_, err := b.orgRepo.GetOrganizationById(ctx, orgId)
+	if err != nil {
+		return errors.New("organization does not exist")
+	}
+	user, err := b.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return errors.New("user does not exist")
+	}
+	return b.orgRepo.AddResponsible(ctx, orgId, user.Id)
+}
+
+func (b *adminBackend) RemoveResponsible(ctx context.Context, orgId uuid.UUID, username string) error {
+	user, err := b.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return errors.New("user does not exist")
+	}
+	return b.orgRepo.RemoveResponsible(ctx, orgId, user.Id)
+}
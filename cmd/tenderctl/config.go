@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type Config struct {
+	Server       string `json:"server"`
+	Username     string `json:"username"`
+	PostgresConn string `json:"postgresConn"`
+	ServerConfig string `json:"serverConfig"`
+}
+
+func defaultConfigPath() string {
+	if path, ok := os.LookupEnv("TENDERCTL_CONFIG"); ok {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tenderctl", "config.json")
+}
+
+func loadConfig(path string, required bool) (cfg Config, err error) {
+	cfg.Server = "http://localhost:8080"
+	if path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return cfg, nil
+	}
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &cfg)
+	return
+}
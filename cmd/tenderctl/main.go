@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+	"avi/pkg/client"
+)
+
+const usage = `usage: tenderctl [global flags] <command> [flags] [args]
+
+commands:
+  tenders list [--service-type T] [--search S] [--my] [--offset N] [--limit N]
+  tenders status <tenderId> <Created|Published|Closed>
+  tenders rollback <tenderId> <version>
+  tenders history <tenderId>
+  bids list <tenderId> [--offset N] [--limit N]
+  bids status <bidId> <Created|Published|Canceled>
+  bids rollback <bidId> <version>
+  bids history <bidId>
+  responsibles list <organizationId>            (admin mode)
+  responsibles add <organizationId> <username>  (admin mode)
+  responsibles remove <organizationId> <username> (admin mode)
+
+global flags:
+`
+
+type app struct {
+	backend backend
+	format  string
+}
+
+func main() {
+	err := run(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tenderctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	global := flag.NewFlagSet("tenderctl", flag.ContinueOnError)
+	global.Usage = func() {
+		fmt.Fprint(global.Output(), usage)
+		global.PrintDefaults()
+	}
+	configPath := global.String("config", "", "path to config file (default $TENDERCTL_CONFIG or user config dir)")
+	server := global.String("server", "", "API server address, overrides config")
+	username := global.String("username", "", "username to act as, overrides config")
+	admin := global.Bool("admin", false, "talk directly to the database instead of the API")
+	format := global.String("output", formatTable, "output format: table or json")
+	err := global.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *format != formatTable && *format != formatJSON {
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+
+	path := *configPath
+	if path == "" {
+		path = defaultConfigPath()
+	}
+	cfg, err := loadConfig(path, *configPath != "")
+	if err != nil {
+		return fmt.Errorf("can not load config: %w", err)
+	}
+	if *server != "" {
+		cfg.Server = *server
+	}
+	if *username != "" {
+		cfg.Username = *username
+	}
+
+	rest := global.Args()
+	if len(rest) < 2 {
+		global.Usage()
+		return errors.New("command is required")
+	}
+
+	ctx := context.Background()
+	a := &app{format: *format}
+	if *admin {
+		a.backend, err = newAdminBackend(ctx, cfg)
+	} else {
+		if cfg.Username == "" {
+			return errors.New("username is required, set it in config or with --username")
+		}
+		a.backend, err = newAPIBackend(cfg)
+	}
+	if err != nil {
+		return err
+	}
+	defer a.backend.Close()
+
+	resource, action, cmdArgs := rest[0], rest[1], rest[2:]
+	switch resource {
+	case "tenders":
+		return a.tenders(ctx, action, cmdArgs)
+	case "bids":
+		return a.bids(ctx, action, cmdArgs)
+	case "responsibles":
+		return a.responsibles(ctx, action, cmdArgs)
+	}
+	return fmt.Errorf("unknown command %q", resource)
+}
+
+func (a *app) tenders(ctx context.Context, action string, args []string) error {
+	switch action {
+	case "list":
+		fs := flag.NewFlagSet("tenders list", flag.ContinueOnError)
+		serviceType := fs.String("service-type", "", "filter by service type")
+		search := fs.String("search", "", "filter by substring of name or description")
+		my := fs.Bool("my", false, "list tenders created by the user")
+		page := pageFlags(fs)
+		err := fs.Parse(args)
+		if err != nil {
+			return err
+		}
+		tenders, err := a.backend.Tenders(
+			ctx, model.TenderServiceType(*serviceType), *my, *page,
+		)
+		if err != nil {
+			return err
+		}
+		if *search != "" {
+			tenders = searchTenders(tenders, *search)
+		}
+		return printTenders(os.Stdout, a.format, tenders)
+	case "status":
+		id, err := argId(args, 0, "tenderId")
+		if err != nil {
+			return err
+		}
+		if len(args) < 2 {
+			return errors.New("status is required")
+		}
+		tender, err := a.backend.SetTenderStatus(ctx, id, model.TenderStatus(args[1]))
+		if err != nil {
+			return err
+		}
+		return printTenders(os.Stdout, a.format, []*model.Tender{tender})
+	case "rollback":
+		id, err := argId(args, 0, "tenderId")
+		if err != nil {
+			return err
+		}
+		version, err := argVersion(args, 1)
+		if err != nil {
+			return err
+		}
+		tender, err := a.backend.RollbackTender(ctx, id, version)
+		if err != nil {
+			return err
+		}
+		return printTenders(os.Stdout, a.format, []*model.Tender{tender})
+	case "history":
+		id, err := argId(args, 0, "tenderId")
+		if err != nil {
+			return err
+		}
+		tenders, err := a.backend.TenderHistory(ctx, id)
+		if err != nil {
+			return err
+		}
+		return printTenderHistory(os.Stdout, a.format, tenders)
+	}
+	return fmt.Errorf("unknown tenders command %q", action)
+}
+
+func (a *app) bids(ctx context.Context, action string, args []string) error {
+	switch action {
+	case "list":
+		id, err := argId(args, 0, "tenderId")
+		if err != nil {
+			return err
+		}
+		fs := flag.NewFlagSet("bids list", flag.ContinueOnError)
+		page := pageFlags(fs)
+		err = fs.Parse(args[1:])
+		if err != nil {
+			return err
+		}
+		bids, err := a.backend.Bids(ctx, id, *page)
+		if err != nil {
+			return err
+		}
+		return printBids(os.Stdout, a.format, bids)
+	case "status":
+		id, err := argId(args, 0, "bidId")
+		if err != nil {
+			return err
+		}
+		if len(args) < 2 {
+			return errors.New("status is required")
+		}
+		bid, err := a.backend.SetBidStatus(ctx, id, model.BidStatus(args[1]))
+		if err != nil {
+			return err
+		}
+		return printBids(os.Stdout, a.format, []*model.Bid{bid})
+	case "rollback":
+		id, err := argId(args, 0, "bidId")
+		if err != nil {
+			return err
+		}
+		version, err := argVersion(args, 1)
+		if err != nil {
+			return err
+		}
+		bid, err := a.backend.RollbackBid(ctx, id, version)
+		if err != nil {
+			return err
+		}
+		return printBids(os.Stdout, a.format, []*model.Bid{bid})
+	case "history":
+		id, err := argId(args, 0, "bidId")
+		if err != nil {
+			return err
+		}
+		bids, err := a.backend.BidHistory(ctx, id)
+		if err != nil {
+			return err
+		}
+		return printBidHistory(os.Stdout, a.format, bids)
+	}
+	return fmt.Errorf("unknown bids command %q", action)
+}
+
+func (a *app) responsibles(ctx context.Context, action string, args []string) error {
+	orgId, err := argId(args, 0, "organizationId")
+	if err != nil {
+		return err
+	}
+	switch action {
+	case "list":
+		users, err := a.backend.Responsibles(ctx, orgId)
+		if err != nil {
+			return err
+		}
+		return printUsers(os.Stdout, a.format, users)
+	case "add", "remove":
+		if len(args) < 2 {
+			return errors.New("username is required")
+		}
+		if action == "add" {
+			err = a.backend.AddResponsible(ctx, orgId, args[1])
+		} else {
+			err = a.backend.RemoveResponsible(ctx, orgId, args[1])
+		}
+		if err != nil {
+			return err
+		}
+		users, err := a.backend.Responsibles(ctx, orgId)
+		if err != nil {
+			return err
+		}
+		return printUsers(os.Stdout, a.format, users)
+	}
+	return fmt.Errorf("unknown responsibles command %q", action)
+}
+
+func pageFlags(fs *flag.FlagSet) *client.Page {
+	page := &client.Page{}
+	fs.IntVar(&page.Offset, "offset", 0, "number of items to skip")
+	fs.IntVar(&page.Limit, "limit", 0, "maximum number of items")
+	return page
+}
+
+func argId(args []string, i int, name string) (uuid.UUID, error) {
+	if len(args) <= i {
+		return uuid.Nil, fmt.Errorf("%s is required", name)
+	}
+	id, err := uuid.Parse(args[i])
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("incorrect %s", name)
+	}
+	return id, nil
+}
+
+func argVersion(args []string, i int) (int32, error) {
+	if len(args) <= i {
+		return 0, errors.New("version is required")
+	}
+	version, err := strconv.Atoi(args[i])
+	if err != nil || version < 1 {
+		return 0, errors.New("incorrect version")
+	}
+	return int32(version), nil
+}
+
+func searchTenders(tenders []*model.Tender, search string) []*model.Tender {
+	search = strings.ToLower(search)
+	found := []*model.Tender{}
+	for _, tender := range tenders {
+		if strings.Contains(strings.ToLower(tender.Name), search) ||
+			strings.Contains(strings.ToLower(tender.Description), search) {
+			found = append(found, tender)
+		}
+	}
+	return found
+}
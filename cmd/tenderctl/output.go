@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"avi/internal/model"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+type field struct {
+	name  string
+	value string
+}
+
+func tenderFields(tender *model.Tender) []field {
+	return []field{
+		{"name", tender.Name},
+		{"description", tender.Description},
+		{"serviceType", string(tender.ServiceType)},
+		{"status", string(tender.Status)},
+		{"organizationId", tender.OrganizationId.String()},
+	}
+}
+
+func bidFields(bid *model.Bid) []field {
+	return []field{
+		{"name", bid.Name},
+		{"description", bid.Description},
+		{"status", string(bid.Status)},
+		{"tenderId", bid.TenderId.String()},
+		{"authorType", string(bid.AuthorType)},
+		{"authorId", bid.AuthorId.String()},
+	}
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func printTenders(w io.Writer, format string, tenders []*model.Tender) error {
+	if format == formatJSON {
+		if tenders == nil {
+			tenders = []*model.Tender{}
+		}
+		return printJSON(w, tenders)
+	}
+	rows := make([][]string, 0, len(tenders))
+	for _, tender := range tenders {
+		rows = append(rows, []string{
+			tender.Id.String(),
+			tender.Name,
+			string(tender.ServiceType),
+			string(tender.Status),
+			strconv.Itoa(int(tender.Version)),
+			tender.CreatedAt.Format(time.DateTime),
+		})
+	}
+	return printTable(
+		w,
+		[]string{"ID", "NAME", "SERVICE TYPE", "STATUS", "VERSION", "CREATED AT"},
+		rows,
+	)
+}
+
+func printBids(w io.Writer, format string, bids []*model.Bid) error {
+	if format == formatJSON {
+		if bids == nil {
+			bids = []*model.Bid{}
+		}
+		return printJSON(w, bids)
+	}
+	rows := make([][]string, 0, len(bids))
+	for _, bid := range bids {
+		rows = append(rows, []string{
+			bid.Id.String(),
+			bid.Name,
+			string(bid.Status),
+			string(bid.AuthorType),
+			bid.AuthorId.String(),
+			strconv.Itoa(int(bid.Version)),
+			bid.CreatedAt.Format(time.DateTime),
+		})
+	}
+	return printTable(
+		w,
+		[]string{"ID", "NAME", "STATUS", "AUTHOR TYPE", "AUTHOR ID", "VERSION", "CREATED AT"},
+		rows,
+	)
+}
+
+func printUsers(w io.Writer, format string, users []*model.User) error {
+	if format == formatJSON {
+		return printJSON(w, users)
+	}
+	rows := make([][]string, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, []string{
+			user.Id.String(),
+			user.Username,
+			user.FirstName,
+			user.LastName,
+		})
+	}
+	return printTable(w, []string{"ID", "USERNAME", "FIRST NAME", "LAST NAME"}, rows)
+}
+
+func printTenderHistory(w io.Writer, format string, tenders []*model.Tender) error {
+	if format == formatJSON {
+		return printJSON(w, tenders)
+	}
+	versions := make([]int32, len(tenders))
+	fields := make([][]field, len(tenders))
+	for i, tender := range tenders {
+		versions[i] = tender.Version
+		fields[i] = tenderFields(tender)
+	}
+	return printDiffs(w, versions, fields)
+}
+
+func printBidHistory(w io.Writer, format string, bids []*model.Bid) error {
+	if format == formatJSON {
+		return printJSON(w, bids)
+	}
+	versions := make([]int32, len(bids))
+	fields := make([][]field, len(bids))
+	for i, bid := range bids {
+		versions[i] = bid.Version
+		fields[i] = bidFields(bid)
+	}
+	return printDiffs(w, versions, fields)
+}
+
+func printDiffs(w io.Writer, versions []int32, fields [][]field) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "version %d\n", versions[0])
+	for _, f := range fields[0] {
+		fmt.Fprintf(w, "  %s: %q\n", f.name, f.value)
+	}
+	for i := 1; i < len(fields); i++ {
+		fmt.Fprintf(w, "version %d -> %d\n", versions[i-1], versions[i])
+		changed := false
+		for j, f := range fields[i] {
+			prev := fields[i-1][j]
+			if prev.value == f.value {
+				continue
+			}
+			changed = true
+			fmt.Fprintf(w, "  - %s: %q\n", f.name, prev.value)
+			fmt.Fprintf(w, "  + %s: %q\n", f.name, f.value)
+		}
+		if !changed {
+			fmt.Fprintln(w, "  no changes")
+		}
+	}
+	return nil
+}
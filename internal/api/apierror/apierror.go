@@ -2,14 +2,29 @@ package apierror
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"avi/internal/database"
 )
 
+const StatusClientClosedRequest = 499
+
 type ErrorResponse struct {
 	Reason string `json:"reason"`
 }
 
 func HandleError(w http.ResponseWriter, r *http.Request, err error, status int) {
+	switch {
+	case r.Context().Err() != nil || errors.Is(err, database.ErrorQueryCanceled):
+		status = StatusClientClosedRequest
+	case errors.Is(err, database.ErrorQueryTimeout):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, database.ErrorLockNotAvailable):
+		w.Header().Set("Retry-After", "1")
+		status = http.StatusConflict
+	}
+
 	errRes := ErrorResponse{Reason: err.Error()}
 	res, _ := json.Marshal(errRes)
 	w.WriteHeader(status)
@@ -0,0 +1,692 @@
+package attachment
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"avi/internal/api/apierror"
+	"avi/internal/logging"
+	"avi/internal/model"
+	attachmentService "avi/internal/service/attachment"
+	bidService "avi/internal/service/bid"
+	templateService "avi/internal/service/template"
+	tenderService "avi/internal/service/tender"
+)
+
+func UploadTenderAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkTenderWriteRight(w, r, tenderId, username) {
+		return
+	}
+
+	service, err := attachmentService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("attachments service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	fileName, file, err := openFilePart(w, r, service.MaxSize())
+	if err != nil {
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	attachment, err := service.UploadTenderAttachment(r.Context(), tenderId, fileName, file)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(attachment)
+	w.Write(res)
+}
+
+func GetTenderAttachmentsHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	var version int
+	if versionQ := r.URL.Query().Get("version"); len(versionQ) != 0 {
+		version, _ = strconv.Atoi(versionQ)
+	}
+
+	username := r.URL.Query().Get("username")
+	if !checkTenderReadRight(w, r, tenderId, username) {
+		return
+	}
+
+	service, err := attachmentService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("attachments service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	attachments, err := service.GetTenderAttachments(r.Context(), tenderId, int32(version))
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+	if attachments == nil {
+		attachments = []*model.Attachment{}
+	}
+
+	res, _ := json.Marshal(attachments)
+	w.Write(res)
+}
+
+func DownloadTenderAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	attachmentId, err := uuid.Parse(chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		err = errors.New("incorrect attachment uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if !checkTenderReadRight(w, r, tenderId, username) {
+		return
+	}
+
+	serveAttachment(w, r, tenderId, attachmentId)
+}
+
+func DeleteTenderAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	attachmentId, err := uuid.Parse(chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		err = errors.New("incorrect attachment uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkTenderWriteRight(w, r, tenderId, username) {
+		return
+	}
+
+	service, err := attachmentService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("attachments service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	tender, err := service.DeleteTenderAttachment(r.Context(), tenderId, attachmentId)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(tender)
+	w.Write(res)
+}
+
+func UploadBidAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	bidId, err := uuid.Parse(chi.URLParam(r, "bidId"))
+	if err != nil {
+		err = errors.New("incorrect bid uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkBidWriteRight(w, r, bidId, username) {
+		return
+	}
+
+	service, err := attachmentService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("attachments service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	fileName, file, err := openFilePart(w, r, service.MaxSize())
+	if err != nil {
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	attachment, err := service.UploadBidAttachment(r.Context(), bidId, fileName, file)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(attachment)
+	w.Write(res)
+}
+
+func GetBidAttachmentsHandler(w http.ResponseWriter, r *http.Request) {
+	bidId, err := uuid.Parse(chi.URLParam(r, "bidId"))
+	if err != nil {
+		err = errors.New("incorrect bid uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	var version int
+	if versionQ := r.URL.Query().Get("version"); len(versionQ) != 0 {
+		version, _ = strconv.Atoi(versionQ)
+	}
+
+	if !checkBidReadRight(w, r, bidId, username) {
+		return
+	}
+
+	service, err := attachmentService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("attachments service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	attachments, err := service.GetBidAttachments(r.Context(), bidId, int32(version))
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+	if attachments == nil {
+		attachments = []*model.Attachment{}
+	}
+
+	res, _ := json.Marshal(attachments)
+	w.Write(res)
+}
+
+func DownloadBidAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	bidId, err := uuid.Parse(chi.URLParam(r, "bidId"))
+	if err != nil {
+		err = errors.New("incorrect bid uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	attachmentId, err := uuid.Parse(chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		err = errors.New("incorrect attachment uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkBidReadRight(w, r, bidId, username) {
+		return
+	}
+
+	serveAttachment(w, r, bidId, attachmentId)
+}
+
+func DeleteBidAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	bidId, err := uuid.Parse(chi.URLParam(r, "bidId"))
+	if err != nil {
+		err = errors.New("incorrect bid uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	attachmentId, err := uuid.Parse(chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		err = errors.New("incorrect attachment uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkBidWriteRight(w, r, bidId, username) {
+		return
+	}
+
+	service, err := attachmentService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("attachments service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	bid, err := service.DeleteBidAttachment(r.Context(), bidId, attachmentId)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(bid)
+	w.Write(res)
+}
+
+func UploadTemplateAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	templateId, err := uuid.Parse(chi.URLParam(r, "templateId"))
+	if err != nil {
+		err = errors.New("incorrect template uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkTemplateRight(w, r, templateId, username) {
+		return
+	}
+
+	service, err := attachmentService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("attachments service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	fileName, file, err := openFilePart(w, r, service.MaxSize())
+	if err != nil {
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	attachment, err := service.UploadTemplateAttachment(r.Context(), templateId, fileName, file)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(attachment)
+	w.Write(res)
+}
+
+func GetTemplateAttachmentsHandler(w http.ResponseWriter, r *http.Request) {
+	templateId, err := uuid.Parse(chi.URLParam(r, "templateId"))
+	if err != nil {
+		err = errors.New("incorrect template uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkTemplateRight(w, r, templateId, username) {
+		return
+	}
+
+	service, err := attachmentService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("attachments service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	attachments, err := service.GetTemplateAttachments(r.Context(), templateId)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+	if attachments == nil {
+		attachments = []*model.Attachment{}
+	}
+
+	res, _ := json.Marshal(attachments)
+	w.Write(res)
+}
+
+func DownloadTemplateAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	templateId, err := uuid.Parse(chi.URLParam(r, "templateId"))
+	if err != nil {
+		err = errors.New("incorrect template uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	attachmentId, err := uuid.Parse(chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		err = errors.New("incorrect attachment uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkTemplateRight(w, r, templateId, username) {
+		return
+	}
+
+	serveAttachment(w, r, templateId, attachmentId)
+}
+
+func DeleteTemplateAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	templateId, err := uuid.Parse(chi.URLParam(r, "templateId"))
+	if err != nil {
+		err = errors.New("incorrect template uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	attachmentId, err := uuid.Parse(chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		err = errors.New("incorrect attachment uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkTemplateRight(w, r, templateId, username) {
+		return
+	}
+
+	service, err := attachmentService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("attachments service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	template, err := service.DeleteTemplateAttachment(r.Context(), templateId, attachmentId)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(template)
+	w.Write(res)
+}
+
+func openFilePart(
+	w http.ResponseWriter, r *http.Request, maxSize int64,
+) (string, io.Reader, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+1<<20)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return "", nil, errors.New("multipart/form-data body is required")
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return "", nil, errors.New("file part is required")
+		}
+		if err != nil {
+			return "", nil, errors.New("incorrect request body")
+		}
+		if part.FormName() == "file" {
+			return part.FileName(), part, nil
+		}
+	}
+}
+
+func serveAttachment(
+	w http.ResponseWriter, r *http.Request, objectId uuid.UUID, attachmentId uuid.UUID,
+) {
+	service, err := attachmentService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("attachments service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	attachment, content, err := service.OpenAttachment(r.Context(), objectId, attachmentId)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(
+		"attachment", map[string]string{"filename": attachment.FileName},
+	))
+	w.Header().Set("ETag", `"`+attachment.Checksum+`"`)
+	w.Header().Set("X-Checksum-Sha256", attachment.Checksum)
+	_, err = io.Copy(w, content)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+	}
+}
+
+func handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := http.StatusBadRequest
+	if errors.Is(err, attachmentService.ErrorTenderNotFound) ||
+		errors.Is(err, attachmentService.ErrorBidNotFound) ||
+		errors.Is(err, attachmentService.ErrorTemplateNotFound) ||
+		errors.Is(err, attachmentService.ErrorAttachmentNotFound) {
+		httpStatus = http.StatusNotFound
+	}
+	if errors.Is(err, attachmentService.ErrorAttachmentTooLarge) {
+		httpStatus = http.StatusRequestEntityTooLarge
+	}
+	if errors.Is(err, attachmentService.ErrorAttachmentTypeNotAllowed) {
+		httpStatus = http.StatusUnsupportedMediaType
+	}
+	apierror.HandleError(w, r, err, httpStatus)
+}
+
+func checkTenderReadRight(
+	w http.ResponseWriter, r *http.Request, tenderId uuid.UUID, username string,
+) bool {
+	service, err := tenderService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("tenders service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return false
+	}
+
+	err = service.CheckReadRightByUsername(r.Context(), tenderId, username)
+	if err != nil {
+		handleTenderRightsError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func checkTenderWriteRight(
+	w http.ResponseWriter, r *http.Request, tenderId uuid.UUID, username string,
+) bool {
+	service, err := tenderService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("tenders service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return false
+	}
+
+	err = service.CheckWriteRightByUsername(r.Context(), tenderId, username)
+	if err != nil {
+		handleTenderRightsError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func handleTenderRightsError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := http.StatusBadRequest
+	if errors.Is(err, tenderService.ErrorTenderNorFound) {
+		httpStatus = http.StatusNotFound
+	}
+	if errors.Is(err, tenderService.ErrorUserNorFound) {
+		httpStatus = http.StatusUnauthorized
+	}
+	if errors.Is(err, tenderService.ErrorUserIsNotOrgResponsible) ||
+		errors.Is(err, tenderService.ErrorUserIsNotInvited) {
+		httpStatus = http.StatusForbidden
+	}
+	apierror.HandleError(w, r, err, httpStatus)
+}
+
+func checkBidReadRight(
+	w http.ResponseWriter, r *http.Request, bidId uuid.UUID, username string,
+) bool {
+	service, err := bidService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("bids service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return false
+	}
+
+	err = service.CheckReadRightByUsername(r.Context(), bidId, username)
+	if err != nil {
+		handleBidRightsError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func checkBidWriteRight(
+	w http.ResponseWriter, r *http.Request, bidId uuid.UUID, username string,
+) bool {
+	service, err := bidService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("bids service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return false
+	}
+
+	err = service.CheckAuthorRightByUsername(r.Context(), bidId, username)
+	if err != nil {
+		handleBidRightsError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func handleBidRightsError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := http.StatusBadRequest
+	if errors.Is(err, bidService.ErrorBidNotFound) ||
+		errors.Is(err, bidService.ErrorTenderNotFound) {
+		httpStatus = http.StatusNotFound
+	}
+	if errors.Is(err, bidService.ErrorUserNotFound) {
+		httpStatus = http.StatusUnauthorized
+	}
+	if errors.Is(err, bidService.ErrorUserIsNotBidAuthor) {
+		httpStatus = http.StatusForbidden
+	}
+	apierror.HandleError(w, r, err, httpStatus)
+}
+
+func checkTemplateRight(
+	w http.ResponseWriter, r *http.Request, templateId uuid.UUID, username string,
+) bool {
+	service, err := templateService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("templates service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return false
+	}
+
+	err = service.CheckRightByUsername(r.Context(), templateId, username)
+	if err != nil {
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, templateService.ErrorTemplateNotFound) {
+			httpStatus = http.StatusNotFound
+		}
+		if errors.Is(err, templateService.ErrorUserNotFound) {
+			httpStatus = http.StatusUnauthorized
+		}
+		if errors.Is(err, templateService.ErrorUserIsNotOrgResponsible) {
+			httpStatus = http.StatusForbidden
+		}
+		apierror.HandleError(w, r, err, httpStatus)
+		return false
+	}
+	return true
+}
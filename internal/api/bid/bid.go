@@ -3,30 +3,35 @@ package bid
 import (
 	"encoding/json"
 	"errors"
-	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 
 	"avi/internal/api/apierror"
+	"avi/internal/logging"
 	"avi/internal/model"
 	bidService "avi/internal/service/bid"
 )
 
 type CreateBidRequest struct {
-	Name        string              `json:"name"        validate:"required,max=100"`
-	Description string              `json:"description" validate:"required,max=500"`
-	TenderId    uuid.UUID           `json:"tenderId"    validate:"required,max=100"`
-	AuthorType  model.BidAuthorType `json:"authorType"  validate:"required,oneof=User Organization"`
-	AuthorId    uuid.UUID           `json:"authorID"    validate:"required,max=100"`
+	Name            string              `json:"name"        validate:"required,max=100"`
+	Description     string              `json:"description" validate:"required,max=500"`
+	TenderId        uuid.UUID           `json:"tenderId"    validate:"required,max=100"`
+	AuthorType      model.BidAuthorType `json:"authorType"  validate:"required,oneof=User Organization"`
+	AuthorId        uuid.UUID           `json:"authorID"        validate:"required,max=100"`
+	CreatorUsername string              `json:"creatorUsername" validate:"required"`
+	LotIds          []uuid.UUID         `json:"lotIds"`
+	Amount          *float64            `json:"amount"          validate:"omitempty,gt=0"`
 }
 
 type EditBidRequest struct {
-	Name        string `json:"name"        validate:"max=100"`
-	Description string `json:"description" validate:"max=500"`
+	Name        string   `json:"name"        validate:"max=100"`
+	Description string   `json:"description" validate:"max=500"`
+	Amount      *float64 `json:"amount"      validate:"omitempty,gt=0"`
 }
 
 func CreateBidHandler(w http.ResponseWriter, r *http.Request) {
@@ -35,7 +40,7 @@ func CreateBidHandler(w http.ResponseWriter, r *http.Request) {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 	err := validate.Struct(bidReq)
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err = errors.New("incorrect request body")
 		apierror.HandleError(w, r, err, http.StatusBadRequest)
 		return
@@ -43,18 +48,22 @@ func CreateBidHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := bidService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("bids service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
 	bid, err := service.CreateBid(
+		r.Context(),
 		bidReq.Name,
 		bidReq.Description,
 		bidReq.TenderId,
 		bidReq.AuthorType,
 		bidReq.AuthorId,
+		bidReq.CreatorUsername,
+		bidReq.LotIds,
+		bidReq.Amount,
 	)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
@@ -62,12 +71,27 @@ func CreateBidHandler(w http.ResponseWriter, r *http.Request) {
 			errors.Is(err, bidService.ErrorOrgNotFound) {
 			httpStatus = http.StatusUnauthorized
 		}
-		if errors.Is(err, bidService.ErrorUserIsNotOrgResponsible) {
+		var conflictErr *bidService.ConflictOfInterestError
+		if errors.Is(err, bidService.ErrorUserIsNotOrgResponsible) ||
+			errors.Is(err, bidService.ErrorUserIsNotBidAuthor) ||
+			errors.Is(err, bidService.ErrorAuthorIsNotInvited) ||
+			errors.Is(err, bidService.ErrorAuthorIsNotShortlisted) ||
+			errors.As(err, &conflictErr) {
 			httpStatus = http.StatusForbidden
 		}
-		if errors.Is(err, bidService.ErrorTenderNotFound) {
+		if errors.Is(err, bidService.ErrorConflictCheckFailed) {
+			httpStatus = http.StatusInternalServerError
+		}
+		if errors.Is(err, bidService.ErrorTenderNotFound) ||
+			errors.Is(err, bidService.ErrorLotNotFound) {
 			httpStatus = http.StatusNotFound
 		}
+		if errors.Is(err, bidService.ErrorLotIsNotOpen) {
+			httpStatus = http.StatusConflict
+		}
+		if errors.Is(err, bidService.ErrorBidLimitExceeded) {
+			httpStatus = http.StatusTooManyRequests
+		}
 		apierror.HandleError(w, r, err, httpStatus)
 		return
 	}
@@ -96,13 +120,13 @@ func GetMyBidsHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := bidService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("bids service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	bids, err := service.GetBidsByUsername(offset, limit, username)
+	bids, err := service.GetBidsByUsername(r.Context(), offset, limit, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorUserNotFound) {
@@ -143,13 +167,13 @@ func GetBidsHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := bidService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("bids service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	err = service.CheckRWRightsByUsername(tenderId, username)
+	err = service.CheckRWRightsByUsername(r.Context(), tenderId, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorTenderNotFound) {
@@ -165,7 +189,8 @@ func GetBidsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bids, err := service.GetBidsByTenderId(offset, limit, tenderId)
+	stage := model.BidStage(r.URL.Query().Get("stage"))
+	bids, err := service.GetBidsByTenderId(r.Context(), offset, limit, tenderId, stage)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorTenderNotFound) {
@@ -196,13 +221,13 @@ func GetBidStatusHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := bidService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("bids service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	bid, err := service.GetBidById(bidId)
+	bid, err := service.GetBidById(r.Context(), bidId)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorBidNotFound) {
@@ -212,7 +237,7 @@ func GetBidStatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = service.CheckRWRightsByUsername(bid.TenderId, username)
+	err = service.CheckRWRightsByUsername(r.Context(), bid.TenderId, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorTenderNotFound) {
@@ -256,13 +281,13 @@ func UpdateBidStatusHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := bidService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("bids service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	bid, err := service.GetBidById(bidId)
+	bid, err := service.GetBidById(r.Context(), bidId)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorBidNotFound) {
@@ -272,7 +297,7 @@ func UpdateBidStatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = service.CheckRWRightsByUsername(bid.TenderId, username)
+	err = service.CheckRWRightsByUsername(r.Context(), bid.TenderId, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorTenderNotFound) {
@@ -289,7 +314,7 @@ func UpdateBidStatusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	bid, err = service.UpdateBidStatusById(
-		bidId, model.BidStatus(status),
+		r.Context(), bidId, model.BidStatus(status),
 	)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
@@ -317,13 +342,13 @@ func EditBidHandler(w http.ResponseWriter, r *http.Request) {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 	err = validate.Struct(bidReq)
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err = errors.New("incorrect request body")
 		apierror.HandleError(w, r, err, http.StatusBadRequest)
 		return
 	}
 
-	if bidReq.Name == "" && bidReq.Description == "" {
+	if bidReq.Name == "" && bidReq.Description == "" && bidReq.Amount == nil {
 		err = errors.New("incorrect request body")
 		apierror.HandleError(w, r, err, http.StatusBadRequest)
 		return
@@ -338,13 +363,13 @@ func EditBidHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := bidService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("bids service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	bid, err := service.GetBidById(bidId)
+	bid, err := service.GetBidById(r.Context(), bidId)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorBidNotFound) {
@@ -354,7 +379,7 @@ func EditBidHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = service.CheckRWRightsByUsername(bid.TenderId, username)
+	err = service.CheckRWRightsByUsername(r.Context(), bid.TenderId, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorTenderNotFound) {
@@ -371,7 +396,7 @@ func EditBidHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	bid, err = service.EditBidById(
-		bidId, bidReq.Name, bidReq.Description,
+		r.Context(), bidId, bidReq.Name, bidReq.Description, bidReq.Amount,
 	)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
@@ -413,15 +438,26 @@ func SumbitDecisionHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var lotId uuid.NullUUID
+	if lotIdQ := r.URL.Query().Get("lotId"); len(lotIdQ) != 0 {
+		lotId.UUID, err = uuid.Parse(lotIdQ)
+		if err != nil {
+			err = errors.New("incorrect lot uuid")
+			apierror.HandleError(w, r, err, http.StatusBadRequest)
+			return
+		}
+		lotId.Valid = true
+	}
+
 	service, err := bidService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("bids service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	bid, err := service.GetBidById(bidId)
+	bid, err := service.GetBidById(r.Context(), bidId)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorBidNotFound) {
@@ -431,7 +467,7 @@ func SumbitDecisionHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = service.CheckRWRightsByUsername(bid.TenderId, username)
+	err = service.CheckRWRightsByUsername(r.Context(), bid.TenderId, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorTenderNotFound) {
@@ -447,12 +483,28 @@ func SumbitDecisionHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bid, err = service.SubmitDecisionById(bidId, decision)
+	bid, err = service.SubmitDecisionById(r.Context(), bidId, username, decision, lotId)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
-		if errors.Is(err, bidService.ErrorBidNotFound) {
+		if errors.Is(err, bidService.ErrorBidNotFound) ||
+			errors.Is(err, bidService.ErrorLotNotFound) {
 			httpStatus = http.StatusNotFound
 		}
+		if errors.Is(err, bidService.ErrorLotIsNotOpen) ||
+			errors.Is(err, bidService.ErrorBidIsNotCommercial) ||
+			errors.Is(err, bidService.ErrorIncorrectLots) {
+			httpStatus = http.StatusConflict
+		}
+		if errors.Is(err, bidService.ErrorUserNotFound) {
+			httpStatus = http.StatusUnauthorized
+		}
+		var conflictErr *bidService.ConflictOfInterestError
+		if errors.As(err, &conflictErr) {
+			httpStatus = http.StatusForbidden
+		}
+		if errors.Is(err, bidService.ErrorConflictCheckFailed) {
+			httpStatus = http.StatusInternalServerError
+		}
 		apierror.HandleError(w, r, err, httpStatus)
 		return
 	}
@@ -483,21 +535,37 @@ func FeedbackHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var rating *int
+	if ratingQ := r.URL.Query().Get("rating"); len(ratingQ) != 0 {
+		value, err := strconv.Atoi(ratingQ)
+		if err != nil {
+			err = errors.New("incorrect rating")
+			apierror.HandleError(w, r, err, http.StatusBadRequest)
+			return
+		}
+		rating = &value
+	}
+
+	tags := []model.ReviewTag{}
+	if tagsQ := r.URL.Query().Get("tags"); len(tagsQ) != 0 {
+		for _, tag := range strings.Split(tagsQ, ",") {
+			tags = append(tags, model.ReviewTag(strings.TrimSpace(tag)))
+		}
+	}
+
 	service, err := bidService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("bids service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	bid, err := service.CreateReviewById(bidId, feedback)
+	bid, err := service.CreateReviewById(
+		r.Context(), bidId, username, feedback, rating, tags,
+	)
 	if err != nil {
-		httpStatus := http.StatusBadRequest
-		if errors.Is(err, bidService.ErrorBidNotFound) {
-			httpStatus = http.StatusNotFound
-		}
-		apierror.HandleError(w, r, err, httpStatus)
+		handleReviewError(w, r, err)
 		return
 	}
 
@@ -529,13 +597,13 @@ func RollbackHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := bidService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("bids service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	bid, err := service.GetBidById(bidId)
+	bid, err := service.GetBidById(r.Context(), bidId)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorBidNotFound) {
@@ -545,7 +613,7 @@ func RollbackHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = service.CheckRWRightsByUsername(bid.TenderId, username)
+	err = service.CheckRWRightsByUsername(r.Context(), bid.TenderId, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorTenderNotFound) {
@@ -561,7 +629,7 @@ func RollbackHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bid, err = service.RollbackById(bidId, int32(version))
+	bid, err = service.RollbackById(r.Context(), bidId, int32(version))
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorBidNotFound) {
@@ -609,13 +677,13 @@ func GetReviewsHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := bidService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("bids service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	err = service.CheckRWRightsByUsername(tenderId, reqUsername)
+	err = service.CheckRWRightsByUsername(r.Context(), tenderId, reqUsername)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorTenderNotFound) {
@@ -631,7 +699,7 @@ func GetReviewsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bid, err := service.GetReviews(offset, limit, authorUsername, tenderId)
+	bid, err := service.GetReviews(r.Context(), offset, limit, authorUsername, tenderId)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, bidService.ErrorTenderNotFound) {
@@ -650,3 +718,66 @@ func GetReviewsHandler(w http.ResponseWriter, r *http.Request) {
 	res, _ := json.Marshal(bid)
 	w.Write(res)
 }
+
+func GetBidHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	bidId, err := uuid.Parse(chi.URLParam(r, "bidId"))
+	if err != nil {
+		err = errors.New("incorrect bid uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := bidService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("bids service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	bid, err := service.GetBidById(r.Context(), bidId)
+	if err != nil {
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, bidService.ErrorBidNotFound) {
+			httpStatus = http.StatusNotFound
+		}
+		apierror.HandleError(w, r, err, httpStatus)
+		return
+	}
+
+	err = service.CheckRWRightsByUsername(r.Context(), bid.TenderId, username)
+	if err != nil {
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, bidService.ErrorTenderNotFound) {
+			httpStatus = http.StatusNotFound
+		}
+		if errors.Is(err, bidService.ErrorUserNotFound) {
+			httpStatus = http.StatusUnauthorized
+		}
+		if errors.Is(err, bidService.ErrorUserIsNotOrgResponsible) {
+			httpStatus = http.StatusForbidden
+		}
+		apierror.HandleError(w, r, err, httpStatus)
+		return
+	}
+
+	bids, err := service.GetBidHistory(r.Context(), bidId)
+	if err != nil {
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, bidService.ErrorBidNotFound) {
+			httpStatus = http.StatusNotFound
+		}
+		apierror.HandleError(w, r, err, httpStatus)
+		return
+	}
+
+	res, _ := json.Marshal(bids)
+	w.Write(res)
+}
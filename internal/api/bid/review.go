@@ -0,0 +1,239 @@
+package bid
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"avi/internal/api/apierror"
+	"avi/internal/logging"
+	"avi/internal/model"
+	bidService "avi/internal/service/bid"
+)
+
+type EditReviewRequest struct {
+	Description string            `json:"description" validate:"max=1000"`
+	Rating      *int              `json:"rating"      validate:"omitempty,min=1,max=5"`
+	Tags        []model.ReviewTag `json:"tags"`
+}
+
+type ReplyRequest struct {
+	Text string `json:"text" validate:"required,max=1000"`
+}
+
+func EditReviewHandler(w http.ResponseWriter, r *http.Request) {
+	bidId, reviewId, username, ok := parseReviewParams(w, r)
+	if !ok {
+		return
+	}
+
+	reviewReq := EditReviewRequest{}
+	json.NewDecoder(r.Body).Decode(&reviewReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err := validate.Struct(reviewReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := bidService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("bids service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	review, err := service.EditReview(
+		r.Context(),
+		bidId,
+		reviewId,
+		username,
+		reviewReq.Description,
+		reviewReq.Rating,
+		reviewReq.Tags,
+	)
+	if err != nil {
+		handleReviewError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(review)
+	w.Write(res)
+}
+
+func DeleteReviewHandler(w http.ResponseWriter, r *http.Request) {
+	bidId, reviewId, username, ok := parseReviewParams(w, r)
+	if !ok {
+		return
+	}
+
+	service, err := bidService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("bids service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = service.DeleteReview(r.Context(), bidId, reviewId, username)
+	if err != nil {
+		handleReviewError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func ReplyToReviewHandler(w http.ResponseWriter, r *http.Request) {
+	bidId, reviewId, username, ok := parseReviewParams(w, r)
+	if !ok {
+		return
+	}
+
+	replyReq := ReplyRequest{}
+	json.NewDecoder(r.Body).Decode(&replyReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err := validate.Struct(replyReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := bidService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("bids service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	reply, err := service.ReplyToReview(
+		r.Context(), bidId, reviewId, username, replyReq.Text,
+	)
+	if err != nil {
+		handleReviewError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(reply)
+	w.Write(res)
+}
+
+func parseReviewParams(
+	w http.ResponseWriter, r *http.Request,
+) (bidId uuid.UUID, reviewId uuid.UUID, username string, ok bool) {
+	bidId, err := uuid.Parse(chi.URLParam(r, "bidId"))
+	if err != nil {
+		err = errors.New("incorrect bid uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	reviewId, err = uuid.Parse(chi.URLParam(r, "reviewId"))
+	if err != nil {
+		err = errors.New("incorrect review uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username = r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	ok = true
+	return
+}
+
+func handleReviewError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := http.StatusBadRequest
+	if errors.Is(err, bidService.ErrorBidNotFound) ||
+		errors.Is(err, bidService.ErrorTenderNotFound) ||
+		errors.Is(err, bidService.ErrorReviewNotFound) {
+		httpStatus = http.StatusNotFound
+	}
+	if errors.Is(err, bidService.ErrorUserNotFound) {
+		httpStatus = http.StatusUnauthorized
+	}
+	var conflictErr *bidService.ConflictOfInterestError
+	if errors.Is(err, bidService.ErrorUserIsNotOrgResponsible) ||
+		errors.Is(err, bidService.ErrorUserIsNotReviewer) ||
+		errors.Is(err, bidService.ErrorUserIsNotBidAuthor) ||
+		errors.As(err, &conflictErr) {
+		httpStatus = http.StatusForbidden
+	}
+	if errors.Is(err, bidService.ErrorConflictCheckFailed) {
+		httpStatus = http.StatusInternalServerError
+	}
+	apierror.HandleError(w, r, err, httpStatus)
+}
+
+func GetReputationHandler(w http.ResponseWriter, r *http.Request) {
+	authorId, err := uuid.Parse(chi.URLParam(r, "authorId"))
+	if err != nil {
+		err = errors.New("incorrect author uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	authorType := model.BidAuthorType(r.URL.Query().Get("authorType"))
+	if authorType != model.UserBidAuthorType &&
+		authorType != model.OrgBidAuthorType {
+		err := errors.New("incorrect authorType param")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	limit := 5
+	if limitQ := r.URL.Query().Get("reviewsLimit"); len(limitQ) != 0 {
+		limit, err = strconv.Atoi(limitQ)
+		if err != nil || limit < 0 || limit > 50 {
+			err = errors.New("incorrect reviewsLimit param")
+			apierror.HandleError(w, r, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	service, err := bidService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("bids service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	reputation, err := service.GetReputation(r.Context(), authorType, authorId, username, limit)
+	if err != nil {
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, bidService.ErrorAuthorNotFound) {
+			httpStatus = http.StatusNotFound
+		}
+		if errors.Is(err, bidService.ErrorUserNotFound) {
+			httpStatus = http.StatusUnauthorized
+		}
+		apierror.HandleError(w, r, err, httpStatus)
+		return
+	}
+
+	res, _ := json.Marshal(reputation)
+	w.Write(res)
+}
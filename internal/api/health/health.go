@@ -0,0 +1,112 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"avi/internal/buildinfo"
+	"avi/internal/schema"
+	"avi/internal/server"
+)
+
+const checkTimeout = 2 * time.Second
+
+type State interface {
+	ShuttingDown() bool
+	Workers() []server.WorkerStatus
+}
+
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type Checker struct {
+	db    *sql.DB
+	state State
+}
+
+func NewChecker(db *sql.DB, state State) *Checker {
+	return &Checker{db: db, state: state}
+}
+
+func Register(r chi.Router, checker *Checker) {
+	r.Get("/healthz", LivenessHandler)
+	r.Get("/readyz", checker.ReadinessHandler)
+	r.Get("/version", VersionHandler)
+}
+
+func LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	res, _ := json.Marshal(Report{Status: "ok"})
+	w.Write(res)
+}
+
+func VersionHandler(w http.ResponseWriter, r *http.Request) {
+	res, _ := json.Marshal(buildinfo.Get())
+	w.Write(res)
+}
+
+func (c *Checker) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	report, ready := c.Ready(r.Context())
+	res, _ := json.Marshal(report)
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(res)
+}
+
+func (c *Checker) Ready(ctx context.Context) (report Report, ready bool) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report = Report{Status: "ok", Checks: map[string]string{}}
+	ready = true
+	fail := func(check string, reason string) {
+		report.Checks[check] = reason
+		report.Status = "unavailable"
+		ready = false
+	}
+
+	if c.state.ShuttingDown() {
+		fail("shutdown", "server is shutting down")
+	} else {
+		report.Checks["shutdown"] = "ok"
+	}
+
+	err := c.db.PingContext(ctx)
+	if err != nil {
+		fail("database", err.Error())
+		fail("migrations", "database is unavailable")
+	} else {
+		report.Checks["database"] = "ok"
+
+		pending, err := schema.Pending(ctx, c.db)
+		switch {
+		case err != nil:
+			fail("migrations", err.Error())
+		case len(pending) > 0:
+			fail("migrations", "pending tables: "+strings.Join(pending, ", "))
+		default:
+			report.Checks["migrations"] = "ok"
+		}
+	}
+
+	var stopped []string
+	for _, worker := range c.state.Workers() {
+		if !worker.Running {
+			stopped = append(stopped, worker.Name+": "+worker.Err.Error())
+		}
+	}
+	if len(stopped) > 0 {
+		fail("workers", strings.Join(stopped, "; "))
+	} else {
+		report.Checks["workers"] = "ok"
+	}
+	return
+}
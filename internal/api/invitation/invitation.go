@@ -0,0 +1,155 @@
+package invitation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"avi/internal/api/apierror"
+	"avi/internal/logging"
+	"avi/internal/model"
+	invitationService "avi/internal/service/invitation"
+)
+
+type InvitationRequest struct {
+	InviteeType model.InviteeType `json:"inviteeType" validate:"required,oneof=User Organization"`
+	InviteeId   uuid.UUID         `json:"inviteeId"   validate:"required"`
+}
+
+func InviteHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	invitationReq := InvitationRequest{}
+	json.NewDecoder(r.Body).Decode(&invitationReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(invitationReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := invitationService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("invitations service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	invitation, err := service.Invite(
+		r.Context(), tenderId, username, invitationReq.InviteeType, invitationReq.InviteeId,
+	)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(invitation)
+	w.Write(res)
+}
+
+func GetInvitationsHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := invitationService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("invitations service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	invitations, err := service.GetInvitations(r.Context(), tenderId, username)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(invitations)
+	w.Write(res)
+}
+
+func RevokeInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	invitationId, err := uuid.Parse(chi.URLParam(r, "invitationId"))
+	if err != nil {
+		err = errors.New("incorrect invitation uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := invitationService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("invitations service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = service.Revoke(r.Context(), tenderId, invitationId, username)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := http.StatusBadRequest
+	if errors.Is(err, invitationService.ErrorTenderNotFound) ||
+		errors.Is(err, invitationService.ErrorInviteeNotFound) ||
+		errors.Is(err, invitationService.ErrorInvitationNotFound) {
+		httpStatus = http.StatusNotFound
+	}
+	if errors.Is(err, invitationService.ErrorUserNotFound) {
+		httpStatus = http.StatusUnauthorized
+	}
+	if errors.Is(err, invitationService.ErrorUserIsNotOrgResponsible) {
+		httpStatus = http.StatusForbidden
+	}
+	apierror.HandleError(w, r, err, httpStatus)
+}
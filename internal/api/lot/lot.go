@@ -0,0 +1,205 @@
+package lot
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"avi/internal/api/apierror"
+	"avi/internal/logging"
+	lotService "avi/internal/service/lot"
+	tenderService "avi/internal/service/tender"
+)
+
+type LotRequest struct {
+	Name        string   `json:"name"        validate:"required,max=100"`
+	Description string   `json:"description" validate:"required,max=500"`
+	Quantity    int      `json:"quantity"    validate:"required,min=1"`
+	Budget      *float64 `json:"budget"      validate:"omitempty,gt=0"`
+}
+
+func CreateLotHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	lotReq := LotRequest{}
+	json.NewDecoder(r.Body).Decode(&lotReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(lotReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkTenderRight(w, r, tenderId, username, true) {
+		return
+	}
+
+	service, err := lotService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("lots service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	lot, err := service.CreateLot(
+		r.Context(),
+		tenderId,
+		lotReq.Name,
+		lotReq.Description,
+		lotReq.Quantity,
+		lotReq.Budget,
+	)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(lot)
+	w.Write(res)
+}
+
+func GetLotsHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if !checkTenderRight(w, r, tenderId, username, false) {
+		return
+	}
+
+	service, err := lotService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("lots service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	lots, err := service.GetLots(r.Context(), tenderId)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(lots)
+	w.Write(res)
+}
+
+func CancelLotHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	lotId, err := uuid.Parse(chi.URLParam(r, "lotId"))
+	if err != nil {
+		err = errors.New("incorrect lot uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if !checkTenderRight(w, r, tenderId, username, true) {
+		return
+	}
+
+	service, err := lotService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("lots service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	lot, err := service.CancelLot(r.Context(), tenderId, lotId)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(lot)
+	w.Write(res)
+}
+
+func checkTenderRight(
+	w http.ResponseWriter,
+	r *http.Request,
+	tenderId uuid.UUID,
+	username string,
+	write bool,
+) bool {
+	service, err := tenderService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("tenders service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return false
+	}
+
+	if write {
+		err = service.CheckWriteRightByUsername(r.Context(), tenderId, username)
+	} else {
+		err = service.CheckReadRightByUsername(r.Context(), tenderId, username)
+	}
+	if err != nil {
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, tenderService.ErrorTenderNorFound) {
+			httpStatus = http.StatusNotFound
+		}
+		if errors.Is(err, tenderService.ErrorUserNorFound) {
+			httpStatus = http.StatusUnauthorized
+		}
+		if errors.Is(err, tenderService.ErrorUserIsNotOrgResponsible) ||
+			errors.Is(err, tenderService.ErrorUserIsNotInvited) {
+			httpStatus = http.StatusForbidden
+		}
+		apierror.HandleError(w, r, err, httpStatus)
+		return false
+	}
+	return true
+}
+
+func handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := http.StatusBadRequest
+	if errors.Is(err, lotService.ErrorTenderNotFound) ||
+		errors.Is(err, lotService.ErrorLotNotFound) {
+		httpStatus = http.StatusNotFound
+	}
+	if errors.Is(err, lotService.ErrorTenderIsClosed) ||
+		errors.Is(err, lotService.ErrorTenderIsNotCreated) ||
+		errors.Is(err, lotService.ErrorLotIsNotOpen) {
+		httpStatus = http.StatusConflict
+	}
+	apierror.HandleError(w, r, err, httpStatus)
+}
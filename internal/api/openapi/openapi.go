@@ -0,0 +1,206 @@
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+
+	"avi/internal/api/apierror"
+	"avi/internal/logging"
+)
+
+var ErrorRouteNotDocumented = errors.New("route is not documented in api specification")
+
+//go:embed openapi.yaml
+var specData []byte
+
+//go:embed swagger.html
+var swaggerPage []byte
+
+var (
+	loadOnce sync.Once
+	spec     *openapi3.T
+	specJSON []byte
+	router   routers.Router
+	loadErr  error
+)
+
+func load() error {
+	loadOnce.Do(func() {
+		openapi3.DefineStringFormatCallback("uuid", func(value string) error {
+			_, err := uuid.Parse(value)
+			return err
+		})
+
+		loader := openapi3.NewLoader()
+		spec, loadErr = loader.LoadFromData(specData)
+		if loadErr != nil {
+			return
+		}
+		loadErr = spec.Validate(context.Background())
+		if loadErr != nil {
+			return
+		}
+		specJSON, loadErr = json.Marshal(spec)
+		if loadErr != nil {
+			return
+		}
+		router, loadErr = gorillamux.NewRouter(spec)
+	})
+	return loadErr
+}
+
+func Spec() (*openapi3.T, error) {
+	err := load()
+	return spec, err
+}
+
+func SpecHandler(w http.ResponseWriter, r *http.Request) {
+	err := load()
+	if err != nil {
+		apierror.HandleError(w, r, errors.New("api specification is unavailable"), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(specJSON)
+}
+
+func SwaggerUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(swaggerPage)
+}
+
+func ValidateRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := load()
+		if err != nil {
+			apierror.HandleError(w, r, errors.New("api specification is unavailable"), http.StatusInternalServerError)
+			return
+		}
+
+		routeReq := r
+		if path := r.URL.Path; len(path) > 1 && strings.HasSuffix(path, "/") {
+			routeReq = r.Clone(r.Context())
+			routeReq.URL.Path = strings.TrimSuffix(path, "/")
+		}
+		route, pathParams, err := router.FindRoute(routeReq)
+		if err != nil {
+			status := http.StatusNotFound
+			if errors.Is(err, routers.ErrMethodNotAllowed) {
+				status = http.StatusMethodNotAllowed
+			}
+			logging.FromContext(r.Context()).Warn(
+				"request to route missing from api specification",
+				"method", r.Method,
+				"path", r.URL.Path,
+			)
+			apierror.HandleError(w, r, ErrorRouteNotDocumented, status)
+			return
+		}
+
+		contentType := r.Header.Get("Content-Type")
+		if body := route.Operation.RequestBody; body != nil &&
+			body.Value.Content.Get("application/json") != nil &&
+			!strings.HasPrefix(contentType, "application/json") {
+			r.Header.Set("Content-Type", "application/json")
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				ExcludeRequestBody: strings.HasPrefix(contentType, "multipart/"),
+			},
+		}
+		err = openapi3filter.ValidateRequest(r.Context(), input)
+		if err != nil {
+			apierror.HandleError(w, r, validationError(err), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func validationError(err error) error {
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) {
+		if reqErr.Parameter != nil {
+			return fmt.Errorf("incorrect %s param", reqErr.Parameter.Name)
+		}
+		if reqErr.RequestBody != nil {
+			return errors.New("incorrect request body")
+		}
+	}
+	return errors.New("request does not match api specification")
+}
+
+func CheckRoutes(routes chi.Routes) error {
+	err := load()
+	if err != nil {
+		return err
+	}
+
+	documented := map[string]bool{}
+	for path, item := range spec.Paths.Map() {
+		for method := range item.Operations() {
+			documented[method+" "+path] = true
+		}
+	}
+
+	registered := map[string]bool{}
+	err = chi.Walk(routes, func(
+		method string,
+		route string,
+		handler http.Handler,
+		middlewares ...func(http.Handler) http.Handler,
+	) error {
+		if !strings.HasPrefix(route, "/api") {
+			return nil
+		}
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		registered[method+" "+route] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var missing, stale []string
+	for route := range registered {
+		if !documented[route] {
+			missing = append(missing, route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			stale = append(stale, route)
+		}
+	}
+	if len(missing) == 0 && len(stale) == 0 {
+		return nil
+	}
+	slices.Sort(missing)
+	slices.Sort(stale)
+	return fmt.Errorf(
+		"api specification is out of sync with router: undocumented routes [%s], unknown documented routes [%s]",
+		strings.Join(missing, ", "),
+		strings.Join(stale, ", "),
+	)
+}
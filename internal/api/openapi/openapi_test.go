@@ -0,0 +1,70 @@
+package openapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+
+	"avi/internal/api/openapi"
+	"avi/internal/api/router"
+)
+
+func TestRouterMatchesSpec(t *testing.T) {
+	err := openapi.CheckRoutes(router.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckRoutesReportsUndocumentedRoute(t *testing.T) {
+	r := router.New()
+	r.Get("/api/undocumented", func(w http.ResponseWriter, r *http.Request) {})
+
+	err := openapi.CheckRoutes(r)
+	if err == nil {
+		t.Fatal("expected undocumented route to be reported")
+	}
+}
+
+func TestValidateRequestRejectsUndocumentedRoute(t *testing.T) {
+	called := false
+	r := chi.NewRouter()
+	r.Use(openapi.ValidateRequest)
+	r.Get("/api/undocumented", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	r.Post("/api/ping", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/api/undocumented", http.StatusNotFound},
+		{http.MethodPost, "/api/ping", http.StatusMethodNotAllowed},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(test.method, test.path, nil))
+		if w.Code != test.status {
+			t.Errorf("%s %s: status %d, want %d", test.method, test.path, w.Code, test.status)
+		}
+	}
+	if called {
+		t.Error("handler of undocumented route was called")
+	}
+}
+
+func TestValidateRequestChecksParameters(t *testing.T) {
+	r := router.New()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tenders/not-a-uuid/status", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
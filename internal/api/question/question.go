@@ -0,0 +1,190 @@
+package question
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"avi/internal/api/apierror"
+	"avi/internal/logging"
+	"avi/internal/model"
+	questionService "avi/internal/service/question"
+)
+
+type QuestionRequest struct {
+	Text string `json:"text" validate:"required,max=1000"`
+}
+
+type AnswerRequest struct {
+	Text       string                 `json:"text"       validate:"required,max=2000"`
+	Visibility model.AnswerVisibility `json:"visibility" validate:"required,oneof=Public Private"`
+}
+
+func AskQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	questionReq := QuestionRequest{}
+	json.NewDecoder(r.Body).Decode(&questionReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(questionReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := questionService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("questions service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	question, err := service.AskQuestion(r.Context(), tenderId, username, questionReq.Text)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(question)
+	w.Write(res)
+}
+
+func GetQuestionsHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	var offset int
+	if offsetQ := r.URL.Query().Get("offset"); len(offsetQ) != 0 {
+		offset, _ = strconv.Atoi(offsetQ)
+	}
+
+	var limit int
+	if limitQ := r.URL.Query().Get("limit"); len(limitQ) != 0 {
+		limit, _ = strconv.Atoi(limitQ)
+	}
+
+	service, err := questionService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("questions service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	questions, err := service.GetQuestions(r.Context(), offset, limit, tenderId, username)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+	if questions == nil {
+		questions = []*model.Question{}
+	}
+
+	res, _ := json.Marshal(questions)
+	w.Write(res)
+}
+
+func AnswerQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	questionId, err := uuid.Parse(chi.URLParam(r, "questionId"))
+	if err != nil {
+		err = errors.New("incorrect question uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	answerReq := AnswerRequest{}
+	json.NewDecoder(r.Body).Decode(&answerReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(answerReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := questionService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("questions service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	question, err := service.AnswerQuestion(
+		r.Context(), tenderId, questionId, username, answerReq.Text, answerReq.Visibility,
+	)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(question)
+	w.Write(res)
+}
+
+func handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := http.StatusBadRequest
+	if errors.Is(err, questionService.ErrorTenderNotFound) ||
+		errors.Is(err, questionService.ErrorQuestionNotFound) {
+		httpStatus = http.StatusNotFound
+	}
+	if errors.Is(err, questionService.ErrorUserNotFound) {
+		httpStatus = http.StatusUnauthorized
+	}
+	if errors.Is(err, questionService.ErrorUserIsNotOrgResponsible) ||
+		errors.Is(err, questionService.ErrorUserIsOrgResponsible) ||
+		errors.Is(err, questionService.ErrorUserIsNotInvited) {
+		httpStatus = http.StatusForbidden
+	}
+	if errors.Is(err, questionService.ErrorTenderIsNotPublished) ||
+		errors.Is(err, questionService.ErrorQuestionIsNotOpen) {
+		httpStatus = http.StatusConflict
+	}
+	apierror.HandleError(w, r, err, httpStatus)
+}
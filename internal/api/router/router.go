@@ -0,0 +1,114 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"avi/internal/api/attachment"
+	"avi/internal/api/bid"
+	"avi/internal/api/invitation"
+	"avi/internal/api/lot"
+	"avi/internal/api/openapi"
+	"avi/internal/api/question"
+	"avi/internal/api/servicetype"
+	"avi/internal/api/shortlist"
+	"avi/internal/api/template"
+	"avi/internal/api/tender"
+	"avi/internal/idempotency"
+	"avi/internal/logging"
+	"avi/internal/metrics"
+	"avi/internal/ratelimit"
+	"avi/internal/tracing"
+)
+
+func New() chi.Router {
+	r := chi.NewRouter()
+	r.Use(tracing.Middleware)
+	r.Use(logging.Middleware)
+	r.Use(metrics.Middleware)
+	r.Route("/api", func(r chi.Router) {
+		r.Use(openapi.ValidateRequest)
+		r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
+			res, _ := json.Marshal("ok")
+			w.Write(res)
+		})
+		r.Get("/openapi.json", openapi.SpecHandler)
+		r.Get("/docs", openapi.SwaggerUIHandler)
+		r.Route("/tenders", func(r chi.Router) {
+			limited := r.With(ratelimit.Middleware)
+			idempotent := limited.With(idempotency.Middleware)
+			r.Get("/", tender.GetTendersHandler)
+			idempotent.Post("/new", tender.CreateTenderHandler)
+			r.Get("/my", tender.GetMyTendersHandler)
+			limited.Patch("/{tenderId}/edit", tender.EditTenderHandler)
+			r.Get("/{tenderId}/status", tender.GetTenderStatusHandler)
+			limited.Put("/{tenderId}/status", tender.UpdateTenderStatusHandler)
+			limited.Put("/{tenderId}/rollback/{version}", tender.RollbackTenderHandler)
+			r.Get("/{tenderId}/history", tender.GetTenderHistoryHandler)
+			idempotent.Post("/{tenderId}/clone", tender.CloneTenderHandler)
+			idempotent.Post("/{tenderId}/attachments", attachment.UploadTenderAttachmentHandler)
+			r.Get("/{tenderId}/attachments", attachment.GetTenderAttachmentsHandler)
+			r.Get("/{tenderId}/attachments/{attachmentId}", attachment.DownloadTenderAttachmentHandler)
+			limited.Delete("/{tenderId}/attachments/{attachmentId}", attachment.DeleteTenderAttachmentHandler)
+			idempotent.Post("/{tenderId}/questions", question.AskQuestionHandler)
+			r.Get("/{tenderId}/questions", question.GetQuestionsHandler)
+			limited.Put("/{tenderId}/questions/{questionId}/answer", question.AnswerQuestionHandler)
+			idempotent.Post("/{tenderId}/invitations", invitation.InviteHandler)
+			r.Get("/{tenderId}/invitations", invitation.GetInvitationsHandler)
+			limited.Delete("/{tenderId}/invitations/{invitationId}", invitation.RevokeInvitationHandler)
+			idempotent.Post("/{tenderId}/lots", lot.CreateLotHandler)
+			r.Get("/{tenderId}/lots", lot.GetLotsHandler)
+			limited.Put("/{tenderId}/lots/{lotId}/cancel", lot.CancelLotHandler)
+			idempotent.Post("/{tenderId}/shortlist", shortlist.ShortlistHandler)
+			r.Get("/{tenderId}/shortlist", shortlist.GetShortlistHandler)
+			limited.Delete("/{tenderId}/shortlist/{entryId}", shortlist.RemoveFromShortlistHandler)
+		})
+		r.Route("/service_types", func(r chi.Router) {
+			limited := r.With(ratelimit.Middleware)
+			idempotent := limited.With(idempotency.Middleware)
+			r.Get("/", servicetype.GetServiceTypesHandler)
+			idempotent.Post("/new", servicetype.CreateServiceTypeHandler)
+			limited.Patch("/{code}/edit", servicetype.EditServiceTypeHandler)
+			limited.Delete("/{code}", servicetype.DeleteServiceTypeHandler)
+		})
+		r.Route("/templates", func(r chi.Router) {
+			limited := r.With(ratelimit.Middleware)
+			idempotent := limited.With(idempotency.Middleware)
+			r.Get("/", template.GetTemplatesHandler)
+			idempotent.Post("/new", template.CreateTemplateHandler)
+			limited.Patch("/{templateId}/edit", template.EditTemplateHandler)
+			limited.Delete("/{templateId}", template.DeleteTemplateHandler)
+			idempotent.Post("/{templateId}/tenders", template.CreateTenderHandler)
+			idempotent.Post("/{templateId}/attachments", attachment.UploadTemplateAttachmentHandler)
+			r.Get("/{templateId}/attachments", attachment.GetTemplateAttachmentsHandler)
+			r.Get("/{templateId}/attachments/{attachmentId}", attachment.DownloadTemplateAttachmentHandler)
+			limited.Delete("/{templateId}/attachments/{attachmentId}", attachment.DeleteTemplateAttachmentHandler)
+		})
+		r.Route("/bids", func(r chi.Router) {
+			limited := r.With(ratelimit.Middleware)
+			idempotent := limited.With(idempotency.Middleware)
+			idempotent.Post("/new", bid.CreateBidHandler)
+			r.Get("/my", bid.GetMyBidsHandler)
+			r.Get("/reputation/{authorId}", bid.GetReputationHandler)
+			r.Get("/{tenderId}/list", bid.GetBidsHandler)
+			r.Get("/{bidId}/status", bid.GetBidStatusHandler)
+			limited.Put("/{bidId}/status", bid.UpdateBidStatusHandler)
+			limited.Patch("/{bidId}/edit", bid.EditBidHandler)
+			idempotent.Put("/{bidId}/submit_decision", bid.SumbitDecisionHandler)
+			limited.Put("/{bidId}/feedback", bid.FeedbackHandler)
+			limited.Put("/{bidId}/rollback/{version}", bid.RollbackHandler)
+			r.Get("/{bidId}/history", bid.GetBidHistoryHandler)
+			idempotent.Post("/{bidId}/attachments", attachment.UploadBidAttachmentHandler)
+			r.Get("/{bidId}/attachments", attachment.GetBidAttachmentsHandler)
+			r.Get("/{bidId}/attachments/{attachmentId}", attachment.DownloadBidAttachmentHandler)
+			limited.Delete("/{bidId}/attachments/{attachmentId}", attachment.DeleteBidAttachmentHandler)
+			r.Get("/{tenderId}/reviews", bid.GetReviewsHandler)
+			limited.Patch("/{bidId}/reviews/{reviewId}", bid.EditReviewHandler)
+			limited.Delete("/{bidId}/reviews/{reviewId}", bid.DeleteReviewHandler)
+			idempotent.Post("/{bidId}/reviews/{reviewId}/replies", bid.ReplyToReviewHandler)
+		})
+	})
+	return r
+}
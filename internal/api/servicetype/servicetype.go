@@ -0,0 +1,186 @@
+package servicetype
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+
+	"avi/internal/api/apierror"
+	"avi/internal/logging"
+	"avi/internal/model"
+	serviceTypeService "avi/internal/service/servicetype"
+)
+
+type ServiceTypeRequest struct {
+	Code       model.TenderServiceType  `json:"code"       validate:"required,max=50"`
+	Name       string                   `json:"name"       validate:"required,max=200"`
+	ParentCode *model.TenderServiceType `json:"parentCode" validate:"omitempty,max=50"`
+}
+
+type EditServiceTypeRequest struct {
+	Name       string                   `json:"name"       validate:"max=200"`
+	ParentCode *model.TenderServiceType `json:"parentCode" validate:"omitempty,max=50"`
+}
+
+func GetServiceTypesHandler(w http.ResponseWriter, r *http.Request) {
+	parentCode := model.TenderServiceType(r.URL.Query().Get("parentCode"))
+
+	service, err := serviceTypeService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("service types service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	serviceTypes, err := service.GetServiceTypes(r.Context(), parentCode)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(serviceTypes)
+	w.Write(res)
+}
+
+func CreateServiceTypeHandler(w http.ResponseWriter, r *http.Request) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	serviceTypeReq := ServiceTypeRequest{}
+	json.NewDecoder(r.Body).Decode(&serviceTypeReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err := validate.Struct(serviceTypeReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := serviceTypeService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("service types service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	serviceType, err := service.CreateServiceType(
+		r.Context(),
+		username,
+		serviceTypeReq.Code,
+		serviceTypeReq.Name,
+		serviceTypeReq.ParentCode,
+	)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(serviceType)
+	w.Write(res)
+}
+
+func EditServiceTypeHandler(w http.ResponseWriter, r *http.Request) {
+	code := model.TenderServiceType(chi.URLParam(r, "code"))
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	editServiceTypeReq := EditServiceTypeRequest{}
+	json.NewDecoder(r.Body).Decode(&editServiceTypeReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err := validate.Struct(editServiceTypeReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if editServiceTypeReq.Name == "" && editServiceTypeReq.ParentCode == nil {
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := serviceTypeService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("service types service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	serviceType, err := service.UpdateServiceType(
+		r.Context(),
+		username,
+		code,
+		editServiceTypeReq.Name,
+		editServiceTypeReq.ParentCode,
+	)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(serviceType)
+	w.Write(res)
+}
+
+func DeleteServiceTypeHandler(w http.ResponseWriter, r *http.Request) {
+	code := model.TenderServiceType(chi.URLParam(r, "code"))
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := serviceTypeService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("service types service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = service.DeleteServiceType(r.Context(), username, code)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := http.StatusBadRequest
+	if errors.Is(err, serviceTypeService.ErrorServiceTypeNotFound) {
+		httpStatus = http.StatusNotFound
+	}
+	if errors.Is(err, serviceTypeService.ErrorUserNotFound) {
+		httpStatus = http.StatusUnauthorized
+	}
+	if errors.Is(err, serviceTypeService.ErrorUserIsNotAdmin) {
+		httpStatus = http.StatusForbidden
+	}
+	if errors.Is(err, serviceTypeService.ErrorServiceTypeExists) ||
+		errors.Is(err, serviceTypeService.ErrorServiceTypeIsUsed) {
+		httpStatus = http.StatusConflict
+	}
+	apierror.HandleError(w, r, err, httpStatus)
+}
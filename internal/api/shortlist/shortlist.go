@@ -0,0 +1,155 @@
+package shortlist
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"avi/internal/api/apierror"
+	"avi/internal/logging"
+	shortlistService "avi/internal/service/shortlist"
+)
+
+type ShortlistRequest struct {
+	BidId uuid.UUID `json:"bidId" validate:"required"`
+}
+
+func ShortlistHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	shortlistReq := ShortlistRequest{}
+	json.NewDecoder(r.Body).Decode(&shortlistReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(shortlistReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := shortlistService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("shortlist service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	entry, err := service.Shortlist(r.Context(), tenderId, username, shortlistReq.BidId)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(entry)
+	w.Write(res)
+}
+
+func GetShortlistHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := shortlistService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("shortlist service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	entries, err := service.GetShortlist(r.Context(), tenderId, username)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(entries)
+	w.Write(res)
+}
+
+func RemoveFromShortlistHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	entryId, err := uuid.Parse(chi.URLParam(r, "entryId"))
+	if err != nil {
+		err = errors.New("incorrect shortlist entry uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := shortlistService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("shortlist service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = service.Remove(r.Context(), tenderId, entryId, username)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := http.StatusBadRequest
+	if errors.Is(err, shortlistService.ErrorTenderNotFound) ||
+		errors.Is(err, shortlistService.ErrorBidNotFound) ||
+		errors.Is(err, shortlistService.ErrorEntryNotFound) {
+		httpStatus = http.StatusNotFound
+	}
+	if errors.Is(err, shortlistService.ErrorUserNotFound) {
+		httpStatus = http.StatusUnauthorized
+	}
+	if errors.Is(err, shortlistService.ErrorUserIsNotOrgResponsible) {
+		httpStatus = http.StatusForbidden
+	}
+	if errors.Is(err, shortlistService.ErrorTenderIsNotPrequalification) ||
+		errors.Is(err, shortlistService.ErrorBidIsNotPrequalification) {
+		httpStatus = http.StatusConflict
+	}
+	apierror.HandleError(w, r, err, httpStatus)
+}
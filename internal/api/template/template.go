@@ -0,0 +1,266 @@
+package template
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"avi/internal/api/apierror"
+	"avi/internal/logging"
+	"avi/internal/model"
+	templateService "avi/internal/service/template"
+)
+
+type TemplateRequest struct {
+	Name           string                  `json:"name"           validate:"required,max=100"`
+	Description    string                  `json:"description"    validate:"required,max=500"`
+	ServiceType    model.TenderServiceType `json:"serviceType"    validate:"required,max=50"`
+	OrganizationId uuid.UUID               `json:"organizationId" validate:"required"`
+	Criteria       []string                `json:"criteria"       validate:"max=50,dive,required,max=200"`
+}
+
+type EditTemplateRequest struct {
+	Name        string                  `json:"name"        validate:"max=100"`
+	Description string                  `json:"description" validate:"max=500"`
+	ServiceType model.TenderServiceType `json:"serviceType" validate:"max=50"`
+	Criteria    []string                `json:"criteria"    validate:"omitempty,max=50,dive,required,max=200"`
+}
+
+type TemplateTenderRequest struct {
+	Name        string `json:"name"        validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	InviteOnly  bool   `json:"inviteOnly"`
+}
+
+func CreateTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	templateReq := TemplateRequest{}
+	json.NewDecoder(r.Body).Decode(&templateReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err := validate.Struct(templateReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := templateService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("templates service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	template, err := service.CreateTemplate(
+		r.Context(),
+		username,
+		templateReq.OrganizationId,
+		templateReq.Name,
+		templateReq.Description,
+		templateReq.ServiceType,
+		templateReq.Criteria,
+	)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(template)
+	w.Write(res)
+}
+
+func GetTemplatesHandler(w http.ResponseWriter, r *http.Request) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := templateService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("templates service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	templates, err := service.GetTemplates(r.Context(), username)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(templates)
+	w.Write(res)
+}
+
+func EditTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	templateId, err := uuid.Parse(chi.URLParam(r, "templateId"))
+	if err != nil {
+		err = errors.New("incorrect template uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	editTemplateReq := EditTemplateRequest{}
+	json.NewDecoder(r.Body).Decode(&editTemplateReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(editTemplateReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if editTemplateReq.Name == "" &&
+		editTemplateReq.Description == "" &&
+		editTemplateReq.ServiceType == "" &&
+		editTemplateReq.Criteria == nil {
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := templateService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("templates service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	template, err := service.UpdateTemplate(
+		r.Context(),
+		templateId,
+		username,
+		editTemplateReq.Name,
+		editTemplateReq.Description,
+		editTemplateReq.ServiceType,
+		editTemplateReq.Criteria,
+	)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(template)
+	w.Write(res)
+}
+
+func DeleteTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	templateId, err := uuid.Parse(chi.URLParam(r, "templateId"))
+	if err != nil {
+		err = errors.New("incorrect template uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := templateService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("templates service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = service.DeleteTemplate(r.Context(), templateId, username)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func CreateTenderHandler(w http.ResponseWriter, r *http.Request) {
+	templateId, err := uuid.Parse(chi.URLParam(r, "templateId"))
+	if err != nil {
+		err = errors.New("incorrect template uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	tenderReq := TemplateTenderRequest{}
+	json.NewDecoder(r.Body).Decode(&tenderReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(tenderReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := templateService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("templates service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	tender, err := service.CreateTender(
+		r.Context(),
+		templateId,
+		username,
+		tenderReq.Name,
+		tenderReq.Description,
+		tenderReq.InviteOnly,
+	)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	res, _ := json.Marshal(tender)
+	w.Write(res)
+}
+
+func handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := http.StatusBadRequest
+	if errors.Is(err, templateService.ErrorTemplateNotFound) {
+		httpStatus = http.StatusNotFound
+	}
+	if errors.Is(err, templateService.ErrorUserNotFound) {
+		httpStatus = http.StatusUnauthorized
+	}
+	if errors.Is(err, templateService.ErrorUserIsNotOrgResponsible) {
+		httpStatus = http.StatusForbidden
+	}
+	apierror.HandleError(w, r, err, httpStatus)
+}
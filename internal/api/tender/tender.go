@@ -3,7 +3,6 @@ package tender
 import (
 	"encoding/json"
 	"errors"
-	"log/slog"
 	"net/http"
 	"strconv"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/google/uuid"
 
 	"avi/internal/api/apierror"
+	"avi/internal/logging"
 	"avi/internal/model"
 	tenderService "avi/internal/service/tender"
 )
@@ -19,15 +19,31 @@ import (
 type TenderRequest struct {
 	Name            string                  `json:"name"            validate:"required,max=100"`
 	Description     string                  `json:"description"     validate:"required,max=500"`
-	ServiceType     model.TenderServiceType `json:"serviceType"     validate:"required,oneof=Construction Delivery Manufacture"`
+	ServiceType     model.TenderServiceType `json:"serviceType"     validate:"required,max=50"`
 	OrganizationId  uuid.UUID               `json:"organizationId"  validate:"required,max=100"`
 	CreatorUsername string                  `json:"creatorUsername" validate:"required"`
+	InviteOnly      bool                    `json:"inviteOnly"`
+	Criteria        []string                `json:"criteria"        validate:"max=50,dive,required,max=200"`
+	Budget          *float64                `json:"budget"          validate:"omitempty,gt=0"`
+	Reserve         *float64                `json:"reserve"         validate:"omitempty,gt=0"`
+	Currency        string                  `json:"currency"        validate:"omitempty,iso4217"`
+	StrictBudget    bool                    `json:"strictBudget"`
 }
 
 type EditTenderRequest struct {
-	Name        string                  `json:"name"        validate:"max=100"`
-	Description string                  `json:"description" validate:"max=500"`
-	ServiceType model.TenderServiceType `json:"serviceType" validate:"oneof=Construction Delivery Manufacture ''"`
+	Name         string                  `json:"name"         validate:"max=100"`
+	Description  string                  `json:"description"  validate:"max=500"`
+	ServiceType  model.TenderServiceType `json:"serviceType"  validate:"max=50"`
+	InviteOnly   *bool                   `json:"inviteOnly"`
+	Criteria     []string                `json:"criteria"     validate:"omitempty,max=50,dive,required,max=200"`
+	Budget       *float64                `json:"budget"       validate:"omitempty,gt=0"`
+	Reserve      *float64                `json:"reserve"      validate:"omitempty,gt=0"`
+	Currency     string                  `json:"currency"     validate:"omitempty,iso4217"`
+	StrictBudget *bool                   `json:"strictBudget"`
+}
+
+type CloneTenderRequest struct {
+	Name string `json:"name" validate:"max=100"`
 }
 
 func CreateTenderHandler(w http.ResponseWriter, r *http.Request) {
@@ -36,7 +52,7 @@ func CreateTenderHandler(w http.ResponseWriter, r *http.Request) {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 	err := validate.Struct(tenderReq)
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err = errors.New("incorrect request body")
 		apierror.HandleError(w, r, err, http.StatusBadRequest)
 		return
@@ -44,18 +60,27 @@ func CreateTenderHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := tenderService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("tenders service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
 	tender, err := service.CreateTender(
+		r.Context(),
 		tenderReq.Name,
 		tenderReq.Description,
 		model.TenderServiceType(tenderReq.ServiceType),
 		tenderReq.OrganizationId,
 		tenderReq.CreatorUsername,
+		tenderReq.InviteOnly,
+		tenderReq.Criteria,
+		model.TenderBudget{
+			Budget:       tenderReq.Budget,
+			Reserve:      tenderReq.Reserve,
+			Currency:     tenderReq.Currency,
+			StrictBudget: tenderReq.StrictBudget,
+		},
 	)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
@@ -89,15 +114,20 @@ func GetTendersHandler(w http.ResponseWriter, r *http.Request) {
 		serviceType = model.TenderServiceType(serviceTypeQ)
 	}
 
+	includeSubcategories := r.URL.Query().Get("includeSubcategories") == "true"
+
 	service, err := tenderService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("tenders service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	tenders, err := service.GetTenders(serviceType, offset, limit, "")
+	username := r.URL.Query().Get("username")
+	tenders, err := service.GetTenders(
+		r.Context(), serviceType, includeSubcategories, offset, limit, "", username,
+	)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, tenderService.ErrorUserNorFound) {
@@ -133,13 +163,13 @@ func GetMyTendersHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := tenderService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("tenders service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	tenders, err := service.GetMyTenders(offset, limit, username)
+	tenders, err := service.GetMyTenders(r.Context(), offset, limit, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, tenderService.ErrorUserNorFound) {
@@ -165,13 +195,13 @@ func GetTenderStatusHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := tenderService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("tenders service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	tender, err := service.GetTenderById(tenderId)
+	tender, err := service.GetTenderById(r.Context(), tenderId)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, tenderService.ErrorTenderNorFound) {
@@ -182,7 +212,7 @@ func GetTenderStatusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	username := r.URL.Query().Get("username")
-	err = service.CheckReadRightByUsername(tenderId, username)
+	err = service.CheckReadRightByUsername(r.Context(), tenderId, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, tenderService.ErrorTenderNorFound) {
@@ -191,7 +221,8 @@ func GetTenderStatusHandler(w http.ResponseWriter, r *http.Request) {
 		if errors.Is(err, tenderService.ErrorUserNorFound) {
 			httpStatus = http.StatusUnauthorized
 		}
-		if errors.Is(err, tenderService.ErrorUserIsNotOrgResponsible) {
+		if errors.Is(err, tenderService.ErrorUserIsNotOrgResponsible) ||
+			errors.Is(err, tenderService.ErrorUserIsNotInvited) {
 			httpStatus = http.StatusForbidden
 		}
 		apierror.HandleError(w, r, err, httpStatus)
@@ -227,13 +258,13 @@ func UpdateTenderStatusHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := tenderService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("tenders service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	err = service.CheckWriteRightByUsername(tenderId, username)
+	err = service.CheckWriteRightByUsername(r.Context(), tenderId, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, tenderService.ErrorTenderNorFound) {
@@ -249,9 +280,13 @@ func UpdateTenderStatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tender, err := service.UpdateTenderStatus(tenderId, model.TenderStatus(status))
+	tender, err := service.UpdateTenderStatus(r.Context(), tenderId, model.TenderStatus(status))
 	if err != nil {
-		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, tenderService.ErrorIncorrectStage) {
+			httpStatus = http.StatusConflict
+		}
+		apierror.HandleError(w, r, err, httpStatus)
 		return
 	}
 
@@ -279,7 +314,7 @@ func EditTenderHandler(w http.ResponseWriter, r *http.Request) {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 	err = validate.Struct(editTenderReq)
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err = errors.New("incorrect request body")
 		apierror.HandleError(w, r, err, http.StatusBadRequest)
 		return
@@ -287,7 +322,13 @@ func EditTenderHandler(w http.ResponseWriter, r *http.Request) {
 
 	if editTenderReq.Name == "" &&
 		editTenderReq.Description == "" &&
-		editTenderReq.ServiceType == "" {
+		editTenderReq.ServiceType == "" &&
+		editTenderReq.InviteOnly == nil &&
+		editTenderReq.Criteria == nil &&
+		editTenderReq.Budget == nil &&
+		editTenderReq.Reserve == nil &&
+		editTenderReq.Currency == "" &&
+		editTenderReq.StrictBudget == nil {
 		err = errors.New("incorrect request body")
 		apierror.HandleError(w, r, err, http.StatusBadRequest)
 		return
@@ -295,13 +336,13 @@ func EditTenderHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := tenderService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("tenders service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	err = service.CheckWriteRightByUsername(tenderId, username)
+	err = service.CheckWriteRightByUsername(r.Context(), tenderId, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, tenderService.ErrorTenderNorFound) {
@@ -318,10 +359,17 @@ func EditTenderHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	tender, err := service.UpdateTender(
+		r.Context(),
 		tenderId,
 		editTenderReq.Name,
 		editTenderReq.Description,
 		editTenderReq.ServiceType,
+		editTenderReq.InviteOnly,
+		editTenderReq.Criteria,
+		editTenderReq.Budget,
+		editTenderReq.Reserve,
+		editTenderReq.Currency,
+		editTenderReq.StrictBudget,
 	)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
@@ -360,13 +408,13 @@ func RollbackTenderHandler(w http.ResponseWriter, r *http.Request) {
 
 	service, err := tenderService.NewService()
 	if err != nil {
-		slog.Error(err.Error())
+		logging.FromContext(r.Context()).Error(err.Error())
 		err := errors.New("tenders service creation failed")
 		apierror.HandleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	err = service.CheckWriteRightByUsername(tenderId, username)
+	err = service.CheckWriteRightByUsername(r.Context(), tenderId, username)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, tenderService.ErrorTenderNorFound) {
@@ -382,8 +430,61 @@ func RollbackTenderHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tender, err := service.RollBackTender(tenderId, int32(version))
+	tender, err := service.RollBackTender(r.Context(), tenderId, int32(version))
+
+	if err != nil {
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, tenderService.ErrorTenderNorFound) {
+			httpStatus = http.StatusNotFound
+		}
+		apierror.HandleError(w, r, err, httpStatus)
+		return
+	}
+
+	res, _ := json.Marshal(tender)
+	w.Write(res)
+}
+
+func GetTenderHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := tenderService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("tenders service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = service.CheckWriteRightByUsername(r.Context(), tenderId, username)
+	if err != nil {
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, tenderService.ErrorTenderNorFound) {
+			httpStatus = http.StatusNotFound
+		}
+		if errors.Is(err, tenderService.ErrorUserNorFound) {
+			httpStatus = http.StatusUnauthorized
+		}
+		if errors.Is(err, tenderService.ErrorUserIsNotOrgResponsible) {
+			httpStatus = http.StatusForbidden
+		}
+		apierror.HandleError(w, r, err, httpStatus)
+		return
+	}
 
+	tenders, err := service.GetTenderHistory(r.Context(), tenderId)
 	if err != nil {
 		httpStatus := http.StatusBadRequest
 		if errors.Is(err, tenderService.ErrorTenderNorFound) {
@@ -393,6 +494,76 @@ func RollbackTenderHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	res, _ := json.Marshal(tenders)
+	w.Write(res)
+}
+
+func CloneTenderHandler(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.Parse(chi.URLParam(r, "tenderId"))
+	if err != nil {
+		err = errors.New("incorrect tender uuid")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		err := errors.New("username param is required")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	cloneReq := CloneTenderRequest{}
+	json.NewDecoder(r.Body).Decode(&cloneReq)
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	err = validate.Struct(cloneReq)
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("incorrect request body")
+		apierror.HandleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	service, err := tenderService.NewService()
+	if err != nil {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err := errors.New("tenders service creation failed")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = service.CheckWriteRightByUsername(r.Context(), tenderId, username)
+	if err != nil {
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, tenderService.ErrorTenderNorFound) {
+			httpStatus = http.StatusNotFound
+		}
+		if errors.Is(err, tenderService.ErrorUserNorFound) {
+			httpStatus = http.StatusUnauthorized
+		}
+		if errors.Is(err, tenderService.ErrorUserIsNotOrgResponsible) {
+			httpStatus = http.StatusForbidden
+		}
+		apierror.HandleError(w, r, err, httpStatus)
+		return
+	}
+
+	tender, err := service.CloneTender(r.Context(), tenderId, username, cloneReq.Name)
+	if err != nil {
+		httpStatus := http.StatusBadRequest
+		if errors.Is(err, tenderService.ErrorTenderNorFound) {
+			httpStatus = http.StatusNotFound
+		}
+		if errors.Is(err, tenderService.ErrorUserNorFound) {
+			httpStatus = http.StatusUnauthorized
+		}
+		if errors.Is(err, tenderService.ErrorTenderIsNotClosed) {
+			httpStatus = http.StatusConflict
+		}
+		apierror.HandleError(w, r, err, httpStatus)
+		return
+	}
+
 	res, _ := json.Marshal(tender)
 	w.Write(res)
 }
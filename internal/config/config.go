@@ -0,0 +1,511 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server      Server      `yaml:"server"`
+	Postgres    Postgres    `yaml:"postgres"`
+	Tracing     Tracing     `yaml:"tracing"`
+	Logging     Logging     `yaml:"logging"`
+	RateLimit   RateLimit   `yaml:"rateLimit"`
+	Idempotency Idempotency `yaml:"idempotency"`
+	Storage     Storage     `yaml:"storage"`
+	Attachments Attachments `yaml:"attachments"`
+	Admin       Admin       `yaml:"admin"`
+}
+
+type Server struct {
+	Address           string        `yaml:"address"`
+	ReadTimeout       time.Duration `yaml:"readTimeout"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdownTimeout"`
+	DrainDelay        time.Duration `yaml:"drainDelay"`
+}
+
+type Postgres struct {
+	Conn                     string        `yaml:"conn"`
+	MaxOpenConns             int           `yaml:"maxOpenConns"`
+	MaxIdleConns             int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime          time.Duration `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime          time.Duration `yaml:"connMaxIdleTime"`
+	ConnectTimeout           time.Duration `yaml:"connectTimeout"`
+	StatementTimeout         time.Duration `yaml:"statementTimeout"`
+	IdleInTransactionTimeout time.Duration `yaml:"idleInTransactionTimeout"`
+}
+
+type Logging struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+type RateLimit struct {
+	Enabled          bool                  `yaml:"enabled"`
+	Store            string                `yaml:"store"`
+	FailOpen         bool                  `yaml:"failOpen"`
+	TrustedProxies   []string              `yaml:"trustedProxies"`
+	User             Limit                 `yaml:"user"`
+	IP               Limit                 `yaml:"ip"`
+	Routes           map[string]RouteLimit `yaml:"routes"`
+	MaxBidsPerAuthor int                   `yaml:"maxBidsPerAuthor"`
+}
+
+type RouteLimit struct {
+	User Limit `yaml:"user"`
+	IP   Limit `yaml:"ip"`
+}
+
+type Limit struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	Burst    int           `yaml:"burst"`
+}
+
+type Idempotency struct {
+	Enabled     bool          `yaml:"enabled"`
+	Retention   time.Duration `yaml:"retention"`
+	LockTimeout time.Duration `yaml:"lockTimeout"`
+}
+
+type Storage struct {
+	Type     string `yaml:"type"`
+	LocalDir string `yaml:"localDir"`
+	S3       S3     `yaml:"s3"`
+}
+
+type S3 struct {
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"accessKey"`
+	SecretKey string `yaml:"secretKey"`
+}
+
+type Attachments struct {
+	MaxSize int64 `yaml:"maxSize"`
+}
+
+type Admin struct {
+	Usernames []string `yaml:"usernames"`
+}
+
+type Tracing struct {
+	Enabled     bool    `yaml:"enabled"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	ServiceName string  `yaml:"serviceName"`
+	SampleRatio float64 `yaml:"sampleRatio"`
+}
+
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			ReadTimeout:       30 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   15 * time.Second,
+		},
+		Postgres: Postgres{
+			MaxOpenConns:             20,
+			MaxIdleConns:             10,
+			ConnMaxLifetime:          30 * time.Minute,
+			ConnMaxIdleTime:          5 * time.Minute,
+			ConnectTimeout:           5 * time.Second,
+			StatementTimeout:         15 * time.Second,
+			IdleInTransactionTimeout: time.Minute,
+		},
+		Tracing: Tracing{
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			ServiceName: "avi",
+			SampleRatio: 1,
+		},
+		Logging: Logging{
+			Level:  "info",
+			Format: "json",
+		},
+		RateLimit: RateLimit{
+			Enabled:  true,
+			Store:    "memory",
+			FailOpen: true,
+			User:     Limit{Requests: 60, Period: time.Minute},
+			IP:       Limit{Requests: 120, Period: time.Minute},
+			Routes: map[string]RouteLimit{
+				"POST /api/bids/new": {
+					User: Limit{Requests: 10, Period: time.Minute, Burst: 5},
+					IP:   Limit{Requests: 30, Period: time.Minute, Burst: 10},
+				},
+				"PUT /api/bids/{bidId}/feedback": {
+					User: Limit{Requests: 10, Period: time.Minute, Burst: 5},
+					IP:   Limit{Requests: 30, Period: time.Minute, Burst: 10},
+				},
+			},
+		},
+		Idempotency: Idempotency{
+			Enabled:     true,
+			Retention:   24 * time.Hour,
+			LockTimeout: time.Minute,
+		},
+		Storage: Storage{
+			Type:     "local",
+			LocalDir: "attachments",
+		},
+		Attachments: Attachments{
+			MaxSize: 20 << 20,
+		},
+	}
+}
+
+func Load(path string) (*Config, error) {
+	cfg, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func Read(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("can not read config: %w", err)
+		}
+		defer file.Close()
+
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true)
+		err = decoder.Decode(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("can not parse config %s: %w", path, err)
+		}
+	}
+
+	err := cfg.applyEnv()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) applyEnv() error {
+	env := envReader{}
+	env.string("SERVER_ADDRESS", &cfg.Server.Address)
+	env.duration("SERVER_READ_TIMEOUT", &cfg.Server.ReadTimeout)
+	env.duration("SERVER_READ_HEADER_TIMEOUT", &cfg.Server.ReadHeaderTimeout)
+	env.duration("SERVER_WRITE_TIMEOUT", &cfg.Server.WriteTimeout)
+	env.duration("SERVER_IDLE_TIMEOUT", &cfg.Server.IdleTimeout)
+	env.duration("SERVER_SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+	env.duration("SERVER_DRAIN_DELAY", &cfg.Server.DrainDelay)
+	env.string("POSTGRES_CONN", &cfg.Postgres.Conn)
+	env.int("POSTGRES_MAX_OPEN_CONNS", &cfg.Postgres.MaxOpenConns)
+	env.int("POSTGRES_MAX_IDLE_CONNS", &cfg.Postgres.MaxIdleConns)
+	env.duration("POSTGRES_CONN_MAX_LIFETIME", &cfg.Postgres.ConnMaxLifetime)
+	env.duration("POSTGRES_CONN_MAX_IDLE_TIME", &cfg.Postgres.ConnMaxIdleTime)
+	env.duration("POSTGRES_CONNECT_TIMEOUT", &cfg.Postgres.ConnectTimeout)
+	env.duration("POSTGRES_STATEMENT_TIMEOUT", &cfg.Postgres.StatementTimeout)
+	env.duration("POSTGRES_IDLE_IN_TRANSACTION_TIMEOUT", &cfg.Postgres.IdleInTransactionTimeout)
+	env.bool("TRACING_ENABLED", &cfg.Tracing.Enabled)
+	env.string("TRACING_ENDPOINT", &cfg.Tracing.Endpoint)
+	env.bool("TRACING_INSECURE", &cfg.Tracing.Insecure)
+	env.string("TRACING_SERVICE_NAME", &cfg.Tracing.ServiceName)
+	env.float("TRACING_SAMPLE_RATIO", &cfg.Tracing.SampleRatio)
+	env.string("LOG_LEVEL", &cfg.Logging.Level)
+	env.string("LOG_FORMAT", &cfg.Logging.Format)
+	env.bool("RATE_LIMIT_ENABLED", &cfg.RateLimit.Enabled)
+	env.string("RATE_LIMIT_STORE", &cfg.RateLimit.Store)
+	env.bool("RATE_LIMIT_FAIL_OPEN", &cfg.RateLimit.FailOpen)
+	env.list("RATE_LIMIT_TRUSTED_PROXIES", &cfg.RateLimit.TrustedProxies)
+	env.int("RATE_LIMIT_USER_REQUESTS", &cfg.RateLimit.User.Requests)
+	env.duration("RATE_LIMIT_USER_PERIOD", &cfg.RateLimit.User.Period)
+	env.int("RATE_LIMIT_USER_BURST", &cfg.RateLimit.User.Burst)
+	env.int("RATE_LIMIT_IP_REQUESTS", &cfg.RateLimit.IP.Requests)
+	env.duration("RATE_LIMIT_IP_PERIOD", &cfg.RateLimit.IP.Period)
+	env.int("RATE_LIMIT_IP_BURST", &cfg.RateLimit.IP.Burst)
+	env.int("MAX_BIDS_PER_AUTHOR", &cfg.RateLimit.MaxBidsPerAuthor)
+	env.bool("IDEMPOTENCY_ENABLED", &cfg.Idempotency.Enabled)
+	env.duration("IDEMPOTENCY_RETENTION", &cfg.Idempotency.Retention)
+	env.duration("IDEMPOTENCY_LOCK_TIMEOUT", &cfg.Idempotency.LockTimeout)
+	env.string("STORAGE_TYPE", &cfg.Storage.Type)
+	env.string("STORAGE_LOCAL_DIR", &cfg.Storage.LocalDir)
+	env.string("S3_ENDPOINT", &cfg.Storage.S3.Endpoint)
+	env.string("S3_REGION", &cfg.Storage.S3.Region)
+	env.string("S3_BUCKET", &cfg.Storage.S3.Bucket)
+	env.string("S3_ACCESS_KEY", &cfg.Storage.S3.AccessKey)
+	env.string("S3_SECRET_KEY", &cfg.Storage.S3.SecretKey)
+	env.int64("MAX_ATTACHMENT_SIZE", &cfg.Attachments.MaxSize)
+	env.list("ADMIN_USERNAMES", &cfg.Admin.Usernames)
+	return errors.Join(env.errs...)
+}
+
+func (cfg *Config) Validate() error {
+	var errs []error
+
+	if cfg.Server.Address == "" {
+		errs = append(errs, errors.New("server address is required (SERVER_ADDRESS)"))
+	} else if _, _, err := net.SplitHostPort(cfg.Server.Address); err != nil {
+		errs = append(errs, fmt.Errorf("incorrect server address %q: %w", cfg.Server.Address, err))
+	}
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server read timeout", cfg.Server.ReadTimeout},
+		{"server read header timeout", cfg.Server.ReadHeaderTimeout},
+		{"server write timeout", cfg.Server.WriteTimeout},
+		{"server idle timeout", cfg.Server.IdleTimeout},
+		{"server drain delay", cfg.Server.DrainDelay},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative", timeout.name))
+		}
+	}
+	if cfg.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server shutdown timeout must be positive"))
+	}
+
+	errs = append(errs, cfg.Postgres.Validate())
+
+	if cfg.Tracing.Enabled {
+		if cfg.Tracing.Endpoint == "" {
+			errs = append(errs, errors.New("tracing endpoint is required (TRACING_ENDPOINT)"))
+		}
+		if cfg.Tracing.ServiceName == "" {
+			errs = append(errs, errors.New("tracing service name is required (TRACING_SERVICE_NAME)"))
+		}
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("tracing sample ratio must be between 0 and 1"))
+	}
+
+	switch strings.ToLower(cfg.Logging.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("incorrect log level %q (LOG_LEVEL)", cfg.Logging.Level))
+	}
+	switch strings.ToLower(cfg.Logging.Format) {
+	case "json", "text":
+	default:
+		errs = append(errs, fmt.Errorf("incorrect log format %q (LOG_FORMAT)", cfg.Logging.Format))
+	}
+
+	switch cfg.RateLimit.Store {
+	case "memory", "postgres":
+	default:
+		errs = append(errs, fmt.Errorf("incorrect rate limit store %q (RATE_LIMIT_STORE)", cfg.RateLimit.Store))
+	}
+	_, err := cfg.RateLimit.ProxyPrefixes()
+	errs = append(errs, err)
+	errs = append(errs, cfg.RateLimit.User.validate("user rate limit"))
+	errs = append(errs, cfg.RateLimit.IP.validate("ip rate limit"))
+	for route, limit := range cfg.RateLimit.Routes {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("incorrect rate limit route %q, expected \"METHOD /path\"", route))
+		}
+		errs = append(errs, limit.User.validate(route+" user rate limit"))
+		errs = append(errs, limit.IP.validate(route+" ip rate limit"))
+	}
+	if cfg.RateLimit.MaxBidsPerAuthor < 0 {
+		errs = append(errs, errors.New("max bids per author must not be negative (MAX_BIDS_PER_AUTHOR)"))
+	}
+
+	if cfg.Idempotency.Retention <= 0 {
+		errs = append(errs, errors.New("idempotency retention must be positive (IDEMPOTENCY_RETENTION)"))
+	}
+	if cfg.Idempotency.LockTimeout <= 0 {
+		errs = append(errs, errors.New("idempotency lock timeout must be positive (IDEMPOTENCY_LOCK_TIMEOUT)"))
+	}
+
+	switch cfg.Storage.Type {
+	case "local":
+		if cfg.Storage.LocalDir == "" {
+			errs = append(errs, errors.New("storage local dir is required (STORAGE_LOCAL_DIR)"))
+		}
+	case "s3":
+		s3 := cfg.Storage.S3
+		if s3.Endpoint == "" || s3.Bucket == "" || s3.AccessKey == "" || s3.SecretKey == "" {
+			errs = append(errs, errors.New(
+				"s3 endpoint, bucket and credentials are required (S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY)",
+			))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("incorrect storage type %q (STORAGE_TYPE)", cfg.Storage.Type))
+	}
+	if cfg.Attachments.MaxSize <= 0 {
+		errs = append(errs, errors.New("max attachment size must be positive (MAX_ATTACHMENT_SIZE)"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (pg Postgres) Validate() error {
+	var errs []error
+
+	if pg.Conn == "" {
+		errs = append(errs, errors.New("postgres connection is required (POSTGRES_CONN)"))
+	}
+	if pg.MaxOpenConns < 0 || pg.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("postgres pool size must not be negative"))
+	}
+	if pg.MaxOpenConns > 0 && pg.MaxIdleConns > pg.MaxOpenConns {
+		errs = append(errs, errors.New("postgres max idle conns must not exceed max open conns"))
+	}
+	if pg.ConnectTimeout <= 0 {
+		errs = append(errs, errors.New("postgres connect timeout must be positive"))
+	}
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"postgres conn max lifetime", pg.ConnMaxLifetime},
+		{"postgres conn max idle time", pg.ConnMaxIdleTime},
+		{"postgres statement timeout", pg.StatementTimeout},
+		{"postgres idle in transaction timeout", pg.IdleInTransactionTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative", timeout.name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (cfg RateLimit) ProxyPrefixes() ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	var errs []error
+	for _, proxy := range cfg.TrustedProxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("incorrect trusted proxy %q (RATE_LIMIT_TRUSTED_PROXIES)", proxy))
+			continue
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, errors.Join(errs...)
+}
+
+func (limit Limit) validate(name string) error {
+	switch {
+	case limit.Requests < 0 || limit.Burst < 0:
+		return fmt.Errorf("%s must not be negative", name)
+	case limit.Requests > 0 && limit.Period <= 0:
+		return fmt.Errorf("%s period must be positive", name)
+	}
+	return nil
+}
+
+type envReader struct {
+	errs []error
+}
+
+func (env *envReader) string(key string, dst *string) {
+	value, ok := os.LookupEnv(key)
+	if ok && value != "" {
+		*dst = value
+	}
+}
+
+func (env *envReader) int(key string, dst *int) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("incorrect %s: %w", key, err))
+		return
+	}
+	*dst = parsed
+}
+
+func (env *envReader) int64(key string, dst *int64) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("incorrect %s: %w", key, err))
+		return
+	}
+	*dst = parsed
+}
+
+func (env *envReader) list(key string, dst *[]string) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
+func (env *envReader) duration(key string, dst *time.Duration) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("incorrect %s: %w", key, err))
+		return
+	}
+	*dst = parsed
+}
+
+func (env *envReader) bool(key string, dst *bool) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("incorrect %s: %w", key, err))
+		return
+	}
+	*dst = parsed
+}
+
+func (env *envReader) float(key string, dst *float64) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		env.errs = append(env.errs, fmt.Errorf("incorrect %s: %w", key, err))
+		return
+	}
+	*dst = parsed
+}
@@ -1,33 +1,119 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"log/slog"
-	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+
+	"avi/internal/config"
 )
 
-func Connect() (db *sql.DB, err error) {
-	connStr, ok := os.LookupEnv("POSTGRES_CONN")
-	if !ok {
-		errMsg := "no POSTGRES_CONN environment variable"
-		slog.Error(errMsg)
-		err = errors.New(errMsg)
+var ErrorPoolIsNotOpen = errors.New("database pool is not open")
+var ErrorQueryTimeout = errors.New("database query timed out")
+var ErrorQueryCanceled = errors.New("database query was canceled")
+var ErrorLockNotAvailable = errors.New("database row is locked by another request, retry later")
+
+var pool atomic.Pointer[sql.DB]
+
+func Open(ctx context.Context, cfg config.Postgres) (db *sql.DB, err error) {
+	conn, err := connString(cfg)
+	if err != nil {
 		return
 	}
-
-	db, err = sql.Open("postgres", connStr)
+	db, err = sql.Open("postgres", conn)
 	if err != nil {
 		slog.Error(err.Error())
 		return
 	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	err = db.Ping()
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+	err = db.PingContext(pingCtx)
 	if err != nil {
 		slog.Error(err.Error())
-		return
+		db.Close()
+		return nil, err
+	}
+
+	old := pool.Swap(db)
+	if old != nil {
+		old.Close()
 	}
 	return
 }
+
+func Connect() (db *sql.DB, err error) {
+	db = pool.Load()
+	if db == nil {
+		slog.Error(ErrorPoolIsNotOpen.Error())
+		err = ErrorPoolIsNotOpen
+	}
+	return
+}
+
+func Close() error {
+	db := pool.Swap(nil)
+	if db == nil {
+		return nil
+	}
+	return db.Close()
+}
+
+func Interrupted(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.Canceled):
+		return ErrorQueryCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorQueryTimeout
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "57014":
+			return ErrorQueryTimeout
+		case "55P03":
+			return ErrorLockNotAvailable
+		}
+	}
+	return nil
+}
+
+func connString(cfg config.Postgres) (conn string, err error) {
+	conn = cfg.Conn
+	if strings.HasPrefix(conn, "postgres://") || strings.HasPrefix(conn, "postgresql://") {
+		conn, err = pq.ParseURL(conn)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	params := []string{conn}
+	if cfg.StatementTimeout > 0 {
+		params = append(params, "statement_timeout="+milliseconds(cfg.StatementTimeout))
+	}
+	if cfg.IdleInTransactionTimeout > 0 {
+		params = append(
+			params,
+			"idle_in_transaction_session_timeout="+milliseconds(cfg.IdleInTransactionTimeout),
+		)
+	}
+	return strings.Join(params, " "), nil
+}
+
+func milliseconds(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
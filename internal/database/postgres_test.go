@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestInterrupted(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"context canceled", fmt.Errorf("query: %w", context.Canceled), ErrorQueryCanceled},
+		{"context deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), ErrorQueryTimeout},
+		{"statement timeout", &pq.Error{Code: "57014"}, ErrorQueryTimeout},
+		{"lock not available", &pq.Error{Code: "55P03"}, ErrorLockNotAvailable},
+		{"idle in transaction timeout", &pq.Error{Code: "25P03"}, nil},
+		{"unique violation", &pq.Error{Code: "23505"}, nil},
+		{"other error", errors.New("connection reset"), nil},
+	}
+	for _, test := range tests {
+		if got := Interrupted(test.err); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
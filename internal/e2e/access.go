@@ -0,0 +1,212 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	"avi/internal/model"
+	"avi/pkg/client"
+)
+
+func invitationsScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	invitee, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	outsider, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	owner := c.owner()
+
+	tender, err := h.publishedTender(ctx, c, true)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.GetTenderStatus(ctx, tender.Id, outsider.owner().Username)
+	err = expectError(err, client.ErrForbidden, "get invite-only tender status by outsider")
+	if err != nil {
+		return err
+	}
+	_, err = h.createUserBid(ctx, tender.Id, outsider.owner())
+	err = expectError(err, client.ErrForbidden, "bid on invite-only tender by outsider")
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.InviteToTender(
+		ctx, tender.Id, outsider.owner().Username, model.UserInviteeType, invitee.owner().Id,
+	)
+	err = expectError(err, client.ErrForbidden, "invite by outsider")
+	if err != nil {
+		return err
+	}
+
+	invitation, err := h.Client.InviteToTender(
+		ctx, tender.Id, owner.Username, model.UserInviteeType, invitee.owner().Id,
+	)
+	if err != nil {
+		return fmt.Errorf("invite user: %w", err)
+	}
+
+	invitations, err := h.Client.GetTenderInvitations(ctx, tender.Id, owner.Username)
+	if err != nil {
+		return fmt.Errorf("get invitations: %w", err)
+	}
+	err = check(len(invitations) == 1 && invitations[0].Id == invitation.Id,
+		"invitations: expected only %s, got %d", invitation.Id, len(invitations))
+	if err != nil {
+		return err
+	}
+
+	err = h.tenderStatus(ctx, tender.Id, invitee.owner().Username, model.TenderStatusPublished)
+	if err != nil {
+		return fmt.Errorf("invitee: %w", err)
+	}
+	_, err = h.createUserBid(ctx, tender.Id, invitee.owner())
+	if err != nil {
+		return fmt.Errorf("invitee: %w", err)
+	}
+
+	err = h.Client.RevokeTenderInvitation(ctx, tender.Id, invitation.Id, owner.Username)
+	if err != nil {
+		return fmt.Errorf("revoke invitation: %w", err)
+	}
+
+	_, err = h.Client.GetTenderStatus(ctx, tender.Id, invitee.owner().Username)
+	return expectError(err, client.ErrForbidden, "get tender status after revoke")
+}
+
+func shortlistScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	shortlisted, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	removed, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	owner := c.owner()
+
+	tender, err := h.createTender(ctx, c, "Delivery", false)
+	if err != nil {
+		return err
+	}
+	_, err = h.Client.UpdateTenderStatus(
+		ctx, tender.Id, model.TenderStatusPrequalification, owner.Username,
+	)
+	if err != nil {
+		return fmt.Errorf("start prequalification: %w", err)
+	}
+
+	var entries []*model.ShortlistEntry
+	for _, bidder := range []*customer{shortlisted, removed} {
+		bid, err := h.createUserBid(ctx, tender.Id, bidder.owner())
+		if err != nil {
+			return err
+		}
+		if bid.Stage != model.PrequalificationBidStage {
+			return fmt.Errorf("prequalification bid: unexpected stage %s", bid.Stage)
+		}
+
+		_, err = h.Client.SubmitBidDecision(ctx, bid.Id, client.DecisionApproved, owner.Username)
+		err = expectError(err, client.ErrConflict, "decision on prequalification bid")
+		if err != nil {
+			return err
+		}
+
+		entry, err := h.Client.ShortlistBidAuthor(ctx, tender.Id, bid.Id, owner.Username)
+		if err != nil {
+			return fmt.Errorf("shortlist bid author: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	err = h.Client.RemoveFromShortlist(ctx, tender.Id, entries[1].Id, owner.Username)
+	if err != nil {
+		return fmt.Errorf("remove from shortlist: %w", err)
+	}
+
+	list, err := h.Client.GetShortlist(ctx, tender.Id, owner.Username)
+	if err != nil {
+		return fmt.Errorf("get shortlist: %w", err)
+	}
+	err = check(len(list) == 1 && list[0].Id == entries[0].Id,
+		"shortlist: expected only %s, got %d entries", entries[0].Id, len(list))
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.UpdateTenderStatus(
+		ctx, tender.Id, model.TenderStatusCommercial, owner.Username,
+	)
+	if err != nil {
+		return fmt.Errorf("start commercial stage: %w", err)
+	}
+
+	_, err = h.createUserBid(ctx, tender.Id, removed.owner())
+	err = expectError(err, client.ErrForbidden, "commercial bid by removed author")
+	if err != nil {
+		return err
+	}
+
+	bid, err := h.createUserBid(ctx, tender.Id, shortlisted.owner())
+	if err != nil {
+		return fmt.Errorf("commercial bid: %w", err)
+	}
+	return check(bid.Stage == model.CommercialBidStage,
+		"commercial bid: unexpected stage %s", bid.Stage)
+}
+
+func questionsScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	asker, err := h.CreateEmployee(ctx)
+	if err != nil {
+		return err
+	}
+	owner := c.owner()
+
+	tender, err := h.publishedTender(ctx, c, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.AskQuestion(ctx, tender.Id, owner.Username, "own question")
+	err = expectError(err, client.ErrForbidden, "question by responsible")
+	if err != nil {
+		return err
+	}
+
+	question, err := h.Client.AskQuestion(ctx, tender.Id, asker.Username, "delivery terms?")
+	if err != nil {
+		return fmt.Errorf("ask question: %w", err)
+	}
+
+	question, err = h.Client.AnswerQuestion(ctx, tender.Id, question.Id, owner.Username, client.Answer{
+		Text:       "within 30 days",
+		Visibility: model.PublicAnswerVisibility,
+	})
+	if err != nil {
+		return fmt.Errorf("answer question: %w", err)
+	}
+
+	questions, err := h.Client.GetQuestions(ctx, tender.Id, asker.Username, client.Page{})
+	if err != nil {
+		return fmt.Errorf("get questions: %w", err)
+	}
+	return check(len(questions) == 1 && questions[0].Status == model.AnsweredQuestionStatus &&
+		questions[0].Answer == question.Answer,
+		"questions: expected one answered question, got %d", len(questions))
+}
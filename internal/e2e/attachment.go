@@ -0,0 +1,146 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+	"avi/pkg/client"
+)
+
+type attachmentOps struct {
+	object   string
+	upload   func(fileName string, content io.Reader) (*model.Attachment, error)
+	list     func() ([]*model.Attachment, error)
+	download func(id uuid.UUID) (*client.AttachmentContent, error)
+	remove   func(id uuid.UUID) error
+}
+
+func attachmentsScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	bidder, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	owner, author := c.owner(), bidder.owner()
+
+	tender, err := h.publishedTender(ctx, c, false)
+	if err != nil {
+		return err
+	}
+	bid, err := h.createUserBid(ctx, tender.Id, author)
+	if err != nil {
+		return err
+	}
+
+	err = checkAttachments(attachmentOps{
+		object: "tender",
+		upload: func(fileName string, content io.Reader) (*model.Attachment, error) {
+			return h.Client.UploadTenderAttachment(ctx, tender.Id, owner.Username, fileName, content)
+		},
+		list: func() ([]*model.Attachment, error) {
+			return h.Client.GetTenderAttachments(ctx, tender.Id, 0, owner.Username)
+		},
+		download: func(id uuid.UUID) (*client.AttachmentContent, error) {
+			return h.Client.DownloadTenderAttachment(ctx, tender.Id, id, owner.Username)
+		},
+		remove: func(id uuid.UUID) error {
+			_, err := h.Client.DeleteTenderAttachment(ctx, tender.Id, id, owner.Username)
+			return err
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = checkAttachments(attachmentOps{
+		object: "bid",
+		upload: func(fileName string, content io.Reader) (*model.Attachment, error) {
+			return h.Client.UploadBidAttachment(ctx, bid.Id, author.Username, fileName, content)
+		},
+		list: func() ([]*model.Attachment, error) {
+			return h.Client.GetBidAttachments(ctx, bid.Id, 0, author.Username)
+		},
+		download: func(id uuid.UUID) (*client.AttachmentContent, error) {
+			return h.Client.DownloadBidAttachment(ctx, bid.Id, id, author.Username)
+		},
+		remove: func(id uuid.UUID) error {
+			_, err := h.Client.DeleteBidAttachment(ctx, bid.Id, id, author.Username)
+			return err
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.UploadBidAttachment(
+		ctx, bid.Id, owner.Username, "proposal.txt", strings.NewReader("tender owner file"),
+	)
+	err = expectFailure(err, "upload bid attachment by tender owner")
+	if err != nil {
+		return err
+	}
+	_, err = h.Client.GetBidAttachments(ctx, bid.Id, 0, owner.Username)
+	if err != nil {
+		return fmt.Errorf("list bid attachments by tender owner: %w", err)
+	}
+	return nil
+}
+
+func checkAttachments(ops attachmentOps) error {
+	_, err := ops.upload("empty.txt", strings.NewReader(""))
+	err = expectError(err, client.ErrBadRequest, "upload empty "+ops.object+" attachment")
+	if err != nil {
+		return err
+	}
+
+	content := "end-to-end " + ops.object + " attachment"
+	attachment, err := ops.upload("notes.txt", strings.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("upload %s attachment: %w", ops.object, err)
+	}
+
+	attachments, err := ops.list()
+	if err != nil {
+		return fmt.Errorf("get %s attachments: %w", ops.object, err)
+	}
+	err = check(len(attachments) == 1 && attachments[0].Id == attachment.Id,
+		"%s attachments: expected only %s, got %d", ops.object, attachment.Id, len(attachments))
+	if err != nil {
+		return err
+	}
+
+	file, err := ops.download(attachment.Id)
+	if err != nil {
+		return fmt.Errorf("download %s attachment: %w", ops.object, err)
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("read %s attachment: %w", ops.object, err)
+	}
+	err = check(string(data) == content && file.FileName == "notes.txt",
+		"%s attachment: unexpected content %q of %q", ops.object, data, file.FileName)
+	if err != nil {
+		return err
+	}
+
+	err = ops.remove(attachment.Id)
+	if err != nil {
+		return fmt.Errorf("delete %s attachment: %w", ops.object, err)
+	}
+
+	attachments, err = ops.list()
+	if err != nil {
+		return fmt.Errorf("get %s attachments after delete: %w", ops.object, err)
+	}
+	return check(len(attachments) == 0,
+		"%s attachments after delete: expected none, got %d", ops.object, len(attachments))
+}
@@ -0,0 +1,225 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	"avi/internal/model"
+	"avi/pkg/client"
+)
+
+func bidLifecycleScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	bidder, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	outsider, err := h.CreateEmployee(ctx)
+	if err != nil {
+		return err
+	}
+	owner, author := c.owner(), bidder.owner()
+
+	tender, err := h.publishedTender(ctx, c, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.CreateBid(ctx, client.NewBid{
+		Name:            uniqueName("e2e bid"),
+		Description:     "own tender bid",
+		TenderId:        tender.Id,
+		AuthorType:      model.OrgBidAuthorType,
+		AuthorId:        c.org.Id,
+		CreatorUsername: owner.Username,
+	})
+	err = expectError(err, client.ErrForbidden, "bid on own tender")
+	if err != nil {
+		return err
+	}
+
+	bid, err := h.createUserBid(ctx, tender.Id, author)
+	if err != nil {
+		return err
+	}
+	err = check(bid.Status == model.CreatedBidStatus && bid.Stage == model.CommercialBidStage,
+		"new bid: status %s, stage %s", bid.Status, bid.Stage)
+	if err != nil {
+		return err
+	}
+	original := bid.Description
+
+	bids, err := h.Client.GetMyBids(ctx, author.Username, client.Page{})
+	if err != nil {
+		return fmt.Errorf("get my bids: %w", err)
+	}
+	err = check(hasBid(bids, bid.Id), "my bids: bid %s is missing", bid.Id)
+	if err != nil {
+		return err
+	}
+
+	bids, err = h.Client.GetBidsForTender(ctx, tender.Id, owner.Username, client.Page{})
+	if err != nil {
+		return fmt.Errorf("get tender bids: %w", err)
+	}
+	err = check(hasBid(bids, bid.Id), "tender bids: bid %s is missing", bid.Id)
+	if err != nil {
+		return err
+	}
+
+	bids, err = h.Client.GetBidsForTenderStage(
+		ctx, tender.Id, model.PrequalificationBidStage, owner.Username, client.Page{},
+	)
+	if err != nil {
+		return fmt.Errorf("get prequalification bids: %w", err)
+	}
+	err = check(len(bids) == 0, "prequalification bids: expected none, got %d", len(bids))
+	if err != nil {
+		return err
+	}
+
+	status, err := h.Client.GetBidStatus(ctx, bid.Id, owner.Username)
+	if err != nil {
+		return fmt.Errorf("get bid status: %w", err)
+	}
+	err = check(status == model.CreatedBidStatus, "bid status: expected Created, got %s", status)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.GetBidStatus(ctx, bid.Id, outsider.Username)
+	err = expectError(err, client.ErrForbidden, "get bid status by outsider")
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.UpdateBidStatus(ctx, bid.Id, model.PublishedBidStatus, owner.Username)
+	if err != nil {
+		return fmt.Errorf("publish bid: %w", err)
+	}
+
+	bid, err = h.Client.EditBid(ctx, bid.Id, owner.Username, client.BidUpdate{
+		Description: "edited bid",
+	})
+	if err != nil {
+		return fmt.Errorf("edit bid: %w", err)
+	}
+	err = check(bid.Version == 3, "edited bid: expected version 3, got %d", bid.Version)
+	if err != nil {
+		return err
+	}
+
+	history, err := h.Client.GetBidHistory(ctx, bid.Id, owner.Username)
+	if err != nil {
+		return fmt.Errorf("get bid history: %w", err)
+	}
+	err = check(len(history) == 3, "bid history: expected 3 versions, got %d", len(history))
+	if err != nil {
+		return err
+	}
+
+	bid, err = h.Client.RollbackBid(ctx, bid.Id, 1, owner.Username)
+	if err != nil {
+		return fmt.Errorf("rollback bid: %w", err)
+	}
+	return check(bid.Version == 4 && bid.Description == original &&
+		bid.Status == model.CreatedBidStatus,
+		"rolled back bid: version %d, status %s, description %q",
+		bid.Version, bid.Status, bid.Description)
+}
+
+func quorumScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 3)
+	if err != nil {
+		return err
+	}
+	bidder, err := h.newCustomer(ctx, 3)
+	if err != nil {
+		return err
+	}
+
+	tender, err := h.publishedTender(ctx, c, false)
+	if err != nil {
+		return err
+	}
+
+	bid, err := h.Client.CreateBid(ctx, client.NewBid{
+		Name:            uniqueName("e2e bid"),
+		Description:     "organization bid",
+		TenderId:        tender.Id,
+		AuthorType:      model.OrgBidAuthorType,
+		AuthorId:        bidder.org.Id,
+		CreatorUsername: bidder.owner().Username,
+	})
+	if err != nil {
+		return fmt.Errorf("create bid: %w", err)
+	}
+
+	_, err = h.Client.SubmitBidDecision(
+		ctx, bid.Id, client.DecisionApproved, bidder.users[1].Username,
+	)
+	err = expectError(err, client.ErrForbidden, "decision by bidder")
+	if err != nil {
+		return err
+	}
+
+	for i, user := range c.users {
+		err = h.tenderStatus(ctx, tender.Id, user.Username, model.TenderStatusPublished)
+		if err != nil {
+			return fmt.Errorf("before approval %d: %w", i+1, err)
+		}
+		_, err = h.Client.SubmitBidDecision(ctx, bid.Id, client.DecisionApproved, user.Username)
+		if err != nil {
+			return fmt.Errorf("approval %d: %w", i+1, err)
+		}
+	}
+
+	err = h.tenderStatus(ctx, tender.Id, c.owner().Username, model.TenderStatusClosed)
+	if err != nil {
+		return fmt.Errorf("after quorum: %w", err)
+	}
+
+	reputation, err := h.Client.GetBidderReputation(
+		ctx, model.OrgBidAuthorType, bidder.org.Id, 0, c.owner().Username,
+	)
+	if err != nil {
+		return fmt.Errorf("get reputation: %w", err)
+	}
+	return check(reputation.Wins == 1, "reputation: expected 1 win, got %d", reputation.Wins)
+}
+
+func rejectionScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 2)
+	if err != nil {
+		return err
+	}
+	bidder, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	tender, err := h.publishedTender(ctx, c, false)
+	if err != nil {
+		return err
+	}
+	bid, err := h.createUserBid(ctx, tender.Id, bidder.owner())
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.SubmitBidDecision(ctx, bid.Id, client.DecisionRejected, c.users[0].Username)
+	if err != nil {
+		return fmt.Errorf("reject bid: %w", err)
+	}
+
+	_, err = h.Client.SubmitBidDecision(ctx, bid.Id, client.DecisionApproved, c.users[1].Username)
+	err = expectFailure(err, "approve rejected bid")
+	if err != nil {
+		return err
+	}
+
+	return h.tenderStatus(ctx, tender.Id, c.owner().Username, model.TenderStatusPublished)
+}
@@ -0,0 +1,205 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+	"avi/pkg/client"
+)
+
+func templatesScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	outsider, err := h.CreateEmployee(ctx)
+	if err != nil {
+		return err
+	}
+	owner := c.owner()
+
+	_, err = h.Client.CreateTemplate(ctx, outsider.Username, client.NewTemplate{
+		Name:           uniqueName("e2e template"),
+		ServiceType:    "Manufacture",
+		OrganizationId: c.org.Id,
+	})
+	err = expectError(err, client.ErrForbidden, "create template by outsider")
+	if err != nil {
+		return err
+	}
+
+	template, err := h.Client.CreateTemplate(ctx, owner.Username, client.NewTemplate{
+		Name:           uniqueName("e2e template"),
+		Description:    "end-to-end template",
+		ServiceType:    "Manufacture",
+		OrganizationId: c.org.Id,
+		Criteria:       []string{"price"},
+	})
+	if err != nil {
+		return fmt.Errorf("create template: %w", err)
+	}
+
+	templates, err := h.Client.GetTemplates(ctx, owner.Username)
+	if err != nil {
+		return fmt.Errorf("get templates: %w", err)
+	}
+	err = check(slices.ContainsFunc(templates, func(t *model.TenderTemplate) bool {
+		return t.Id == template.Id
+	}), "templates: template %s is missing", template.Id)
+	if err != nil {
+		return err
+	}
+
+	template, err = h.Client.EditTemplate(ctx, template.Id, owner.Username, client.TemplateUpdate{
+		Criteria: []string{"price", "quality"},
+	})
+	if err != nil {
+		return fmt.Errorf("edit template: %w", err)
+	}
+
+	err = checkAttachments(attachmentOps{
+		object: "template",
+		upload: func(fileName string, content io.Reader) (*model.Attachment, error) {
+			return h.Client.UploadTemplateAttachment(ctx, template.Id, owner.Username, fileName, content)
+		},
+		list: func() ([]*model.Attachment, error) {
+			return h.Client.GetTemplateAttachments(ctx, template.Id, owner.Username)
+		},
+		download: func(id uuid.UUID) (*client.AttachmentContent, error) {
+			return h.Client.DownloadTemplateAttachment(ctx, template.Id, id, owner.Username)
+		},
+		remove: func(id uuid.UUID) error {
+			_, err := h.Client.DeleteTemplateAttachment(ctx, template.Id, id, owner.Username)
+			return err
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	tender, err := h.Client.CreateTenderFromTemplate(
+		ctx, template.Id, owner.Username, client.TemplateTender{Name: uniqueName("e2e tender")},
+	)
+	if err != nil {
+		return fmt.Errorf("create tender from template: %w", err)
+	}
+	err = check(tender.Status == model.TenderStatusCreated &&
+		tender.ServiceType == template.ServiceType &&
+		slices.Equal(tender.Criteria, template.Criteria),
+		"tender from template: status %s, service type %s, criteria %v",
+		tender.Status, tender.ServiceType, tender.Criteria)
+	if err != nil {
+		return err
+	}
+
+	err = h.Client.DeleteTemplate(ctx, template.Id, owner.Username)
+	if err != nil {
+		return fmt.Errorf("delete template: %w", err)
+	}
+
+	_, err = h.Client.CreateTenderFromTemplate(
+		ctx, template.Id, owner.Username, client.TemplateTender{Name: uniqueName("e2e tender")},
+	)
+	return expectError(err, client.ErrNotFound, "create tender from deleted template")
+}
+
+func serviceTypesScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	owner := c.owner()
+
+	parent := model.TenderServiceType("Construction")
+	_, err = h.Client.CreateServiceType(ctx, owner.Username, client.NewServiceType{
+		Code:       model.TenderServiceType(uniqueName("E2E")),
+		Name:       "not allowed",
+		ParentCode: &parent,
+	})
+	err = expectError(err, client.ErrForbidden, "create service type by non-admin")
+	if err != nil {
+		return err
+	}
+
+	var children []*model.ServiceType
+	for range 2 {
+		serviceType, err := h.Client.CreateServiceType(ctx, h.Admin.Username, client.NewServiceType{
+			Code:       model.TenderServiceType(uniqueName("E2E")),
+			Name:       "end-to-end category",
+			ParentCode: &parent,
+		})
+		if err != nil {
+			return fmt.Errorf("create service type: %w", err)
+		}
+		children = append(children, serviceType)
+	}
+	used, unused := children[0], children[1]
+
+	serviceTypes, err := h.Client.GetServiceTypes(ctx, parent)
+	if err != nil {
+		return fmt.Errorf("get service types: %w", err)
+	}
+	err = check(slices.ContainsFunc(serviceTypes, func(serviceType *model.ServiceType) bool {
+		return serviceType.Code == used.Code
+	}), "service types: %s is missing under %s", used.Code, parent)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.EditServiceType(ctx, used.Code, h.Admin.Username, client.ServiceTypeUpdate{
+		Name: "renamed category",
+	})
+	if err != nil {
+		return fmt.Errorf("edit service type: %w", err)
+	}
+
+	tender, err := h.createTender(ctx, c, used.Code, false)
+	if err != nil {
+		return err
+	}
+	_, err = h.Client.UpdateTenderStatus(ctx, tender.Id, model.TenderStatusPublished, owner.Username)
+	if err != nil {
+		return fmt.Errorf("publish tender: %w", err)
+	}
+
+	tenders, err := h.Client.GetTenders(ctx, used.Code, client.Page{})
+	if err != nil {
+		return fmt.Errorf("get tenders: %w", err)
+	}
+	err = check(len(tenders) == 1 && tenders[0].Id == tender.Id,
+		"tenders of %s: expected only %s, got %d", used.Code, tender.Id, len(tenders))
+	if err != nil {
+		return err
+	}
+
+	tenders, err = h.Client.GetVisibleTenders(ctx, used.Code, owner.Username, client.Page{})
+	if err != nil {
+		return fmt.Errorf("get visible tenders: %w", err)
+	}
+	err = check(hasTender(tenders, tender.Id), "visible tenders: tender %s is missing", tender.Id)
+	if err != nil {
+		return err
+	}
+
+	tenders, err = h.Client.GetTendersInCategory(ctx, parent, client.Page{Limit: 50})
+	if err != nil {
+		return fmt.Errorf("get tenders in category: %w", err)
+	}
+	err = check(len(tenders) > 0, "tenders in %s: expected some", parent)
+	if err != nil {
+		return err
+	}
+
+	err = h.Client.DeleteServiceType(ctx, used.Code, h.Admin.Username)
+	err = expectError(err, client.ErrConflict, "delete used service type")
+	if err != nil {
+		return err
+	}
+
+	return h.Client.DeleteServiceType(ctx, unused.Code, h.Admin.Username)
+}
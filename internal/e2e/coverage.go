@@ -0,0 +1,64 @@
+package e2e
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi"
+)
+
+type coverage struct {
+	mu     sync.Mutex
+	router chi.Router
+	hits   map[string]int
+}
+
+func newCoverage(router chi.Router) *coverage {
+	return &coverage{router: router, hits: map[string]int{}}
+}
+
+func (c *coverage) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rctx := chi.NewRouteContext()
+		rctx.Routes = c.router
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		next.ServeHTTP(w, r)
+
+		pattern := rctx.RoutePattern()
+		if pattern == "" {
+			return
+		}
+		c.mu.Lock()
+		c.hits[routeKey(r.Method, pattern)] += 1
+		c.mu.Unlock()
+	})
+}
+
+func (c *coverage) uncovered() ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var routes []string
+	err := chi.Walk(c.router, func(
+		method string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler,
+	) error {
+		key := routeKey(method, route)
+		if c.hits[key] == 0 {
+			routes = append(routes, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(routes)
+	return slices.Compact(routes), nil
+}
+
+func routeKey(method string, pattern string) string {
+	return method + " " + strings.TrimSuffix(pattern, "/")
+}
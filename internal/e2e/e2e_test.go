@@ -0,0 +1,104 @@
+package e2e
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var (
+	postgresConn = flag.String("e2e.postgres-conn", os.Getenv("E2E_POSTGRES_CONN"),
+		"use an existing postgres server instead of embedded one")
+	binaries = flag.String("e2e.binaries", os.Getenv("E2E_POSTGRES_BINARIES"),
+		"path to unpacked postgres binaries for embedded server")
+	cache = flag.String("e2e.cache", os.Getenv("E2E_POSTGRES_CACHE"),
+		"path to postgres binaries archive cache for embedded server")
+	port     = flag.Uint("e2e.port", 0, "embedded postgres port (default random)")
+	fixtures = flag.String("e2e.fixtures", filepath.Join("..", "..", "init-mock-db.sql"),
+		"path to SQL fixtures")
+	startTimeout = flag.Duration("e2e.start-timeout", 5*time.Minute,
+		"timeout for starting postgres and applying schema")
+	optional = flag.Bool("e2e.optional", envBool("E2E_OPTIONAL"),
+		"skip scenarios instead of failing when the harness can not start")
+)
+
+var harness *Harness
+
+var startErr error
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if testing.Short() {
+		os.Exit(m.Run())
+	}
+
+	var logger io.Writer
+	if testing.Verbose() {
+		logger = os.Stderr
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *startTimeout)
+	harness, startErr = Start(ctx, Config{
+		PostgresConn: *postgresConn,
+		Port:         uint32(*port),
+		BinariesPath: *binaries,
+		CachePath:    *cache,
+		FixturesPath: *fixtures,
+		Logger:       logger,
+	})
+	cancel()
+
+	code := m.Run()
+	if harness != nil {
+		err := harness.Close()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "e2e: close harness:", err)
+		}
+	}
+	os.Exit(code)
+}
+
+func envBool(name string) bool {
+	value, _ := strconv.ParseBool(os.Getenv(name))
+	return value
+}
+
+func TestScenarios(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping end-to-end scenarios in short mode")
+	}
+	if startErr != nil && *optional {
+		t.Skipf("can not start end-to-end harness: %v", startErr)
+	}
+	if startErr != nil {
+		t.Fatalf("can not start end-to-end harness: %v", startErr)
+	}
+
+	ran := 0
+	for _, scenario := range Scenarios {
+		t.Run(scenario.Name, func(t *testing.T) {
+			ran += 1
+			err := scenario.Run(context.Background(), harness)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	if ran < len(Scenarios) {
+		return
+	}
+	uncovered, err := harness.UncoveredRoutes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, route := range uncovered {
+		t.Errorf("route is not covered by any scenario: %s", route)
+	}
+}
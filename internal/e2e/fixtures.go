@@ -0,0 +1,86 @@
+package e2e
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+func (h *Harness) CreateEmployee(ctx context.Context) (*model.User, error) {
+	return h.CreateNamedEmployee(ctx, uniqueName("e2e_user"))
+}
+
+func (h *Harness) CreateNamedEmployee(ctx context.Context, username string) (*model.User, error) {
+	user := &model.User{
+		Username:  username,
+		FirstName: "E2E",
+		LastName:  username,
+	}
+	query := `INSERT INTO employee (username, first_name, last_name)
+	VALUES ($1, $2, $3)
+	RETURNING id, created_at, updated_at;`
+	err := h.DB.QueryRowContext(ctx, query, user.Username, user.FirstName, user.LastName).
+		Scan(&user.Id, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (h *Harness) CreateOrganization(
+	ctx context.Context, responsibles ...*model.User,
+) (*model.Organization, error) {
+	org := &model.Organization{
+		Name:             uniqueName("e2e org"),
+		Description:      "end-to-end fixture",
+		OrganizationType: model.OrgTypeLLC,
+	}
+	query := `INSERT INTO organization (name, description, type)
+	VALUES ($1, $2, $3)
+	RETURNING id, created_at, updated_at;`
+	err := h.DB.QueryRowContext(ctx, query, org.Name, org.Description, org.OrganizationType).
+		Scan(&org.Id, &org.CreatedAt, &org.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range responsibles {
+		err = h.AddResponsible(ctx, org.Id, user.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return org, nil
+}
+
+func (h *Harness) AddResponsible(ctx context.Context, orgId uuid.UUID, userId uuid.UUID) error {
+	query := `INSERT INTO organization_responsible (organization_id, user_id)
+	VALUES ($1, $2);`
+	_, err := h.DB.ExecContext(ctx, query, orgId, userId)
+	return err
+}
+
+func (h *Harness) CreateTeam(
+	ctx context.Context, size int,
+) (*model.Organization, []*model.User, error) {
+	users := make([]*model.User, 0, size)
+	for range size {
+		user, err := h.CreateEmployee(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		users = append(users, user)
+	}
+
+	org, err := h.CreateOrganization(ctx, users...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return org, users, nil
+}
+
+func uniqueName(prefix string) string {
+	return prefix + "_" + uuid.NewString()[:8]
+}
@@ -0,0 +1,234 @@
+package e2e
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"avi/internal/api/openapi"
+	"avi/internal/api/router"
+	"avi/internal/config"
+	"avi/internal/database"
+	"avi/internal/model"
+	"avi/internal/schema"
+	"avi/internal/service/attachment"
+	"avi/internal/service/bid"
+	"avi/internal/service/servicetype"
+	"avi/internal/storage"
+	"avi/pkg/client"
+)
+
+type Config struct {
+	PostgresConn string
+	Port         uint32
+	BinariesPath string
+	CachePath    string
+	FixturesPath string
+	Logger       io.Writer
+}
+
+type Harness struct {
+	Server   *httptest.Server
+	Client   *client.Client
+	DB       *sql.DB
+	Admin    *model.User
+	coverage *coverage
+	cleanup  []func() error
+}
+
+func Start(ctx context.Context, cfg Config) (h *Harness, err error) {
+	h = &Harness{}
+	defer func() {
+		if err != nil {
+			h.Close()
+			h = nil
+		}
+	}()
+
+	dir, err := os.MkdirTemp("", "avi-e2e-*")
+	if err != nil {
+		return
+	}
+	h.cleanup = append(h.cleanup, func() error {
+		return os.RemoveAll(dir)
+	})
+
+	var connStr string
+	if cfg.PostgresConn != "" {
+		connStr, err = h.createDatabase(ctx, cfg.PostgresConn)
+	} else {
+		connStr, err = h.startPostgres(cfg, filepath.Join(dir, "postgres"))
+	}
+	if err != nil {
+		return
+	}
+
+	adminUsername := "e2e_admin_" + uuid.NewString()[:8]
+	serverCfg := config.Default()
+	serverCfg.Postgres.Conn = connStr
+	serverCfg.Storage.LocalDir = filepath.Join(dir, "attachments")
+	serverCfg.Admin.Usernames = []string{adminUsername}
+
+	h.DB, err = database.Open(ctx, serverCfg.Postgres)
+	if err != nil {
+		return
+	}
+
+	blobStorage, err := storage.New(serverCfg.Storage)
+	if err != nil {
+		return
+	}
+	attachment.Setup(serverCfg.Attachments, blobStorage)
+	bid.Setup(serverCfg.RateLimit)
+	servicetype.Setup(serverCfg.Admin)
+
+	err = h.applyFixtures(ctx, cfg.FixturesPath)
+	if err != nil {
+		return
+	}
+
+	err = schema.Apply()
+	if err != nil {
+		return
+	}
+
+	h.Admin, err = h.CreateNamedEmployee(ctx, adminUsername)
+	if err != nil {
+		return
+	}
+
+	r := router.New()
+	err = openapi.CheckRoutes(r)
+	if err != nil {
+		return
+	}
+	h.coverage = newCoverage(r)
+	h.Server = httptest.NewServer(h.coverage.middleware(r))
+
+	h.Client, err = client.New(
+		h.Server.URL,
+		client.WithHTTPClient(h.Server.Client()),
+		client.WithRetries(0, 0),
+	)
+	return
+}
+
+func (h *Harness) Close() error {
+	if h.Server != nil {
+		h.Server.Close()
+	}
+	if h.DB != nil {
+		database.Close()
+	}
+
+	var errs []error
+	for i := len(h.cleanup) - 1; i >= 0; i-- {
+		errs = append(errs, h.cleanup[i]())
+	}
+	h.cleanup = nil
+	return errors.Join(errs...)
+}
+
+func (h *Harness) UncoveredRoutes() ([]string, error) {
+	return h.coverage.uncovered()
+}
+
+func (h *Harness) startPostgres(cfg Config, runtimePath string) (string, error) {
+	port := cfg.Port
+	if port == 0 {
+		var err error
+		port, err = freePort()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = io.Discard
+	}
+	pgConfig := embeddedpostgres.DefaultConfig().
+		Port(port).
+		Database("avi").
+		Username("avi").
+		Password("avi").
+		RuntimePath(runtimePath).
+		Logger(logger)
+	if cfg.BinariesPath != "" {
+		pgConfig = pgConfig.BinariesPath(cfg.BinariesPath)
+	}
+	if cfg.CachePath != "" {
+		pgConfig = pgConfig.CachePath(cfg.CachePath)
+	}
+
+	postgres := embeddedpostgres.NewDatabase(pgConfig)
+	err := postgres.Start()
+	if err != nil {
+		return "", fmt.Errorf("can not start embedded postgres: %w", err)
+	}
+	h.cleanup = append(h.cleanup, postgres.Stop)
+	return pgConfig.GetConnectionURL() + "?sslmode=disable", nil
+}
+
+func (h *Harness) createDatabase(ctx context.Context, connStr string) (string, error) {
+	u, err := url.Parse(connStr)
+	if err != nil || u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return "", errors.New("postgres connection must be a postgres:// url")
+	}
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return "", err
+	}
+
+	name := "avi_e2e_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+	_, err = db.ExecContext(ctx, "CREATE DATABASE "+pq.QuoteIdentifier(name)+";")
+	if err != nil {
+		db.Close()
+		return "", fmt.Errorf("can not create database: %w", err)
+	}
+	h.cleanup = append(h.cleanup, func() error {
+		defer db.Close()
+		_, err := db.Exec("DROP DATABASE " + pq.QuoteIdentifier(name) + " WITH (FORCE);")
+		return err
+	})
+
+	u.Path = "/" + name
+	return u.String(), nil
+}
+
+func (h *Harness) applyFixtures(ctx context.Context, path string) error {
+	if path == "" {
+		path = "init-mock-db.sql"
+	}
+	fixtures, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can not read fixtures: %w", err)
+	}
+	_, err = h.DB.ExecContext(ctx, string(fixtures))
+	if err != nil {
+		return fmt.Errorf("can not apply fixtures: %w", err)
+	}
+	return nil
+}
+
+func freePort() (uint32, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return uint32(listener.Addr().(*net.TCPAddr).Port), nil
+}
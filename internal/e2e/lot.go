@@ -0,0 +1,113 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	"avi/internal/model"
+	"avi/pkg/client"
+)
+
+func lotsScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	bidder, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	owner, author := c.owner(), bidder.owner()
+
+	tender, err := h.createTender(ctx, c, "Construction", false)
+	if err != nil {
+		return err
+	}
+
+	budget := 1000.0
+	var lots []*model.Lot
+	for i := range 2 {
+		lot, err := h.Client.CreateLot(ctx, tender.Id, owner.Username, client.NewLot{
+			Name:        fmt.Sprintf("lot %d", i+1),
+			Description: "end-to-end lot",
+			Quantity:    i + 1,
+			Budget:      &budget,
+		})
+		if err != nil {
+			return fmt.Errorf("create lot %d: %w", i+1, err)
+		}
+		lots = append(lots, lot)
+	}
+
+	_, err = h.Client.UpdateTenderStatus(ctx, tender.Id, model.TenderStatusPublished, owner.Username)
+	if err != nil {
+		return fmt.Errorf("publish tender: %w", err)
+	}
+	_, err = h.Client.CreateLot(ctx, tender.Id, owner.Username, client.NewLot{
+		Name:        "late lot",
+		Description: "end-to-end lot",
+		Quantity:    1,
+	})
+	err = expectFailure(err, "lot on published tender")
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.CancelLot(ctx, tender.Id, lots[1].Id, owner.Username)
+	if err != nil {
+		return fmt.Errorf("cancel lot: %w", err)
+	}
+
+	_, err = h.createUserBid(ctx, tender.Id, author)
+	err = expectFailure(err, "bid without lots")
+	if err != nil {
+		return err
+	}
+	_, err = h.createUserBid(ctx, tender.Id, author, lots[1].Id)
+	err = expectFailure(err, "bid on canceled lot")
+	if err != nil {
+		return err
+	}
+
+	bid, err := h.createUserBid(ctx, tender.Id, author, lots[0].Id)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.SubmitBidLotDecision(
+		ctx, bid.Id, lots[0].Id, client.DecisionApproved, owner.Username,
+	)
+	if err != nil {
+		return fmt.Errorf("approve lot: %w", err)
+	}
+
+	lots, err = h.Client.GetLots(ctx, tender.Id, owner.Username)
+	if err != nil {
+		return fmt.Errorf("get lots: %w", err)
+	}
+	err = check(len(lots) == 2, "lots: expected 2, got %d", len(lots))
+	if err != nil {
+		return err
+	}
+	for _, lot := range lots {
+		switch lot.Status {
+		case model.AwardedLotStatus:
+			err = check(lot.AwardedBidId != nil && *lot.AwardedBidId == bid.Id,
+				"awarded lot %s: unexpected bid %v", lot.Id, lot.AwardedBidId)
+		case model.CanceledLotStatus:
+		default:
+			err = fmt.Errorf("lot %s: unexpected status %s", lot.Id, lot.Status)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = h.Client.CancelLot(ctx, tender.Id, bid.LotIds[0], owner.Username)
+	err = expectError(err, client.ErrConflict, "cancel awarded lot")
+	if err != nil {
+		return err
+	}
+
+	return h.tenderStatus(ctx, tender.Id, owner.Username, model.TenderStatusClosed)
+}
@@ -0,0 +1,48 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func metaScenario(ctx context.Context, h *Harness) error {
+	err := h.Client.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+
+	spec, err := h.get(ctx, "/api/openapi.json")
+	if err != nil {
+		return err
+	}
+	if !json.Valid(spec) {
+		return fmt.Errorf("openapi.json: invalid json")
+	}
+
+	_, err = h.get(ctx, "/api/docs")
+	return err
+}
+
+func (h *Harness) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.Server.URL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := h.Server.Client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", path, err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", path, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get %s: status %d", path, res.StatusCode)
+	}
+	return body, nil
+}
@@ -0,0 +1,120 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	"avi/internal/model"
+	"avi/pkg/client"
+)
+
+func reviewsScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 2)
+	if err != nil {
+		return err
+	}
+	bidder, err := h.newCustomer(ctx, 1)
+	if err != nil {
+		return err
+	}
+	reviewer, colleague, author := c.users[0], c.users[1], bidder.owner()
+
+	tender, err := h.publishedTender(ctx, c, false)
+	if err != nil {
+		return err
+	}
+	bid, err := h.createUserBid(ctx, tender.Id, author)
+	if err != nil {
+		return err
+	}
+
+	invalid := 7
+	_, err = h.Client.SubmitBidFeedback(ctx, bid.Id, client.Feedback{
+		Description: "out of range",
+		Rating:      &invalid,
+	}, reviewer.Username)
+	err = expectError(err, client.ErrBadRequest, "feedback with incorrect rating")
+	if err != nil {
+		return err
+	}
+
+	rating := 4
+	_, err = h.Client.SubmitBidFeedback(ctx, bid.Id, client.Feedback{
+		Description: "solid work",
+		Rating:      &rating,
+		Tags:        []model.ReviewTag{model.QualityReviewTag, model.DeadlinesReviewTag},
+	}, reviewer.Username)
+	if err != nil {
+		return fmt.Errorf("submit feedback: %w", err)
+	}
+
+	reviews, err := h.Client.GetBidReviews(
+		ctx, tender.Id, author.Username, colleague.Username, client.Page{},
+	)
+	if err != nil {
+		return fmt.Errorf("get reviews: %w", err)
+	}
+	err = check(len(reviews) == 1, "reviews: expected 1, got %d", len(reviews))
+	if err != nil {
+		return err
+	}
+	review := reviews[0]
+
+	rating = 5
+	_, err = h.Client.EditBidReview(ctx, bid.Id, review.Id, client.ReviewUpdate{
+		Rating: &rating,
+	}, colleague.Username)
+	err = expectError(err, client.ErrForbidden, "edit review by another user")
+	if err != nil {
+		return err
+	}
+
+	review, err = h.Client.EditBidReview(ctx, bid.Id, review.Id, client.ReviewUpdate{
+		Description: "excellent work",
+		Rating:      &rating,
+	}, reviewer.Username)
+	if err != nil {
+		return fmt.Errorf("edit review: %w", err)
+	}
+	err = check(review.Version == 2, "edited review: expected version 2, got %d", review.Version)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.ReplyToBidReview(ctx, bid.Id, review.Id, "thank you", reviewer.Username)
+	err = expectError(err, client.ErrForbidden, "reply by reviewer")
+	if err != nil {
+		return err
+	}
+	_, err = h.Client.ReplyToBidReview(ctx, bid.Id, review.Id, "thank you", author.Username)
+	if err != nil {
+		return fmt.Errorf("reply to review: %w", err)
+	}
+
+	reputation, err := h.Client.GetBidderReputation(
+		ctx, model.UserBidAuthorType, author.Id, 5, colleague.Username,
+	)
+	if err != nil {
+		return fmt.Errorf("get reputation: %w", err)
+	}
+	err = check(reputation.Ratings == 1 && reputation.AverageRating != nil &&
+		*reputation.AverageRating == 5 && len(reputation.RecentReviews) == 1,
+		"reputation: %d ratings, %d recent reviews", reputation.Ratings,
+		len(reputation.RecentReviews))
+	if err != nil {
+		return err
+	}
+
+	err = h.Client.DeleteBidReview(ctx, bid.Id, review.Id, reviewer.Username)
+	if err != nil {
+		return fmt.Errorf("delete review: %w", err)
+	}
+
+	reviews, err = h.Client.GetBidReviews(
+		ctx, tender.Id, author.Username, reviewer.Username, client.Page{},
+	)
+	if err != nil {
+		return fmt.Errorf("get reviews after delete: %w", err)
+	}
+	return check(len(reviews) == 0, "reviews after delete: expected none, got %d", len(reviews))
+}
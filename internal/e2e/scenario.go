@@ -0,0 +1,148 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+	"avi/pkg/client"
+)
+
+type Scenario struct {
+	Name string
+	Run  func(ctx context.Context, h *Harness) error
+}
+
+var Scenarios = []Scenario{
+	{Name: "meta", Run: metaScenario},
+	{Name: "tender lifecycle", Run: tenderLifecycleScenario},
+	{Name: "bid lifecycle", Run: bidLifecycleScenario},
+	{Name: "quorum approval closes tender", Run: quorumScenario},
+	{Name: "rejection blocks decisions", Run: rejectionScenario},
+	{Name: "reviews and reputation", Run: reviewsScenario},
+	{Name: "lots", Run: lotsScenario},
+	{Name: "invite-only tender", Run: invitationsScenario},
+	{Name: "prequalification shortlist", Run: shortlistScenario},
+	{Name: "questions", Run: questionsScenario},
+	{Name: "attachments", Run: attachmentsScenario},
+	{Name: "templates", Run: templatesScenario},
+	{Name: "service types", Run: serviceTypesScenario},
+}
+
+type customer struct {
+	org   *model.Organization
+	users []*model.User
+}
+
+func (h *Harness) newCustomer(ctx context.Context, size int) (*customer, error) {
+	org, users, err := h.CreateTeam(ctx, size)
+	if err != nil {
+		return nil, fmt.Errorf("create team: %w", err)
+	}
+	return &customer{org: org, users: users}, nil
+}
+
+func (c *customer) owner() *model.User {
+	return c.users[0]
+}
+
+func (h *Harness) createTender(
+	ctx context.Context, c *customer, serviceType model.TenderServiceType, inviteOnly bool,
+) (*model.Tender, error) {
+	tender, err := h.Client.CreateTender(ctx, client.NewTender{
+		Name:            uniqueName("e2e tender"),
+		Description:     "end-to-end tender",
+		ServiceType:     serviceType,
+		OrganizationId:  c.org.Id,
+		CreatorUsername: c.owner().Username,
+		InviteOnly:      inviteOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create tender: %w", err)
+	}
+	return tender, nil
+}
+
+func (h *Harness) publishedTender(
+	ctx context.Context, c *customer, inviteOnly bool,
+) (*model.Tender, error) {
+	tender, err := h.createTender(ctx, c, "Construction", inviteOnly)
+	if err != nil {
+		return nil, err
+	}
+	tender, err = h.Client.UpdateTenderStatus(
+		ctx, tender.Id, model.TenderStatusPublished, c.owner().Username,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("publish tender: %w", err)
+	}
+	return tender, nil
+}
+
+func (h *Harness) createUserBid(
+	ctx context.Context, tenderId uuid.UUID, author *model.User, lotIds ...uuid.UUID,
+) (*model.Bid, error) {
+	bid, err := h.Client.CreateBid(ctx, client.NewBid{
+		Name:            uniqueName("e2e bid"),
+		Description:     "end-to-end bid",
+		TenderId:        tenderId,
+		AuthorType:      model.UserBidAuthorType,
+		AuthorId:        author.Id,
+		CreatorUsername: author.Username,
+		LotIds:          lotIds,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create bid: %w", err)
+	}
+	return bid, nil
+}
+
+func (h *Harness) tenderStatus(
+	ctx context.Context, tenderId uuid.UUID, username string, want model.TenderStatus,
+) error {
+	status, err := h.Client.GetTenderStatus(ctx, tenderId, username)
+	if err != nil {
+		return fmt.Errorf("get tender status: %w", err)
+	}
+	if status != want {
+		return fmt.Errorf("tender status: expected %s, got %s", want, status)
+	}
+	return nil
+}
+
+func expectError(err error, target error, action string) error {
+	if !errors.Is(err, target) {
+		return fmt.Errorf("%s: expected %v, got %v", action, target, err)
+	}
+	return nil
+}
+
+func expectFailure(err error, action string) error {
+	if err == nil {
+		return fmt.Errorf("%s: expected error, got success", action)
+	}
+	return nil
+}
+
+func check(ok bool, format string, args ...any) error {
+	if !ok {
+		return fmt.Errorf(format, args...)
+	}
+	return nil
+}
+
+func hasTender(tenders []*model.Tender, id uuid.UUID) bool {
+	return slices.ContainsFunc(tenders, func(tender *model.Tender) bool {
+		return tender.Id == id
+	})
+}
+
+func hasBid(bids []*model.Bid, id uuid.UUID) bool {
+	return slices.ContainsFunc(bids, func(bid *model.Bid) bool {
+		return bid.Id == id
+	})
+}
@@ -0,0 +1,118 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+
+	"avi/internal/model"
+	"avi/pkg/client"
+)
+
+func tenderLifecycleScenario(ctx context.Context, h *Harness) error {
+	c, err := h.newCustomer(ctx, 2)
+	if err != nil {
+		return err
+	}
+	outsider, err := h.CreateEmployee(ctx)
+	if err != nil {
+		return err
+	}
+	owner, colleague := c.users[0], c.users[1]
+
+	tender, err := h.createTender(ctx, c, "Construction", false)
+	if err != nil {
+		return err
+	}
+	err = check(tender.Status == model.TenderStatusCreated && tender.Version == 1,
+		"new tender: status %s, version %d", tender.Status, tender.Version)
+	if err != nil {
+		return err
+	}
+	original := tender.Description
+
+	tenders, err := h.Client.GetMyTenders(ctx, owner.Username, client.Page{Limit: 50})
+	if err != nil {
+		return fmt.Errorf("get my tenders: %w", err)
+	}
+	err = check(hasTender(tenders, tender.Id), "my tenders: tender %s is missing", tender.Id)
+	if err != nil {
+		return err
+	}
+
+	err = h.tenderStatus(ctx, tender.Id, owner.Username, model.TenderStatusCreated)
+	if err != nil {
+		return err
+	}
+
+	tender, err = h.Client.EditTender(ctx, tender.Id, owner.Username, client.TenderUpdate{
+		Description: "edited by owner",
+	})
+	if err != nil {
+		return fmt.Errorf("edit tender: %w", err)
+	}
+	tender, err = h.Client.EditTender(ctx, tender.Id, colleague.Username, client.TenderUpdate{
+		Name: uniqueName("e2e tender renamed"),
+	})
+	if err != nil {
+		return fmt.Errorf("edit tender by colleague: %w", err)
+	}
+	err = check(tender.Version == 3, "edited tender: expected version 3, got %d", tender.Version)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.EditTender(ctx, tender.Id, outsider.Username, client.TenderUpdate{
+		Description: "edited by outsider",
+	})
+	err = expectError(err, client.ErrForbidden, "edit tender by outsider")
+	if err != nil {
+		return err
+	}
+
+	tender, err = h.Client.RollbackTender(ctx, tender.Id, 1, owner.Username)
+	if err != nil {
+		return fmt.Errorf("rollback tender: %w", err)
+	}
+	err = check(tender.Version == 4 && tender.Description == original,
+		"rolled back tender: version %d, description %q", tender.Version, tender.Description)
+	if err != nil {
+		return err
+	}
+
+	history, err := h.Client.GetTenderHistory(ctx, tender.Id, owner.Username)
+	if err != nil {
+		return fmt.Errorf("get tender history: %w", err)
+	}
+	err = check(len(history) == 4, "tender history: expected 4 versions, got %d", len(history))
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Client.CloneTender(ctx, tender.Id, owner.Username, "")
+	err = expectError(err, client.ErrConflict, "clone open tender")
+	if err != nil {
+		return err
+	}
+
+	for _, status := range []model.TenderStatus{
+		model.TenderStatusPublished, model.TenderStatusClosed,
+	} {
+		_, err = h.Client.UpdateTenderStatus(ctx, tender.Id, status, owner.Username)
+		if err != nil {
+			return fmt.Errorf("update tender status to %s: %w", status, err)
+		}
+	}
+
+	_, err = h.Client.GetTenderStatus(ctx, tender.Id, outsider.Username)
+	err = expectError(err, client.ErrForbidden, "get closed tender status by outsider")
+	if err != nil {
+		return err
+	}
+
+	clone, err := h.Client.CloneTender(ctx, tender.Id, owner.Username, "")
+	if err != nil {
+		return fmt.Errorf("clone tender: %w", err)
+	}
+	return check(clone.Id != tender.Id && clone.Status == model.TenderStatusCreated,
+		"cloned tender: id %s, status %s", clone.Id, clone.Status)
+}
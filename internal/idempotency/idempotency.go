@@ -0,0 +1,252 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+
+	"avi/internal/api/apierror"
+	"avi/internal/config"
+	"avi/internal/logging"
+	"avi/internal/model"
+	idempotencyRepo "avi/internal/repository/idempotency"
+)
+
+const KeyHeader = "Idempotency-Key"
+
+const ReplayedHeader = "Idempotent-Replayed"
+
+const maxKeyLength = 255
+
+const cleanupInterval = 10 * time.Minute
+
+var ErrorIncorrectKey = errors.New("idempotency key must not be longer than 255 characters")
+var ErrorKeyReused = errors.New("idempotency key is already used with a different request")
+var ErrorRequestInProgress = errors.New("request with this idempotency key is in progress")
+var ErrorRequestTooLarge = errors.New("request body is too large")
+
+var current atomic.Pointer[Guard]
+
+type Repository interface {
+	Acquire(
+		ctx context.Context,
+		key string,
+		username string,
+		method string,
+		path string,
+		requestHash string,
+		lockTimeout time.Duration,
+	) (bool, error)
+	GetRequest(
+		ctx context.Context, key string, username string, method string, path string,
+	) (*model.IdempotentRequest, error)
+	SaveResponse(
+		ctx context.Context,
+		key string,
+		username string,
+		method string,
+		path string,
+		statusCode int,
+		contentType string,
+		response []byte,
+	) error
+	Release(ctx context.Context, key string, username string, method string, path string) error
+	DeleteExpired(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type Guard struct {
+	repo        Repository
+	retention   time.Duration
+	lockTimeout time.Duration
+	maxBodySize int64
+}
+
+func New(cfg config.Idempotency, maxBodySize int64, repo Repository) *Guard {
+	return &Guard{
+		repo:        repo,
+		retention:   cfg.Retention,
+		lockTimeout: cfg.LockTimeout,
+		maxBodySize: maxBodySize,
+	}
+}
+
+func Setup(cfg config.Idempotency, attachments config.Attachments) (*Guard, error) {
+	if !cfg.Enabled {
+		current.Store(nil)
+		return nil, nil
+	}
+
+	repo, err := idempotencyRepo.NewRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	guard := New(cfg, attachments.MaxSize+1<<20, repo)
+	current.Store(guard)
+	return guard, nil
+}
+
+func (guard *Guard) Name() string {
+	return "idempotency-cleanup"
+}
+
+func (guard *Guard) Run(ctx context.Context) error {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			deleted, err := guard.repo.DeleteExpired(ctx, guard.retention)
+			if err != nil {
+				if ctx.Err() == nil {
+					logging.FromContext(ctx).Error("idempotency cleanup: " + err.Error())
+				}
+				continue
+			}
+			if deleted > 0 {
+				logging.FromContext(ctx).Info("expired idempotency keys are deleted", "count", deleted)
+			}
+		}
+	}
+}
+
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		guard := current.Load()
+		key := r.Header.Get(KeyHeader)
+		if guard == nil || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxKeyLength {
+			apierror.HandleError(w, r, ErrorIncorrectKey, http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, guard.maxBodySize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			apierror.HandleError(w, r, ErrorRequestTooLarge, http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			err = errors.New("can not read request body")
+			apierror.HandleError(w, r, err, http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		hash := requestHash(r, body)
+		username := requestUser(r, body)
+
+		acquired, err := guard.repo.Acquire(
+			r.Context(), key, username, r.Method, r.URL.Path, hash, guard.lockTimeout,
+		)
+		if err != nil {
+			logging.FromContext(r.Context()).Error(err.Error())
+			err = errors.New("can not check idempotency key")
+			apierror.HandleError(w, r, err, http.StatusInternalServerError)
+			return
+		}
+		if !acquired {
+			guard.replay(w, r, key, username, hash)
+			return
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		response := &bytes.Buffer{}
+		ww.Tee(response)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		ctx := context.WithoutCancel(r.Context())
+		if retryable(status) {
+			err = guard.repo.Release(ctx, key, username, r.Method, r.URL.Path)
+		} else {
+			err = guard.repo.SaveResponse(
+				ctx, key, username, r.Method, r.URL.Path, status, ww.Header().Get("Content-Type"), response.Bytes(),
+			)
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("can not store idempotent response: " + err.Error())
+		}
+	})
+}
+
+func (guard *Guard) replay(
+	w http.ResponseWriter, r *http.Request, key string, username string, hash string,
+) {
+	stored, err := guard.repo.GetRequest(r.Context(), key, username, r.Method, r.URL.Path)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logging.FromContext(r.Context()).Error(err.Error())
+		err = errors.New("can not check idempotency key")
+		apierror.HandleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	switch {
+	case stored != nil && stored.RequestHash != hash:
+		apierror.HandleError(w, r, ErrorKeyReused, http.StatusUnprocessableEntity)
+	case stored == nil || stored.StatusCode == nil:
+		w.Header().Set("Retry-After", "1")
+		apierror.HandleError(w, r, ErrorRequestInProgress, http.StatusConflict)
+	default:
+		if stored.ContentType != "" {
+			w.Header().Set("Content-Type", stored.ContentType)
+		}
+		w.Header().Set(ReplayedHeader, "true")
+		w.WriteHeader(*stored.StatusCode)
+		w.Write(stored.Response)
+	}
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + "\n" + r.URL.Path + "\n" + r.URL.Query().Encode() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func requestUser(r *http.Request, body []byte) string {
+	if username := r.URL.Query().Get("username"); username != "" {
+		return username
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return ""
+	}
+
+	var req struct {
+		CreatorUsername string `json:"creatorUsername"`
+		Username        string `json:"username"`
+	}
+	if json.Unmarshal(body, &req) != nil {
+		return ""
+	}
+	if req.CreatorUsername != "" {
+		return req.CreatorUsername
+	}
+	return req.Username
+}
+
+func retryable(status int) bool {
+	return status >= http.StatusInternalServerError ||
+		status == http.StatusTooManyRequests ||
+		status == apierror.StatusClientClosedRequest
+}
@@ -0,0 +1,268 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"avi/internal/config"
+	"avi/internal/model"
+)
+
+type memoryRepo struct {
+	mu       sync.Mutex
+	requests map[string]*model.IdempotentRequest
+}
+
+func (repo *memoryRepo) id(key string, username string, method string, path string) string {
+	return key + "\n" + username + "\n" + method + "\n" + path
+}
+
+func (repo *memoryRepo) Acquire(
+	ctx context.Context,
+	key string,
+	username string,
+	method string,
+	path string,
+	requestHash string,
+	lockTimeout time.Duration,
+) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	id := repo.id(key, username, method, path)
+	if _, ok := repo.requests[id]; ok {
+		return false, nil
+	}
+	repo.requests[id] = &model.IdempotentRequest{
+		Key: key, Username: username, Method: method, Path: path, RequestHash: requestHash,
+	}
+	return true, nil
+}
+
+func (repo *memoryRepo) GetRequest(
+	ctx context.Context, key string, username string, method string, path string,
+) (*model.IdempotentRequest, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	request, ok := repo.requests[repo.id(key, username, method, path)]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return request, nil
+}
+
+func (repo *memoryRepo) SaveResponse(
+	ctx context.Context,
+	key string,
+	username string,
+	method string,
+	path string,
+	statusCode int,
+	contentType string,
+	response []byte,
+) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	request := repo.requests[repo.id(key, username, method, path)]
+	request.StatusCode = &statusCode
+	request.ContentType = contentType
+	request.Response = response
+	return nil
+}
+
+func (repo *memoryRepo) Release(
+	ctx context.Context, key string, username string, method string, path string,
+) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	delete(repo.requests, repo.id(key, username, method, path))
+	return nil
+}
+
+func (repo *memoryRepo) DeleteExpired(ctx context.Context, retention time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func newHandler(t *testing.T, maxBodySize int64, next http.HandlerFunc) (http.Handler, *int) {
+	t.Helper()
+
+	guard := New(config.Idempotency{LockTimeout: time.Minute}, maxBodySize, &memoryRepo{
+		requests: map[string]*model.IdempotentRequest{},
+	})
+	current.Store(guard)
+	t.Cleanup(func() { current.Store(nil) })
+
+	if next == nil {
+		next = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+
+	var mu sync.Mutex
+	calls := 0
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		next(w, r)
+	}))
+	return handler, &calls
+}
+
+func post(handler http.Handler, target string, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	r.Header.Set(KeyHeader, "key")
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddlewareRejectsLargeBody(t *testing.T) {
+	handler, calls := newHandler(t, 16, nil)
+
+	w := post(handler, "/api/tenders/new", `{"creatorUsername":"user","name":"tender"}`)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+	if *calls != 0 {
+		t.Errorf("handler is called %d times, want 0", *calls)
+	}
+}
+
+func TestMiddlewareScopesKeyByUser(t *testing.T) {
+	handler, calls := newHandler(t, 1<<20, nil)
+
+	w := post(handler, "/api/tenders/new", `{"creatorUsername":"first"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("first user: status %d", w.Code)
+	}
+	w = post(handler, "/api/tenders/new", `{"creatorUsername":"second"}`)
+	if w.Code != http.StatusOK || w.Header().Get(ReplayedHeader) != "" {
+		t.Fatalf("second user: status %d, replayed %q", w.Code, w.Header().Get(ReplayedHeader))
+	}
+	w = post(handler, "/api/tenders/new", `{"creatorUsername":"second"}`)
+	if w.Code != http.StatusOK || w.Header().Get(ReplayedHeader) != "true" {
+		t.Fatalf("repeated request: status %d, replayed %q", w.Code, w.Header().Get(ReplayedHeader))
+	}
+	if *calls != 2 {
+		t.Errorf("handler is called %d times, want 2", *calls)
+	}
+}
+
+func TestMiddlewareRejectsReusedKey(t *testing.T) {
+	handler, calls := newHandler(t, 1<<20, nil)
+
+	w := post(handler, "/api/tenders/new", `{"creatorUsername":"user","name":"first"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("first request: status %d", w.Code)
+	}
+	w = post(handler, "/api/tenders/new", `{"creatorUsername":"user","name":"second"}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if *calls != 1 {
+		t.Errorf("handler is called %d times, want 1", *calls)
+	}
+}
+
+func TestMiddlewareRejectsConcurrentDuplicate(t *testing.T) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	handler, calls := newHandler(t, 1<<20, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-unblock
+		w.WriteHeader(http.StatusOK)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- post(handler, "/api/tenders/new", `{"creatorUsername":"user"}`)
+	}()
+	<-started
+
+	w := post(handler, "/api/tenders/new", `{"creatorUsername":"user"}`)
+	close(unblock)
+	first := <-done
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("duplicate: status %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w.Header().Get("Retry-After") != "1" {
+		t.Errorf("duplicate: Retry-After %q, want %q", w.Header().Get("Retry-After"), "1")
+	}
+	if first.Code != http.StatusOK {
+		t.Errorf("first request: status %d, want %d", first.Code, http.StatusOK)
+	}
+	if *calls != 1 {
+		t.Errorf("handler is called %d times, want 1", *calls)
+	}
+}
+
+func TestMiddlewareReleasesKeyOnRetryableStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{name: "server error", status: http.StatusInternalServerError},
+		{name: "unavailable", status: http.StatusServiceUnavailable},
+		{name: "too many requests", status: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			handler, calls := newHandler(t, 1<<20, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+				status = http.StatusOK
+			})
+
+			w := post(handler, "/api/tenders/new", `{"creatorUsername":"user"}`)
+			if w.Code != tt.status {
+				t.Fatalf("first request: status %d, want %d", w.Code, tt.status)
+			}
+			w = post(handler, "/api/tenders/new", `{"creatorUsername":"user"}`)
+			if w.Code != http.StatusOK || w.Header().Get(ReplayedHeader) != "" {
+				t.Errorf("retry: status %d, replayed %q", w.Code, w.Header().Get(ReplayedHeader))
+			}
+			if *calls != 2 {
+				t.Errorf("handler is called %d times, want 2", *calls)
+			}
+		})
+	}
+}
+
+func TestMiddlewareReplaysResponse(t *testing.T) {
+	handler, calls := newHandler(t, 1<<20, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"tender"}`))
+	})
+
+	first := post(handler, "/api/tenders/new", `{"creatorUsername":"user"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first request: status %d", first.Code)
+	}
+
+	w := post(handler, "/api/tenders/new", `{"creatorUsername":"user"}`)
+	if w.Code != http.StatusCreated {
+		t.Errorf("status %d, want %d", w.Code, http.StatusCreated)
+	}
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type %q, want %q", w.Header().Get("Content-Type"), "application/json")
+	}
+	if w.Body.String() != first.Body.String() {
+		t.Errorf("body %q, want %q", w.Body.String(), first.Body.String())
+	}
+	if w.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("replayed %q, want %q", w.Header().Get(ReplayedHeader), "true")
+	}
+	if *calls != 1 {
+		t.Errorf("handler is called %d times, want 1", *calls)
+	}
+}
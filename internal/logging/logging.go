@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"avi/internal/api/route"
+	"avi/internal/config"
+)
+
+const RequestIdHeader = "X-Request-Id"
+
+const maxRequestIdLength = 128
+
+type contextKey struct{}
+
+func Setup(cfg config.Logging, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(cfg.Level))
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	if strings.EqualFold(cfg.Format, "text") {
+		handler = slog.NewTextHandler(w, options)
+	} else {
+		handler = slog.NewJSONHandler(w, options)
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+func FromContext(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(contextKey{}).(*slog.Logger)
+	if !ok {
+		return slog.Default()
+	}
+	return logger
+}
+
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestId := r.Header.Get(RequestIdHeader)
+		if !validRequestId(requestId) {
+			requestId = uuid.NewString()
+		}
+		w.Header().Set(RequestIdHeader, requestId)
+
+		attrs := []any{slog.String("request_id", requestId)}
+		if spanCtx := trace.SpanContextFromContext(r.Context()); spanCtx.IsValid() {
+			attrs = append(attrs, slog.String("trace_id", spanCtx.TraceID().String()))
+		}
+		if user := r.URL.Query().Get("username"); user != "" {
+			attrs = append(attrs, slog.String("user", user))
+		}
+		logger := slog.Default().With(attrs...)
+		ctx := WithLogger(r.Context(), logger)
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logger.LogAttrs(
+			ctx,
+			level,
+			"request",
+			slog.String("method", r.Method),
+			slog.String("route", route.Pattern(r)),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.String("remote_addr", r.RemoteAddr),
+		)
+	})
+}
+
+func validRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > maxRequestIdLength {
+		return false
+	}
+	for _, c := range requestId {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"avi/internal/api/route"
+)
+
+const namespace = "avi"
+
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	httpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route pattern and status.",
+	}, []string{"method", "route", "status"})
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route pattern and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	queryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Repository method latency.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"repository", "operation"})
+
+	tendersCreated = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tenders_created_total",
+		Help:      "Created tenders, including clones and tenders from templates.",
+	})
+	tenderStatuses = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tender_status_changes_total",
+		Help:      "Tender status changes by new status.",
+	}, []string{"status"})
+	bidsSubmitted = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bids_submitted_total",
+		Help:      "Submitted bids.",
+	})
+	bidDecisions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bid_decisions_total",
+		Help:      "Bid decisions by decision.",
+	}, []string{"decision"})
+	rollbacks = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rollbacks_total",
+		Help:      "Version rollbacks by object.",
+	}, []string{"object"})
+	rateLimited = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Requests rejected by the rate limiter by route pattern and scope.",
+	}, []string{"route", "scope"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+func RegisterDB(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		labels := prometheus.Labels{
+			"method": r.Method,
+			"route":  route.Pattern(r),
+			"status": strconv.Itoa(status),
+		}
+		httpRequests.With(labels).Inc()
+		httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+func ObserveQuery(repository string, operation string, start time.Time) {
+	queryDuration.WithLabelValues(repository, operation).Observe(time.Since(start).Seconds())
+}
+
+func TenderCreated() {
+	tendersCreated.Inc()
+}
+
+func TenderStatusChanged(status string) {
+	tenderStatuses.WithLabelValues(status).Inc()
+}
+
+func BidSubmitted() {
+	bidsSubmitted.Inc()
+}
+
+func BidDecision(decision string) {
+	bidDecisions.WithLabelValues(decision).Inc()
+}
+
+func Rollback(object string) {
+	rollbacks.WithLabelValues(object).Inc()
+}
+
+func RateLimited(route string, scope string) {
+	rateLimited.WithLabelValues(route, scope).Inc()
+}
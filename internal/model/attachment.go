@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttachmentObjectType string
+
+const (
+	TenderAttachmentObjectType   AttachmentObjectType = "Tender"
+	BidAttachmentObjectType      AttachmentObjectType = "Bid"
+	TemplateAttachmentObjectType AttachmentObjectType = "Template"
+)
+
+type Attachment struct {
+	Id          uuid.UUID            `json:"id"`
+	ObjectType  AttachmentObjectType `json:"objectType"`
+	ObjectId    uuid.UUID            `json:"objectId"`
+	FileName    string               `json:"fileName"`
+	ContentType string               `json:"contentType"`
+	Size        int64                `json:"size"`
+	Checksum    string               `json:"checksum"`
+	StorageKey  string               `json:"-"`
+	CreatedAt   time.Time            `json:"createdAt"`
+}
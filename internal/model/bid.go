@@ -8,6 +8,7 @@ import (
 
 type BidStatus string
 type BidAuthorType string
+type BidStage string
 
 const (
 	CreatedBidStatus   BidStatus = "Created"
@@ -20,20 +21,65 @@ const (
 	OrgBidAuthorType  BidAuthorType = "Organization"
 )
 
+const (
+	PrequalificationBidStage BidStage = "Prequalification"
+	CommercialBidStage       BidStage = "Commercial"
+)
+
 type Bid struct {
-	Id          uuid.UUID     `json:"id"`
-	Name        string        `json:"name"`
-	Description string        `json:"description"`
-	Status      BidStatus     `json:"status"`
-	TenderId    uuid.UUID     `json:"tenderId"`
-	AuthorType  BidAuthorType `json:"authorType"`
-	AuthorId    uuid.UUID     `json:"authorId"`
-	Version     int32         `json:"version"`
-	CreatedAt   time.Time     `json:"createdAt"`
+	Id             uuid.UUID     `json:"id"`
+	Name           string        `json:"name"`
+	Description    string        `json:"description"`
+	Status         BidStatus     `json:"status"`
+	TenderId       uuid.UUID     `json:"tenderId"`
+	AuthorType     BidAuthorType `json:"authorType"`
+	AuthorId       uuid.UUID     `json:"authorId"`
+	Stage          BidStage      `json:"stage"`
+	CreatorUserId  *uuid.UUID    `json:"creatorUserId,omitempty"`
+	LotIds         []uuid.UUID   `json:"lotIds,omitempty"`
+	Amount         *float64      `json:"amount,omitempty"`
+	ExceedsReserve bool          `json:"exceedsReserve,omitempty"`
+	Version        int32         `json:"version"`
+	CreatedAt      time.Time     `json:"createdAt"`
+}
+
+type ReviewTag string
+
+const (
+	PriceReviewTag         ReviewTag = "Price"
+	QualityReviewTag       ReviewTag = "Quality"
+	DeadlinesReviewTag     ReviewTag = "Deadlines"
+	CommunicationReviewTag ReviewTag = "Communication"
+	DocumentationReviewTag ReviewTag = "Documentation"
+	ExperienceReviewTag    ReviewTag = "Experience"
+)
+
+var ReviewTags = []ReviewTag{
+	PriceReviewTag,
+	QualityReviewTag,
+	DeadlinesReviewTag,
+	CommunicationReviewTag,
+	DocumentationReviewTag,
+	ExperienceReviewTag,
 }
 
 type Review struct {
-	Id          uuid.UUID `json:"id"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"createdAt"`
+	Id          uuid.UUID      `json:"id"`
+	BidId       uuid.UUID      `json:"bidId"`
+	ReviewerId  *uuid.UUID     `json:"reviewerId,omitempty"`
+	Description string         `json:"description"`
+	Rating      *int           `json:"rating,omitempty"`
+	Tags        []ReviewTag    `json:"tags"`
+	Version     int32          `json:"version"`
+	Replies     []*ReviewReply `json:"replies"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+type ReviewReply struct {
+	Id        uuid.UUID `json:"id"`
+	ReviewId  uuid.UUID `json:"reviewId"`
+	AuthorId  uuid.UUID `json:"authorId"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
 }
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type IdempotentRequest struct {
+	Key         string
+	Username    string
+	Method      string
+	Path        string
+	RequestHash string
+	StatusCode  *int
+	ContentType string
+	Response    []byte
+	CreatedAt   time.Time
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InviteeType string
+
+const (
+	UserInviteeType InviteeType = "User"
+	OrgInviteeType  InviteeType = "Organization"
+)
+
+type Invitation struct {
+	Id          uuid.UUID   `json:"id"`
+	TenderId    uuid.UUID   `json:"tenderId"`
+	InviteeType InviteeType `json:"inviteeType"`
+	InviteeId   uuid.UUID   `json:"inviteeId"`
+	InvitedBy   uuid.UUID   `json:"-"`
+	CreatedAt   time.Time   `json:"createdAt"`
+}
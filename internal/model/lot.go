@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LotStatus string
+
+const (
+	OpenLotStatus     LotStatus = "Open"
+	AwardedLotStatus  LotStatus = "Awarded"
+	CanceledLotStatus LotStatus = "Canceled"
+)
+
+type Lot struct {
+	Id           uuid.UUID  `json:"id"`
+	TenderId     uuid.UUID  `json:"tenderId"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Quantity     int        `json:"quantity"`
+	Budget       *float64   `json:"budget,omitempty"`
+	Status       LotStatus  `json:"status"`
+	AwardedBidId *uuid.UUID `json:"awardedBidId,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
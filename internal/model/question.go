@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type QuestionStatus string
+type AnswerVisibility string
+
+const (
+	OpenQuestionStatus     QuestionStatus = "Open"
+	AnsweredQuestionStatus QuestionStatus = "Answered"
+	ClosedQuestionStatus   QuestionStatus = "Closed"
+)
+
+const (
+	PublicAnswerVisibility  AnswerVisibility = "Public"
+	PrivateAnswerVisibility AnswerVisibility = "Private"
+)
+
+type Question struct {
+	Id         uuid.UUID        `json:"id"`
+	TenderId   uuid.UUID        `json:"tenderId"`
+	AuthorId   uuid.UUID        `json:"-"`
+	Text       string           `json:"text"`
+	Status     QuestionStatus   `json:"status"`
+	Answer     string           `json:"answer,omitempty"`
+	Visibility AnswerVisibility `json:"visibility,omitempty"`
+	AnsweredBy *uuid.UUID       `json:"-"`
+	AnsweredAt *time.Time       `json:"answeredAt,omitempty"`
+	CreatedAt  time.Time        `json:"createdAt"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Reputation struct {
+	AuthorType    BidAuthorType `json:"authorType"`
+	AuthorId      uuid.UUID     `json:"authorId"`
+	Bids          int           `json:"bids"`
+	Wins          int           `json:"wins"`
+	Cancellations int           `json:"cancellations"`
+	AverageRating *float64      `json:"averageRating,omitempty"`
+	Ratings       int           `json:"ratings"`
+	RecentReviews []*Review     `json:"recentReviews"`
+	UpdatedAt     *time.Time    `json:"updatedAt,omitempty"`
+}
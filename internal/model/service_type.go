@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type ServiceType struct {
+	Code       TenderServiceType  `json:"code"`
+	Name       string             `json:"name"`
+	ParentCode *TenderServiceType `json:"parentCode,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ShortlistEntry struct {
+	Id         uuid.UUID     `json:"id"`
+	TenderId   uuid.UUID     `json:"tenderId"`
+	BidId      uuid.UUID     `json:"bidId"`
+	AuthorType BidAuthorType `json:"authorType"`
+	AuthorId   uuid.UUID     `json:"authorId"`
+	AddedBy    uuid.UUID     `json:"-"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
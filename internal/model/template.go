@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TenderTemplate struct {
+	Id             uuid.UUID         `json:"id"`
+	OrganizationId uuid.UUID         `json:"organizationId"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	ServiceType    TenderServiceType `json:"serviceType"`
+	Criteria       []string          `json:"criteria"`
+	Version        int32             `json:"version"`
+	CreatedAt      time.Time         `json:"createdAt"`
+}
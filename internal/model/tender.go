@@ -10,16 +10,18 @@ type TenderStatus string
 type TenderServiceType string
 
 const (
-	TenderStatusCreated   TenderStatus = "Created"
-	TenderStatusPublished TenderStatus = "Published"
-	TenderStatusClosed    TenderStatus = "Closed"
+	TenderStatusCreated          TenderStatus = "Created"
+	TenderStatusPublished        TenderStatus = "Published"
+	TenderStatusPrequalification TenderStatus = "Prequalification"
+	TenderStatusCommercial       TenderStatus = "Commercial"
+	TenderStatusClosed           TenderStatus = "Closed"
 )
 
-const (
-	TenderServiceTypeConstruction TenderServiceType = "Construction"
-	TenderServiceTypeDelivery     TenderServiceType = "Delivery"
-	TenderServiceTypeManufacture  TenderServiceType = "Manufacture"
-)
+func (status TenderStatus) IsOpen() bool {
+	return status == TenderStatusPublished ||
+		status == TenderStatusPrequalification ||
+		status == TenderStatusCommercial
+}
 
 type Tender struct {
 	Id             uuid.UUID         `json:"id"`
@@ -28,6 +30,16 @@ type Tender struct {
 	ServiceType    TenderServiceType `json:"serviceType"`
 	Status         TenderStatus      `json:"status"`
 	OrganizationId uuid.UUID         `json:"organizationId"`
+	InviteOnly     bool              `json:"inviteOnly"`
+	Criteria       []string          `json:"criteria"`
 	Version        int32             `json:"version"`
 	CreatedAt      time.Time         `json:"createdAt"`
+	TenderBudget
+}
+
+type TenderBudget struct {
+	Budget       *float64 `json:"budget,omitempty"`
+	Reserve      *float64 `json:"reserve,omitempty"`
+	Currency     string   `json:"currency,omitempty"`
+	StrictBudget bool     `json:"strictBudget"`
 }
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+func (store *MemoryStore) Take(
+	ctx context.Context, key string, limit Limit,
+) (allowed bool, retryAfter time.Duration, err error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.now()
+	b, ok := store.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limit.Capacity, updated: now}
+		store.buckets[key] = b
+	}
+	b.tokens = min(limit.Capacity, b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+
+	if b.tokens < 1 {
+		return false, limit.wait(b.tokens), nil
+	}
+	b.tokens--
+	return true, 0, nil
+}
+
+func (store *MemoryStore) Cleanup(ctx context.Context, idle time.Duration) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for key, b := range store.buckets {
+		if store.now().Sub(b.updated) > idle {
+			delete(store.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"avi/internal/metrics"
+	"avi/internal/tracing"
+)
+
+type PostgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(ctx context.Context, db *sql.DB) (store *PostgresStore, err error) {
+	store = &PostgresStore{db: db}
+
+	table, err := store.tableExists(ctx)
+	if err != nil {
+		return
+	}
+
+	if table {
+		return
+	}
+
+	err = store.createTable(ctx)
+	if err == nil {
+		slog.Info("Table 'rate_limit_bucket' is created")
+	} else {
+		slog.Info("Can not create table 'rate_limit_bucket'")
+	}
+	return
+}
+
+func (store *PostgresStore) Take(
+	ctx context.Context, key string, limit Limit,
+) (allowed bool, retryAfter time.Duration, err error) {
+	defer metrics.ObserveQuery("ratelimit", "Take", time.Now())
+	ctx, span := tracing.Start(ctx, "PostgresStore.Take")
+	defer span.End()
+	refill := `LEAST($2::float8, bucket.tokens +
+		EXTRACT(EPOCH FROM now() - bucket.updated_at)::float8 * $3::float8)`
+	takeQuery := `
+		INSERT INTO rate_limit_bucket AS bucket (key, tokens, allowed, updated_at)
+		VALUES ($1, $2::float8 - 1, TRUE, now())
+		ON CONFLICT (key) DO UPDATE SET
+		tokens = CASE WHEN ` + refill + ` >= 1 THEN ` + refill + ` - 1 ELSE ` + refill + ` END,
+		allowed = ` + refill + ` >= 1,
+		updated_at = now()
+		RETURNING tokens, allowed;
+	`
+	var tokens float64
+	err = store.db.QueryRowContext(
+		ctx, takeQuery, key, limit.Capacity, limit.Rate,
+	).Scan(&tokens, &allowed)
+	if err != nil || allowed {
+		return
+	}
+	return false, limit.wait(tokens), nil
+}
+
+func (store *PostgresStore) Cleanup(ctx context.Context, idle time.Duration) error {
+	defer metrics.ObserveQuery("ratelimit", "Cleanup", time.Now())
+	ctx, span := tracing.Start(ctx, "PostgresStore.Cleanup")
+	defer span.End()
+	deleteQuery := `
+		DELETE FROM rate_limit_bucket
+		WHERE updated_at < now() - $1 * INTERVAL '1 millisecond';
+	`
+	_, err := store.db.ExecContext(ctx, deleteQuery, idle.Milliseconds())
+	return err
+}
+
+func (store *PostgresStore) tableExists(ctx context.Context) (table bool, err error) {
+	err = store.db.QueryRowContext(
+		ctx,
+		`SELECT EXISTS (SELECT FROM information_schema.tables
+		WHERE table_name = 'rate_limit_bucket');`,
+	).Scan(&table)
+	return
+}
+
+func (store *PostgresStore) createTable(ctx context.Context) error {
+	createBucketTable := `
+		CREATE TABLE IF NOT EXISTS rate_limit_bucket (
+		key TEXT PRIMARY KEY,
+		tokens DOUBLE PRECISION NOT NULL,
+		allowed BOOLEAN NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now());
+	`
+	_, err := store.db.ExecContext(ctx, createBucketTable)
+	return err
+}
@@ -0,0 +1,286 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"avi/internal/api/apierror"
+	"avi/internal/api/route"
+	"avi/internal/config"
+	"avi/internal/logging"
+	"avi/internal/metrics"
+)
+
+var ErrorTooManyRequests = errors.New("too many requests")
+var ErrorUnavailable = errors.New("rate limit is unavailable")
+
+const cleanupInterval = time.Minute
+
+const maxPeekedBody = 1 << 20
+
+var current atomic.Pointer[Limiter]
+
+type Store interface {
+	Take(
+		ctx context.Context, key string, limit Limit,
+	) (allowed bool, retryAfter time.Duration, err error)
+	Cleanup(ctx context.Context, idle time.Duration) error
+}
+
+type Limit struct {
+	Rate     float64
+	Capacity float64
+}
+
+type routeLimit struct {
+	user Limit
+	ip   Limit
+}
+
+type Limiter struct {
+	store          Store
+	failOpen       bool
+	trustedProxies []netip.Prefix
+	defaults       routeLimit
+	routes         map[string]routeLimit
+	idle           time.Duration
+}
+
+func NewLimit(cfg config.Limit) Limit {
+	if cfg.Requests <= 0 || cfg.Period <= 0 {
+		return Limit{}
+	}
+	burst := cfg.Burst
+	if burst == 0 {
+		burst = cfg.Requests
+	}
+	return Limit{
+		Rate:     float64(cfg.Requests) / cfg.Period.Seconds(),
+		Capacity: float64(burst),
+	}
+}
+
+func (limit Limit) Enabled() bool {
+	return limit.Rate > 0
+}
+
+func (limit Limit) refill() time.Duration {
+	if !limit.Enabled() {
+		return 0
+	}
+	return time.Duration(limit.Capacity / limit.Rate * float64(time.Second))
+}
+
+func (limit Limit) wait(tokens float64) time.Duration {
+	return time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+}
+
+func New(cfg config.RateLimit, store Store) (*Limiter, error) {
+	trustedProxies, err := cfg.ProxyPrefixes()
+	if err != nil {
+		return nil, err
+	}
+	limiter := &Limiter{
+		store:          store,
+		failOpen:       cfg.FailOpen,
+		trustedProxies: trustedProxies,
+		defaults:       routeLimit{user: NewLimit(cfg.User), ip: NewLimit(cfg.IP)},
+		routes:         map[string]routeLimit{},
+	}
+	limiter.idle = max(limiter.defaults.user.refill(), limiter.defaults.ip.refill())
+	for key, cfgLimit := range cfg.Routes {
+		limit := routeLimit{user: NewLimit(cfgLimit.User), ip: NewLimit(cfgLimit.IP)}
+		limiter.routes[key] = limit
+		limiter.idle = max(limiter.idle, limit.user.refill(), limit.ip.refill())
+	}
+	return limiter, nil
+}
+
+func Setup(ctx context.Context, cfg config.RateLimit, db *sql.DB) (*Limiter, error) {
+	if !cfg.Enabled {
+		current.Store(nil)
+		return nil, nil
+	}
+
+	var store Store = NewMemoryStore()
+	if cfg.Store == "postgres" {
+		postgresStore, err := NewPostgresStore(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		store = postgresStore
+	}
+
+	limiter, err := New(cfg, store)
+	if err != nil {
+		return nil, err
+	}
+	current.Store(limiter)
+	return limiter, nil
+}
+
+func (limiter *Limiter) Name() string {
+	return "ratelimit-cleanup"
+}
+
+func (limiter *Limiter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			err := limiter.store.Cleanup(ctx, limiter.idle)
+			if err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Error("rate limit cleanup: " + err.Error())
+			}
+		}
+	}
+}
+
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := current.Load()
+		if limiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		retryAfter, scope, err := limiter.check(r)
+		if err != nil && limiter.failOpen {
+			logging.FromContext(r.Context()).Error("rate limit: " + err.Error() + ", request is allowed")
+		} else if err != nil {
+			logging.FromContext(r.Context()).Error("rate limit: " + err.Error() + ", request is rejected")
+			w.Header().Set("Retry-After", "1")
+			apierror.HandleError(w, r, ErrorUnavailable, http.StatusServiceUnavailable)
+			return
+		}
+		if scope != "" {
+			metrics.RateLimited(route.Pattern(r), scope)
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+			apierror.HandleError(w, r, ErrorTooManyRequests, http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (limiter *Limiter) check(r *http.Request) (retryAfter time.Duration, scope string, err error) {
+	routeKey := r.Method + " " + route.Pattern(r)
+	limit, ok := limiter.routes[routeKey]
+	if !ok {
+		limit = limiter.defaults
+	}
+
+	if limit.ip.Enabled() {
+		ip := limiter.clientIP(r)
+		allowed, wait, err := limiter.store.Take(r.Context(), "ip:"+ip+":"+routeKey, limit.ip)
+		if err != nil {
+			return 0, "", err
+		}
+		if !allowed {
+			return wait, "ip", nil
+		}
+	}
+
+	if limit.user.Enabled() {
+		user := requestUser(r)
+		if user == "" {
+			return 0, "", nil
+		}
+		allowed, wait, err := limiter.store.Take(r.Context(), "user:"+user+":"+routeKey, limit.user)
+		if err != nil {
+			return 0, "", err
+		}
+		if !allowed {
+			return wait, "user", nil
+		}
+	}
+	return 0, "", nil
+}
+
+func (limiter *Limiter) clientIP(r *http.Request) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	if !limiter.trusted(remote) {
+		return remote
+	}
+
+	var hops []string
+	for _, forwarded := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(forwarded, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	if len(hops) == 0 {
+		if realIP := strings.TrimSpace(r.Header.Get("X-Real-Ip")); realIP != "" {
+			return realIP
+		}
+		return remote
+	}
+	for i := len(hops) - 1; i > 0; i-- {
+		if !limiter.trusted(hops[i]) {
+			return hops[i]
+		}
+	}
+	return hops[0]
+}
+
+func (limiter *Limiter) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range limiter.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func requestUser(r *http.Request) string {
+	if username := r.URL.Query().Get("username"); username != "" {
+		return username
+	}
+	if r.Body == nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPeekedBody))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if err != nil {
+		return ""
+	}
+	var req struct {
+		CreatorUsername string `json:"creatorUsername"`
+		Username        string `json:"username"`
+	}
+	if json.Unmarshal(body, &req) != nil {
+		return ""
+	}
+	if req.CreatorUsername != "" {
+		return req.CreatorUsername
+	}
+	return req.Username
+}
@@ -0,0 +1,195 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"avi/internal/config"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limit := NewLimit(config.Limit{Requests: 60, Period: time.Minute, Burst: 2})
+
+	tests := []struct {
+		name       string
+		elapsed    time.Duration
+		allowed    bool
+		retryAfter time.Duration
+	}{
+		{"first token of burst", 0, true, 0},
+		{"second token of burst", 0, true, 0},
+		{"burst is exhausted", 0, false, time.Second},
+		{"half token is refilled", 500 * time.Millisecond, false, 500 * time.Millisecond},
+		{"token is refilled", time.Second, true, 0},
+		{"refilled token is used", time.Second, false, time.Second},
+		{"refill is capped by burst", time.Minute, true, 0},
+		{"second token after idle", time.Minute, true, 0},
+		{"no third token after idle", time.Minute, false, time.Second},
+	}
+
+	store := NewMemoryStore()
+	for _, test := range tests {
+		store.now = func() time.Time { return start.Add(test.elapsed) }
+		allowed, retryAfter, err := store.Take(context.Background(), "key", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allowed != test.allowed || retryAfter != test.retryAfter {
+			t.Errorf("%s: got allowed %v retry after %s, want %v and %s",
+				test.name, allowed, retryAfter, test.allowed, test.retryAfter)
+		}
+	}
+
+	store.now = func() time.Time { return start.Add(time.Minute + limit.refill() + time.Second) }
+	err := store.Cleanup(context.Background(), limit.refill())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.buckets) != 0 {
+		t.Errorf("cleanup kept %d idle buckets", len(store.buckets))
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (bool, time.Duration, error) {
+	return false, 0, errors.New("store is unavailable")
+}
+
+func (failingStore) Cleanup(context.Context, time.Duration) error {
+	return nil
+}
+
+func newRouter(t *testing.T, cfg config.RateLimit, store Store) http.Handler {
+	t.Helper()
+
+	limiter, err := New(cfg, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current.Store(limiter)
+	t.Cleanup(func() { current.Store(nil) })
+
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	r := chi.NewRouter()
+	limited := r.With(Middleware)
+	limited.Post("/api/tenders/new", ok)
+	limited.Post("/api/bids/new", ok)
+	return r
+}
+
+func send(handler http.Handler, path string, username string, remote string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, path+"?username="+username, strings.NewReader("{}"))
+	r.RemoteAddr = remote
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	cfg := config.RateLimit{
+		User: config.Limit{Requests: 3, Period: time.Minute},
+		IP:   config.Limit{Requests: 100, Period: time.Minute},
+		Routes: map[string]config.RouteLimit{
+			"POST /api/bids/new": {User: config.Limit{Requests: 1, Period: time.Minute}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		username   string
+		remote     string
+		status     int
+		retryAfter string
+	}{
+		{"route limit", "/api/bids/new", "user", "203.0.113.1:1000", http.StatusOK, ""},
+		{"route limit is exceeded", "/api/bids/new", "user", "203.0.113.1:1000", http.StatusTooManyRequests, "60"},
+		{"user limit ignores address", "/api/bids/new", "user", "203.0.113.2:1000", http.StatusTooManyRequests, "60"},
+		{"route disables ip limit", "/api/bids/new", "other", "203.0.113.1:1000", http.StatusOK, ""},
+		{"default limit", "/api/tenders/new", "user", "203.0.113.1:1000", http.StatusOK, ""},
+		{"default limit from other address", "/api/tenders/new", "user", "203.0.113.2:1000", http.StatusOK, ""},
+		{"default limit from third address", "/api/tenders/new", "user", "203.0.113.3:1000", http.StatusOK, ""},
+		{"default limit is exceeded", "/api/tenders/new", "user", "203.0.113.4:1000", http.StatusTooManyRequests, "20"},
+	}
+
+	handler := newRouter(t, cfg, NewMemoryStore())
+	for _, test := range tests {
+		w := send(handler, test.path, test.username, test.remote)
+		if w.Code != test.status || w.Header().Get("Retry-After") != test.retryAfter {
+			t.Errorf("%s: got %d with Retry-After %q, want %d with %q",
+				test.name, w.Code, w.Header().Get("Retry-After"), test.status, test.retryAfter)
+		}
+	}
+}
+
+func TestMiddlewareStoreFailure(t *testing.T) {
+	tests := []struct {
+		failOpen bool
+		status   int
+	}{
+		{true, http.StatusOK},
+		{false, http.StatusServiceUnavailable},
+	}
+	for _, test := range tests {
+		handler := newRouter(t, config.RateLimit{
+			FailOpen: test.failOpen,
+			IP:       config.Limit{Requests: 1, Period: time.Minute},
+		}, failingStore{})
+		w := send(handler, "/api/tenders/new", "user", "203.0.113.1:1000")
+		if w.Code != test.status {
+			t.Errorf("fail open %v: got %d, want %d", test.failOpen, w.Code, test.status)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	limiter, err := New(config.RateLimit{
+		TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1"},
+	}, NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		realIP    string
+		want      string
+	}{
+		{"direct client", "203.0.113.5:1234", nil, "", "203.0.113.5"},
+		{"untrusted remote ignores headers", "203.0.113.5:1234", []string{"198.51.100.1"}, "198.51.100.2", "203.0.113.5"},
+		{"trusted proxy without headers", "10.0.0.1:1234", nil, "", "10.0.0.1"},
+		{"trusted proxy real ip", "10.0.0.1:1234", nil, "198.51.100.2", "198.51.100.2"},
+		{"single hop", "10.0.0.1:1234", []string{"198.51.100.1"}, "", "198.51.100.1"},
+		{"spoofed left-most hop", "10.0.0.1:1234", []string{"1.2.3.4, 198.51.100.1"}, "", "198.51.100.1"},
+		{"proxy chain", "10.0.0.1:1234", []string{"1.2.3.4, 198.51.100.1", "192.168.1.1, 10.0.0.2"}, "", "198.51.100.1"},
+		{"all hops trusted", "10.0.0.1:1234", []string{"10.0.0.3, 10.0.0.2"}, "", "10.0.0.3"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/bids/new", nil)
+			r.RemoteAddr = test.remote
+			for _, forwarded := range test.forwarded {
+				r.Header.Add("X-Forwarded-For", forwarded)
+			}
+			if test.realIP != "" {
+				r.Header.Set("X-Real-Ip", test.realIP)
+			}
+			if got := limiter.clientIP(r); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package attachment
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"avi/internal/database"
+	"avi/internal/metrics"
+	"avi/internal/model"
+	"avi/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+type AttachmentRepo struct {
+	db *sql.DB
+}
+
+func (repo *AttachmentRepo) GetAttachments(
+	ctx context.Context, objectId uuid.UUID, version int32,
+) (attachments []*model.Attachment, err error) {
+	defer metrics.ObserveQuery("attachment", "GetAttachments", time.Now())
+	ctx, span := tracing.Start(ctx, "AttachmentRepo.GetAttachments")
+	defer span.End()
+	selectQuery := `
+		SELECT attachment.id, attachment.object_type,
+		attachment.object_id, attachment.file_name,
+		attachment.content_type, attachment.size,
+		attachment.checksum, attachment.storage_key,
+		attachment.created_at
+		FROM attachment_version
+		INNER JOIN attachment
+		ON attachment.id = attachment_version.attachment_id
+		WHERE attachment_version.object_id = $1
+		AND attachment_version.version = $2
+		ORDER BY attachment.created_at ASC;
+	`
+	rows, err := repo.db.QueryContext(ctx, selectQuery, objectId, version)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attachment model.Attachment
+		err = rows.Scan(
+			&attachment.Id,
+			&attachment.ObjectType,
+			&attachment.ObjectId,
+			&attachment.FileName,
+			&attachment.ContentType,
+			&attachment.Size,
+			&attachment.Checksum,
+			&attachment.StorageKey,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			return
+		}
+		attachments = append(attachments, &attachment)
+	}
+	return
+}
+
+func (repo *AttachmentRepo) GetAttachmentById(
+	ctx context.Context, objectId uuid.UUID, id uuid.UUID,
+) (attachment *model.Attachment, err error) {
+	defer metrics.ObserveQuery("attachment", "GetAttachmentById", time.Now())
+	ctx, span := tracing.Start(ctx, "AttachmentRepo.GetAttachmentById")
+	defer span.End()
+	selectQuery := `
+		SELECT id, object_type, object_id, file_name,
+		content_type, size, checksum, storage_key,
+		created_at
+		FROM attachment
+		WHERE id = $1 AND object_id = $2;
+	`
+	attachment = &model.Attachment{}
+	err = repo.db.QueryRowContext(
+		ctx, selectQuery, id, objectId,
+	).Scan(
+		&attachment.Id,
+		&attachment.ObjectType,
+		&attachment.ObjectId,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.Checksum,
+		&attachment.StorageKey,
+		&attachment.CreatedAt,
+	)
+	return
+}
+
+func CreateAttachmentTx(
+	ctx context.Context, tx *sql.Tx, attachment *model.Attachment, version int32,
+) error {
+	createQuery := `
+		INSERT INTO attachment
+		(id, object_type, object_id, file_name,
+		content_type, size, checksum, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at;
+	`
+	err := tx.QueryRowContext(
+		ctx,
+		createQuery,
+		attachment.Id,
+		attachment.ObjectType,
+		attachment.ObjectId,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.Checksum,
+		attachment.StorageKey,
+	).Scan(&attachment.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	linkQuery := `
+		INSERT INTO attachment_version
+		(object_id, version, attachment_id)
+		VALUES ($1, $2, $3);
+	`
+	_, err = tx.ExecContext(ctx, linkQuery, attachment.ObjectId, version, attachment.Id)
+	return err
+}
+
+func CopyVersionTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	objectId uuid.UUID,
+	fromVersion int32,
+	toVersion int32,
+	excludeId uuid.UUID,
+) error {
+	copyQuery := `
+		INSERT INTO attachment_version
+		(object_id, version, attachment_id)
+		SELECT object_id, $3, attachment_id
+		FROM attachment_version
+		WHERE object_id = $1 AND version = $2
+		AND attachment_id <> $4;
+	`
+	_, err := tx.ExecContext(ctx, copyQuery, objectId, fromVersion, toVersion, excludeId)
+	return err
+}
+
+func CopyAttachmentsTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	fromObjectId uuid.UUID,
+	fromVersion int32,
+	objectType model.AttachmentObjectType,
+	toObjectId uuid.UUID,
+	toVersion int32,
+) error {
+	copyQuery := `
+		WITH copied AS (
+			INSERT INTO attachment
+			(id, object_type, object_id, file_name,
+			content_type, size, checksum, storage_key)
+			SELECT gen_random_uuid(), $3::attachment_object_type,
+			$4::uuid, attachment.file_name,
+			attachment.content_type, attachment.size,
+			attachment.checksum, attachment.storage_key
+			FROM attachment_version
+			INNER JOIN attachment
+			ON attachment.id = attachment_version.attachment_id
+			WHERE attachment_version.object_id = $1
+			AND attachment_version.version = $2
+			RETURNING id
+		)
+		INSERT INTO attachment_version
+		(object_id, version, attachment_id)
+		SELECT $4::uuid, $5::int, id FROM copied;
+	`
+	_, err := tx.ExecContext(
+		ctx, copyQuery, fromObjectId, fromVersion, objectType, toObjectId, toVersion,
+	)
+	return err
+}
+
+func NewRepo() (repo *AttachmentRepo, err error) {
+	db, err := database.Connect()
+	if err != nil {
+		return
+	}
+	repo = &AttachmentRepo{db: db}
+	return
+}
+
+func Migrate() (err error) {
+	repo, err := NewRepo()
+	if err != nil {
+		return
+	}
+
+	table, err := repo.tableExists()
+	if err != nil {
+		return
+	}
+
+	if !table {
+		err = repo.createTable()
+		if err == nil {
+			slog.Info("Tables 'attachment' and 'attachment_version' are created")
+		} else {
+			slog.Info("Can not create tables 'attachment' and 'attachment_version'")
+			return
+		}
+	}
+
+	objectType, err := repo.objectTypeExists(model.TemplateAttachmentObjectType)
+	if err != nil {
+		return
+	}
+
+	if objectType {
+		return
+	}
+
+	_, err = repo.db.Exec(`
+		ALTER TYPE attachment_object_type ADD VALUE IF NOT EXISTS 'Template';
+	`)
+	if err == nil {
+		slog.Info("Object type 'Template' is added to type 'attachment_object_type'")
+	} else {
+		slog.Info("Can not add object type 'Template' to type 'attachment_object_type'")
+	}
+	return
+}
+
+func (repo *AttachmentRepo) objectTypeExists(
+	objectType model.AttachmentObjectType,
+) (exists bool, err error) {
+	err = repo.db.QueryRow(
+		`SELECT EXISTS (SELECT FROM pg_enum
+		WHERE enumtypid = 'attachment_object_type'::regtype AND enumlabel = $1);`,
+		objectType,
+	).Scan(&exists)
+	return
+}
+
+func (repo *AttachmentRepo) tableExists() (table bool, err error) {
+	rows, err := repo.db.Query(
+		`SELECT EXISTS (SELECT FROM information_schema.tables 
+		WHERE table_name = 'attachment');`,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&table)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (repo *AttachmentRepo) createTable() error {
+	createObjectType := `
+		CREATE TYPE attachment_object_type
+		AS ENUM ('Tender', 'Bid', 'Template');
+	`
+	createAttachmentTable := `
+		CREATE TABLE attachment (
+		id UUID PRIMARY KEY,
+		object_type attachment_object_type NOT NULL,
+		object_id UUID NOT NULL,
+		file_name VARCHAR(255) NOT NULL,
+		content_type VARCHAR(255) NOT NULL,
+		size BIGINT NOT NULL,
+		checksum CHAR(64) NOT NULL,
+		storage_key VARCHAR(300) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+	`
+	createAttachmentVersionTable := `
+		CREATE TABLE attachment_version (
+		object_id UUID NOT NULL,
+		version INT NOT NULL,
+		attachment_id UUID REFERENCES attachment(id),
+		PRIMARY KEY (object_id, version, attachment_id));
+	`
+	_, err := repo.db.Exec(
+		createObjectType +
+			createAttachmentTable +
+			createAttachmentVersionTable,
+	)
+	return err
+}
@@ -1,28 +1,46 @@
 package bid
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"log/slog"
 	"strconv"
 	"time"
 
 	"avi/internal/database"
+	"avi/internal/metrics"
 	"avi/internal/model"
+	attachmentRepo "avi/internal/repository/attachment"
+	lotRepo "avi/internal/repository/lot"
+	"avi/internal/tracing"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+var ErrorBidLimitExceeded = errors.New("bid limit per author for tender is exceeded")
+
 type BidRepo struct {
 	db *sql.DB
 }
 
 func (repo *BidRepo) CreateBid(
+	ctx context.Context,
 	name string,
 	description string,
 	tenderId uuid.UUID,
 	authorType model.BidAuthorType,
 	authorId uuid.UUID,
+	creatorUserId uuid.UUID,
+	lotIds []uuid.UUID,
+	amount *float64,
+	stage model.BidStage,
+	maxBids int,
 ) (bid *model.Bid, err error) {
+	defer metrics.ObserveQuery("bid", "CreateBid", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.CreateBid")
+	defer span.End()
 	var id uuid.UUID
 	var version int32
 	var createdAt time.Time
@@ -31,48 +49,98 @@ func (repo *BidRepo) CreateBid(
 	createQuery := `
 		INSERT INTO bid 
 		(name, description, tender_id, 
-		author_type, author_id)
-		VALUES ($1, $2, $3, $4, $5) 
+		author_type, author_id, creator_user_id, amount, stage)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
 		RETURNING id, status, version, created_at;
 	`
-	err = repo.db.QueryRow(
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	if maxBids > 0 {
+		err = checkBidLimitTx(ctx, tx, tenderId, authorType, authorId, stage, maxBids)
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}
+
+	err = tx.QueryRowContext(
+		ctx,
 		createQuery,
 		name,
 		description,
 		tenderId,
 		authorType,
 		authorId,
+		creatorUserId,
+		amount,
+		stage,
 	).Scan(&id, &status, &version, &createdAt)
 
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = lotRepo.CreateBidLotsTx(ctx, tx, id, lotIds)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = refreshReputationTx(ctx, tx, id)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return
 	}
 
 	bid = &model.Bid{
-		Id:          id,
-		Name:        name,
-		Description: description,
-		AuthorType:  authorType,
-		AuthorId:    authorId,
-		Status:      status,
-		TenderId:    tenderId,
-		Version:     version,
-		CreatedAt:   createdAt,
+		Id:            id,
+		Name:          name,
+		Description:   description,
+		AuthorType:    authorType,
+		AuthorId:      authorId,
+		CreatorUserId: &creatorUserId,
+		LotIds:        lotIds,
+		Amount:        amount,
+		Stage:         stage,
+		Status:        status,
+		TenderId:      tenderId,
+		Version:       version,
+		CreatedAt:     createdAt,
 	}
 	return
 }
 
-func (repo *BidRepo) GetBidsByAuthorId(
+func (repo *BidRepo) GetBidsByUserId(
+	ctx context.Context,
 	offset int,
 	limit int,
-	authorId uuid.UUID,
+	userId uuid.UUID,
 ) (bids []*model.Bid, err error) {
+	defer metrics.ObserveQuery("bid", "GetBidsByUserId", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.GetBidsByUserId")
+	defer span.End()
 	selectQuery := `
 		SELECT id, name, description,
 		status, tender_id, author_type,
-		author_id, version, created_at
+		author_id, version, created_at,
+		creator_user_id, amount, stage
 		FROM bid 
-		WHERE author_id = $1
+		WHERE author_type = 'User' AND author_id = $1
+		OR author_type = 'Organization' AND author_id IN (
+			SELECT organization_id
+			FROM organization_responsible
+			WHERE user_id = $1
+		)
+		ORDER BY created_at ASC
 	`
 	if limit > 0 {
 		selectQuery += " LIMIT " + strconv.Itoa(limit)
@@ -84,7 +152,7 @@ func (repo *BidRepo) GetBidsByAuthorId(
 
 	selectQuery += ";"
 
-	rows, err := repo.db.Query(selectQuery, authorId)
+	rows, err := repo.db.QueryContext(ctx, selectQuery, userId)
 	if err != nil {
 		return
 	}
@@ -102,26 +170,41 @@ func (repo *BidRepo) GetBidsByAuthorId(
 			&bid.AuthorId,
 			&bid.Version,
 			&bid.CreatedAt,
+			&bid.CreatorUserId,
+			&bid.Amount,
+			&bid.Stage,
 		)
 		if err != nil {
 			return
 		}
 		bids = append(bids, &bid)
 	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	err = repo.loadLots(ctx, bids)
 	return
 }
 
 func (repo *BidRepo) GetBidsByTenderId(
+	ctx context.Context,
 	offset int,
 	limit int,
 	tenderId uuid.UUID,
+	stage model.BidStage,
 ) (bids []*model.Bid, err error) {
+	defer metrics.ObserveQuery("bid", "GetBidsByTenderId", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.GetBidsByTenderId")
+	defer span.End()
 	selectQuery := `
 		SELECT id, name, description,
 		status, tender_id, author_type,
-		author_id, version, created_at
+		author_id, version, created_at,
+		creator_user_id, amount, stage
 		FROM bid
-		WHERE tender_id = $1
+		WHERE tender_id = $1 AND ($2 = '' OR stage::text = $2)
 		ORDER BY name ASC
 	`
 	if limit > 0 {
@@ -134,7 +217,7 @@ func (repo *BidRepo) GetBidsByTenderId(
 
 	selectQuery += ";"
 
-	rows, err := repo.db.Query(selectQuery, tenderId)
+	rows, err := repo.db.QueryContext(ctx, selectQuery, tenderId, stage)
 	if err != nil {
 		return
 	}
@@ -152,26 +235,75 @@ func (repo *BidRepo) GetBidsByTenderId(
 			&bid.AuthorId,
 			&bid.Version,
 			&bid.CreatedAt,
+			&bid.CreatorUserId,
+			&bid.Amount,
+			&bid.Stage,
 		)
 		if err != nil {
 			return
 		}
 		bids = append(bids, &bid)
 	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	err = repo.loadLots(ctx, bids)
 	return
 }
 
-func (repo *BidRepo) GetBidById(id uuid.UUID) (bid *model.Bid, err error) {
+func checkBidLimitTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	tenderId uuid.UUID,
+	authorType model.BidAuthorType,
+	authorId uuid.UUID,
+	stage model.BidStage,
+	maxBids int,
+) error {
+	lockQuery := `
+		SELECT id FROM tender WHERE id = $1 FOR NO KEY UPDATE;
+	`
+	countQuery := `
+		SELECT COUNT(*) FROM bid
+		WHERE tender_id = $1 AND author_type = $2 AND author_id = $3
+		AND stage = $4 AND status <> 'Canceled';
+	`
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, lockQuery, tenderId).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = tx.QueryRowContext(
+		ctx, countQuery, tenderId, authorType, authorId, stage,
+	).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count >= maxBids {
+		return ErrorBidLimitExceeded
+	}
+	return nil
+}
+
+func (repo *BidRepo) GetBidById(ctx context.Context, id uuid.UUID) (bid *model.Bid, err error) {
+	defer metrics.ObserveQuery("bid", "GetBidById", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.GetBidById")
+	defer span.End()
 	selectQuery := `
 		SELECT id, name, description,
 		status, tender_id, author_type,
-		author_id, version, created_at
+		author_id, version, created_at,
+		creator_user_id, amount, stage
 		FROM bid
 		WHERE id = $1;
 	`
 	bid = &model.Bid{}
-	err = repo.db.QueryRow(
-		selectQuery, id,
+	err = repo.db.QueryRowContext(
+		ctx, selectQuery, id,
 	).Scan(
 		&bid.Id,
 		&bid.Name,
@@ -182,14 +314,25 @@ func (repo *BidRepo) GetBidById(id uuid.UUID) (bid *model.Bid, err error) {
 		&bid.AuthorId,
 		&bid.Version,
 		&bid.CreatedAt,
+		&bid.CreatorUserId,
+		&bid.Amount,
+		&bid.Stage,
 	)
+	if err != nil {
+		return
+	}
+
+	err = repo.loadLots(ctx, []*model.Bid{bid})
 	return
 }
 
 func (repo *BidRepo) UpdateBidStatusById(
-	id uuid.UUID, status model.BidStatus,
+	ctx context.Context, id uuid.UUID, status model.BidStatus,
 ) (bid *model.Bid, err error) {
-	tx, err := repo.db.Begin()
+	defer metrics.ObserveQuery("bid", "UpdateBidStatusById", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.UpdateBidStatusById")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return
 	}
@@ -197,13 +340,14 @@ func (repo *BidRepo) UpdateBidStatusById(
 	selectQuery := `
 		SELECT id, name, description,
 		status, tender_id, author_type,
-		author_id, version, created_at
+		author_id, version, created_at,
+		creator_user_id, amount, stage
 		FROM bid
 		WHERE id = $1;
 	`
 	bid = &model.Bid{}
-	err = tx.QueryRow(
-		selectQuery, id,
+	err = tx.QueryRowContext(
+		ctx, selectQuery, id,
 	).Scan(
 		&bid.Id,
 		&bid.Name,
@@ -214,6 +358,9 @@ func (repo *BidRepo) UpdateBidStatusById(
 		&bid.AuthorId,
 		&bid.Version,
 		&bid.CreatedAt,
+		&bid.CreatorUserId,
+		&bid.Amount,
+		&bid.Stage,
 	)
 
 	if err != nil {
@@ -225,10 +372,12 @@ func (repo *BidRepo) UpdateBidStatusById(
 		INSERT INTO bid_history
 		(id, name, description,
 		status, tender_id, author_type,
-		author_id, version, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+		author_id, version, created_at,
+		creator_user_id, amount, stage)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
 	`
-	_, err = tx.Exec(
+	_, err = tx.ExecContext(
+		ctx,
 		createQuery,
 		bid.Id,
 		bid.Name,
@@ -239,6 +388,9 @@ func (repo *BidRepo) UpdateBidStatusById(
 		bid.AuthorId,
 		bid.Version,
 		bid.CreatedAt,
+		bid.CreatorUserId,
+		bid.Amount,
+		bid.Stage,
 	)
 
 	if err != nil {
@@ -253,21 +405,42 @@ func (repo *BidRepo) UpdateBidStatusById(
 	`
 	bid.Version += 1
 	bid.Status = status
-	_, err = tx.Exec(updateQuery, status, bid.Version, id)
+	_, err = tx.ExecContext(ctx, updateQuery, status, bid.Version, id)
 	if err != nil {
 		tx.Rollback()
 		return
 	}
 
-	tx.Commit()
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, id, bid.Version-1, bid.Version, uuid.Nil,
+	)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
 
+	err = refreshReputationTx(ctx, tx, id)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+
+	err = repo.loadLots(ctx, []*model.Bid{bid})
 	return
 }
 
 func (repo *BidRepo) EditBidById(
-	id uuid.UUID, name string, description string,
+	ctx context.Context, id uuid.UUID, name string, description string, amount *float64,
 ) (bid *model.Bid, err error) {
-	tx, err := repo.db.Begin()
+	defer metrics.ObserveQuery("bid", "EditBidById", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.EditBidById")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return
 	}
@@ -275,13 +448,14 @@ func (repo *BidRepo) EditBidById(
 	selectQuery := `
 		SELECT id, name, description,
 		status, tender_id, author_type,
-		author_id, version, created_at
+		author_id, version, created_at,
+		creator_user_id, amount, stage
 		FROM bid
 		WHERE id = $1;
 	`
 	bid = &model.Bid{}
-	err = tx.QueryRow(
-		selectQuery, id,
+	err = tx.QueryRowContext(
+		ctx, selectQuery, id,
 	).Scan(
 		&bid.Id,
 		&bid.Name,
@@ -292,6 +466,9 @@ func (repo *BidRepo) EditBidById(
 		&bid.AuthorId,
 		&bid.Version,
 		&bid.CreatedAt,
+		&bid.CreatorUserId,
+		&bid.Amount,
+		&bid.Stage,
 	)
 
 	if err != nil {
@@ -303,10 +480,12 @@ func (repo *BidRepo) EditBidById(
 		INSERT INTO bid_history
 		(id, name, description,
 		status, tender_id, author_type,
-		author_id, version, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+		author_id, version, created_at,
+		creator_user_id, amount, stage)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
 	`
-	_, err = tx.Exec(
+	_, err = tx.ExecContext(
+		ctx,
 		createQuery,
 		bid.Id,
 		bid.Name,
@@ -317,6 +496,9 @@ func (repo *BidRepo) EditBidById(
 		bid.AuthorId,
 		bid.Version,
 		bid.CreatedAt,
+		bid.CreatorUserId,
+		bid.Amount,
+		bid.Stage,
 	)
 
 	if err != nil {
@@ -326,29 +508,50 @@ func (repo *BidRepo) EditBidById(
 
 	updateQuery := `
 		UPDATE bid 
-		SET name = $1, description = $2, version = $3
-		WHERE id = $4;
+		SET name = $1, description = $2, amount = $3, version = $4
+		WHERE id = $5;
 	`
 	bid.Version += 1
 	bid.Name = name
 	bid.Description = description
-	_, err = tx.Exec(
-		updateQuery, bid.Name, bid.Description, bid.Version, id,
+	bid.Amount = amount
+	_, err = tx.ExecContext(
+		ctx, updateQuery, bid.Name, bid.Description, bid.Amount, bid.Version, id,
+	)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, id, bid.Version-1, bid.Version, uuid.Nil,
 	)
 	if err != nil {
 		tx.Rollback()
 		return
 	}
 
-	tx.Commit()
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
 
+	err = repo.loadLots(ctx, []*model.Bid{bid})
 	return
 }
 
 func (repo *BidRepo) CreateReviewById(
-	id uuid.UUID, description string,
+	ctx context.Context,
+	id uuid.UUID,
+	reviewerId uuid.UUID,
+	description string,
+	rating *int,
+	tags []model.ReviewTag,
 ) (bid *model.Bid, err error) {
-	tx, err := repo.db.Begin()
+	defer metrics.ObserveQuery("bid", "CreateReviewById", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.CreateReviewById")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return
 	}
@@ -356,13 +559,14 @@ func (repo *BidRepo) CreateReviewById(
 	selectQuery := `
 		SELECT id, name, description,
 		status, tender_id, author_type,
-		author_id, version, created_at
+		author_id, version, created_at,
+		creator_user_id, amount, stage
 		FROM bid
 		WHERE id = $1;
 	`
 	bid = &model.Bid{}
-	err = tx.QueryRow(
-		selectQuery, id,
+	err = tx.QueryRowContext(
+		ctx, selectQuery, id,
 	).Scan(
 		&bid.Id,
 		&bid.Name,
@@ -373,6 +577,9 @@ func (repo *BidRepo) CreateReviewById(
 		&bid.AuthorId,
 		&bid.Version,
 		&bid.CreatedAt,
+		&bid.CreatorUserId,
+		&bid.Amount,
+		&bid.Stage,
 	)
 
 	if err != nil {
@@ -382,25 +589,35 @@ func (repo *BidRepo) CreateReviewById(
 
 	createQuery := `
 		INSERT INTO review
-		(description, bid_id)
-		VALUES ($1, $2);
+		(description, bid_id, reviewer_id, rating, tags)
+		VALUES ($1, $2, $3, $4, $5);
 	`
-	_, err = tx.Exec(createQuery, description, id)
+	_, err = tx.ExecContext(
+		ctx, createQuery, description, id, reviewerId, rating, pq.Array(tags),
+	)
 
 	if err != nil {
 		tx.Rollback()
 		return
 	}
 
-	tx.Commit()
+	err = refreshReputationTx(ctx, tx, id)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
 
+	err = tx.Commit()
 	return
 }
 
 func (repo *BidRepo) RollbackById(
-	id uuid.UUID, version int32,
+	ctx context.Context, id uuid.UUID, version int32,
 ) (bid *model.Bid, err error) {
-	tx, err := repo.db.Begin()
+	defer metrics.ObserveQuery("bid", "RollbackById", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.RollbackById")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return
 	}
@@ -408,13 +625,14 @@ func (repo *BidRepo) RollbackById(
 	selectQuery := `
 		SELECT id, name, description,
 		status, tender_id, author_type,
-		author_id, version, created_at
+		author_id, version, created_at,
+		creator_user_id, amount, stage
 		FROM bid
 		WHERE id = $1;
 	`
 	bid = &model.Bid{}
-	err = tx.QueryRow(
-		selectQuery, id,
+	err = tx.QueryRowContext(
+		ctx, selectQuery, id,
 	).Scan(
 		&bid.Id,
 		&bid.Name,
@@ -425,6 +643,9 @@ func (repo *BidRepo) RollbackById(
 		&bid.AuthorId,
 		&bid.Version,
 		&bid.CreatedAt,
+		&bid.CreatorUserId,
+		&bid.Amount,
+		&bid.Stage,
 	)
 
 	if err != nil {
@@ -436,10 +657,12 @@ func (repo *BidRepo) RollbackById(
 		INSERT INTO bid_history
 		(id, name, description,
 		status, tender_id, author_type,
-		author_id, version, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+		author_id, version, created_at,
+		creator_user_id, amount, stage)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
 	`
-	_, err = tx.Exec(
+	_, err = tx.ExecContext(
+		ctx,
 		createQuery,
 		bid.Id,
 		bid.Name,
@@ -450,6 +673,9 @@ func (repo *BidRepo) RollbackById(
 		bid.AuthorId,
 		bid.Version,
 		bid.CreatedAt,
+		bid.CreatorUserId,
+		bid.Amount,
+		bid.Stage,
 	)
 
 	if err != nil {
@@ -459,13 +685,14 @@ func (repo *BidRepo) RollbackById(
 
 	selectVersionQuery := `
 		SELECT name, description, status, 
-		tender_id, author_type, author_id
+		tender_id, author_type, author_id,
+		amount
 		FROM bid_history
 		WHERE id = $1 AND version = $2;
 	`
 	bid.Version += 1
-	err = tx.QueryRow(
-		selectVersionQuery, id, version,
+	err = tx.QueryRowContext(
+		ctx, selectVersionQuery, id, version,
 	).Scan(
 		&bid.Name,
 		&bid.Description,
@@ -473,6 +700,7 @@ func (repo *BidRepo) RollbackById(
 		&bid.TenderId,
 		&bid.AuthorType,
 		&bid.AuthorId,
+		&bid.Amount,
 	)
 
 	if err != nil {
@@ -484,10 +712,11 @@ func (repo *BidRepo) RollbackById(
 		UPDATE bid 
 		SET name = $1, description = $2, 
 		status = $3, tender_id = $4, author_type = $5,
-		author_id = $6, version = $7
-		WHERE id = $8;
+		author_id = $6, amount = $7, version = $8
+		WHERE id = $9;
 	`
-	_, err = tx.Exec(
+	_, err = tx.ExecContext(
+		ctx,
 		updateQuery,
 		bid.Name,
 		bid.Description,
@@ -495,6 +724,7 @@ func (repo *BidRepo) RollbackById(
 		bid.TenderId,
 		bid.AuthorType,
 		bid.AuthorId,
+		bid.Amount,
 		bid.Version,
 		id,
 	)
@@ -504,22 +734,48 @@ func (repo *BidRepo) RollbackById(
 		return
 	}
 
-	tx.Commit()
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, id, version, bid.Version, uuid.Nil,
+	)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = refreshReputationTx(ctx, tx, id)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
 
+	err = repo.loadLots(ctx, []*model.Bid{bid})
 	return
 }
 
 func (repo *BidRepo) GetReviews(
+	ctx context.Context,
 	offset int,
 	limit int,
 	authorId uuid.UUID,
 	tenderId uuid.UUID,
 ) (reviews []*model.Review, err error) {
+	defer metrics.ObserveQuery("bid", "GetReviews", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.GetReviews")
+	defer span.End()
 	selectQuery := `
-		SELECT review.id, review.description, review.created_at 
+		SELECT review.id, review.bid_id, review.reviewer_id,
+		review.description, review.rating, review.tags,
+		review.version, review.created_at, review.updated_at
 		FROM bid 
 		INNER JOIN review on bid.id = review.bid_id
 		WHERE bid.tender_id = $1 and bid.author_id=$2
+		AND review.deleted_at IS NULL
+		ORDER BY review.created_at ASC
 	`
 	if limit > 0 {
 		selectQuery += " LIMIT " + strconv.Itoa(limit)
@@ -531,41 +787,486 @@ func (repo *BidRepo) GetReviews(
 
 	selectQuery += ";"
 
-	rows, err := repo.db.Query(selectQuery, tenderId, authorId)
+	rows, err := repo.db.QueryContext(ctx, selectQuery, tenderId, authorId)
 	if err != nil {
 		return
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var review model.Review
+		var review *model.Review
+		review, err = scanReview(rows)
+		if err != nil {
+			return
+		}
+		reviews = append(reviews, review)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	err = repo.loadReplies(ctx, reviews)
+	return
+}
+
+func (repo *BidRepo) GetReviewById(
+	ctx context.Context, id uuid.UUID,
+) (review *model.Review, err error) {
+	defer metrics.ObserveQuery("bid", "GetReviewById", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.GetReviewById")
+	defer span.End()
+	selectQuery := `
+		SELECT id, bid_id, reviewer_id,
+		description, rating, tags,
+		version, created_at, updated_at
+		FROM review
+		WHERE id = $1 AND deleted_at IS NULL;
+	`
+	review, err = scanReview(repo.db.QueryRowContext(ctx, selectQuery, id))
+	if err != nil {
+		return
+	}
+	err = repo.loadReplies(ctx, []*model.Review{review})
+	return
+}
+
+func (repo *BidRepo) EditReview(
+	ctx context.Context, reviewUpd *model.Review,
+) (review *model.Review, err error) {
+	defer metrics.ObserveQuery("bid", "EditReview", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.EditReview")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	err = createReviewHistoryTx(ctx, tx, reviewUpd.Id)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	updateQuery := `
+		UPDATE review
+		SET description = $1, rating = $2, tags = $3,
+		version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING id, bid_id, reviewer_id,
+		description, rating, tags,
+		version, created_at, updated_at;
+	`
+	review, err = scanReview(tx.QueryRowContext(
+		ctx,
+		updateQuery,
+		reviewUpd.Description,
+		reviewUpd.Rating,
+		pq.Array(reviewUpd.Tags),
+		reviewUpd.Id,
+	))
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = refreshReputationTx(ctx, tx, review.BidId)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+	err = repo.loadReplies(ctx, []*model.Review{review})
+	return
+}
+
+func (repo *BidRepo) DeleteReview(ctx context.Context, id uuid.UUID) error {
+	defer metrics.ObserveQuery("bid", "DeleteReview", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.DeleteReview")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = createReviewHistoryTx(ctx, tx, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	updateQuery := `
+		UPDATE review
+		SET deleted_at = CURRENT_TIMESTAMP, version = version + 1,
+		updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING bid_id;
+	`
+	var bidId uuid.UUID
+	err = tx.QueryRowContext(ctx, updateQuery, id).Scan(&bidId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = refreshReputationTx(ctx, tx, bidId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *BidRepo) CreateReply(
+	ctx context.Context, reviewId uuid.UUID, authorId uuid.UUID, text string,
+) (reply *model.ReviewReply, err error) {
+	defer metrics.ObserveQuery("bid", "CreateReply", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.CreateReply")
+	defer span.End()
+	createQuery := `
+		INSERT INTO review_reply
+		(review_id, author_id, text)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at;
+	`
+	reply = &model.ReviewReply{
+		ReviewId: reviewId,
+		AuthorId: authorId,
+		Text:     text,
+	}
+	err = repo.db.QueryRowContext(
+		ctx, createQuery, reviewId, authorId, text,
+	).Scan(&reply.Id, &reply.CreatedAt)
+	return
+}
+
+func (repo *BidRepo) loadReplies(ctx context.Context, reviews []*model.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+	reviewsById := make(map[uuid.UUID]*model.Review, len(reviews))
+	ids := make([]string, 0, len(reviews))
+	for _, review := range reviews {
+		review.Replies = []*model.ReviewReply{}
+		reviewsById[review.Id] = review
+		ids = append(ids, review.Id.String())
+	}
+
+	selectQuery := `
+		SELECT id, review_id, author_id, text, created_at
+		FROM review_reply
+		WHERE review_id = ANY($1::uuid[])
+		ORDER BY created_at ASC;
+	`
+	rows, err := repo.db.QueryContext(ctx, selectQuery, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reply model.ReviewReply
 		err = rows.Scan(
-			&review.Id,
-			&review.Description,
-			&review.CreatedAt,
+			&reply.Id,
+			&reply.ReviewId,
+			&reply.AuthorId,
+			&reply.Text,
+			&reply.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		review := reviewsById[reply.ReviewId]
+		review.Replies = append(review.Replies, &reply)
+	}
+	return rows.Err()
+}
+
+func createReviewHistoryTx(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+	createHistoryQuery := `
+		INSERT INTO review_history
+		(id, bid_id, reviewer_id, description,
+		rating, tags, version, updated_at)
+		SELECT id, bid_id, reviewer_id, description,
+		rating, tags, version, updated_at
+		FROM review
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE;
+	`
+	res, err := tx.ExecContext(ctx, createHistoryQuery, id)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReview(row scanner) (*model.Review, error) {
+	review := &model.Review{}
+	var reviewerId uuid.NullUUID
+	var rating sql.NullInt32
+	var tags []string
+	err := row.Scan(
+		&review.Id,
+		&review.BidId,
+		&reviewerId,
+		&review.Description,
+		&rating,
+		pq.Array(&tags),
+		&review.Version,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if reviewerId.Valid {
+		review.ReviewerId = &reviewerId.UUID
+	}
+	if rating.Valid {
+		value := int(rating.Int32)
+		review.Rating = &value
+	}
+	review.Tags = make([]model.ReviewTag, 0, len(tags))
+	for _, tag := range tags {
+		review.Tags = append(review.Tags, model.ReviewTag(tag))
+	}
+	return review, nil
+}
+
+func (repo *BidRepo) AddAttachment(
+	ctx context.Context, bidId uuid.UUID, attachment *model.Attachment,
+) (bid *model.Bid, err error) {
+	defer metrics.ObserveQuery("bid", "AddAttachment", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.AddAttachment")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	bid, err = repo.newVersionTx(ctx, tx, bidId)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, bidId, bid.Version-1, bid.Version, uuid.Nil,
+	)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = attachmentRepo.CreateAttachmentTx(ctx, tx, attachment, bid.Version)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+
+	err = repo.loadLots(ctx, []*model.Bid{bid})
+	return
+}
+
+func (repo *BidRepo) RemoveAttachment(
+	ctx context.Context, bidId uuid.UUID, attachmentId uuid.UUID,
+) (bid *model.Bid, err error) {
+	defer metrics.ObserveQuery("bid", "RemoveAttachment", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.RemoveAttachment")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	bid, err = repo.newVersionTx(ctx, tx, bidId)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, bidId, bid.Version-1, bid.Version, attachmentId,
+	)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+
+	err = repo.loadLots(ctx, []*model.Bid{bid})
+	return
+}
+
+func (repo *BidRepo) newVersionTx(
+	ctx context.Context, tx *sql.Tx, id uuid.UUID,
+) (bid *model.Bid, err error) {
+	selectQuery := `
+		SELECT id, name, description,
+		status, tender_id, author_type,
+		author_id, version, created_at,
+		creator_user_id, amount, stage
+		FROM bid
+		WHERE id = $1
+		FOR UPDATE;
+	`
+	bid = &model.Bid{}
+	err = tx.QueryRowContext(
+		ctx, selectQuery, id,
+	).Scan(
+		&bid.Id,
+		&bid.Name,
+		&bid.Description,
+		&bid.Status,
+		&bid.TenderId,
+		&bid.AuthorType,
+		&bid.AuthorId,
+		&bid.Version,
+		&bid.CreatedAt,
+		&bid.CreatorUserId,
+		&bid.Amount,
+		&bid.Stage,
+	)
+	if err != nil {
+		return
+	}
+
+	createQuery := `
+		INSERT INTO bid_history
+		(id, name, description,
+		status, tender_id, author_type,
+		author_id, version, created_at,
+		creator_user_id, amount, stage)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+	`
+	_, err = tx.ExecContext(
+		ctx,
+		createQuery,
+		bid.Id,
+		bid.Name,
+		bid.Description,
+		bid.Status,
+		bid.TenderId,
+		bid.AuthorType,
+		bid.AuthorId,
+		bid.Version,
+		bid.CreatedAt,
+		bid.CreatorUserId,
+		bid.Amount,
+		bid.Stage,
+	)
+	if err != nil {
+		return
+	}
+
+	bid.Version += 1
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE bid SET version = $1 WHERE id = $2;`,
+		bid.Version,
+		bid.Id,
+	)
+	return
+}
+
+func (repo *BidRepo) GetBidHistory(
+	ctx context.Context, id uuid.UUID,
+) (bids []*model.Bid, err error) {
+	defer metrics.ObserveQuery("bid", "GetBidHistory", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.GetBidHistory")
+	defer span.End()
+	selectQuery := `
+		SELECT id, name, description,
+		status, tender_id, author_type,
+		author_id, version, created_at,
+		creator_user_id, amount, stage
+		FROM bid_history
+		WHERE id = $1
+		ORDER BY version ASC;
+	`
+	rows, err := repo.db.QueryContext(ctx, selectQuery, id)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bid model.Bid
+		err = rows.Scan(
+			&bid.Id,
+			&bid.Name,
+			&bid.Description,
+			&bid.Status,
+			&bid.TenderId,
+			&bid.AuthorType,
+			&bid.AuthorId,
+			&bid.Version,
+			&bid.CreatedAt,
+			&bid.CreatorUserId,
+			&bid.Amount,
+			&bid.Stage,
 		)
 		if err != nil {
 			return
 		}
-		reviews = append(reviews, &review)
+		bids = append(bids, &bid)
 	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	err = repo.loadLots(ctx, bids)
 	return
 }
 
-func (repo *BidRepo) GetDisicions(id uuid.UUID) (rejects int, approves int, err error) {
+func (repo *BidRepo) GetDisicions(
+	ctx context.Context, id uuid.UUID,
+) (rejects int, approves int, err error) {
+	defer metrics.ObserveQuery("bid", "GetDisicions", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.GetDisicions")
+	defer span.End()
 	selectQuery := `
 		SELECT rejects, approves
 		FROM bid
 		WHERE id = $1;
 	`
-	err = repo.db.QueryRow(selectQuery, id).Scan(&rejects, &approves)
+	err = repo.db.QueryRowContext(ctx, selectQuery, id).Scan(&rejects, &approves)
 	return
 }
 
 func (repo *BidRepo) UpdateApproves(
-	bidId uuid.UUID, approves int, tenderId uuid.UUID, closeTender bool,
+	ctx context.Context, bidId uuid.UUID, approves int, tenderId uuid.UUID, closeTender bool,
 ) error {
-	tx, err := repo.db.Begin()
+	defer metrics.ObserveQuery("bid", "UpdateApproves", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.UpdateApproves")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -575,7 +1276,7 @@ func (repo *BidRepo) UpdateApproves(
 		SET approves = $1
 		WHERE id = $2;
 	`
-	_, err = tx.Exec(updateQuery, approves, bidId)
+	_, err = tx.ExecContext(ctx, updateQuery, approves, bidId)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -583,29 +1284,103 @@ func (repo *BidRepo) UpdateApproves(
 
 	if closeTender {
 		updateQuery = `
-			UPDATE tender 
-			SET status = 'Closed'
+			UPDATE bid 
+			SET won = true
+			WHERE id = $1;
+		`
+		_, err = tx.ExecContext(ctx, updateQuery, bidId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = refreshReputationTx(ctx, tx, bidId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = lotRepo.CloseTenderIfLotsDoneTx(ctx, tx, tenderId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (repo *BidRepo) UpdateLotApproves(
+	ctx context.Context, bidId uuid.UUID, lotId uuid.UUID, approves int,
+	tenderId uuid.UUID, award bool,
+) error {
+	defer metrics.ObserveQuery("bid", "UpdateLotApproves", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.UpdateLotApproves")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = lotRepo.UpdateApprovesTx(ctx, tx, bidId, lotId, approves, award)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if award {
+		updateQuery := `
+			UPDATE bid 
+			SET won = true
 			WHERE id = $1;
 		`
-		_, err = tx.Exec(updateQuery, tenderId)
+		_, err = tx.ExecContext(ctx, updateQuery, bidId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = refreshReputationTx(ctx, tx, bidId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = lotRepo.CloseTenderIfLotsDoneTx(ctx, tx, tenderId)
 		if err != nil {
 			tx.Rollback()
 			return err
 		}
 	}
 
-	tx.Commit()
+	return tx.Commit()
+}
 
+func (repo *BidRepo) loadLots(ctx context.Context, bids []*model.Bid) error {
+	ids := make([]uuid.UUID, 0, len(bids))
+	for _, bid := range bids {
+		ids = append(ids, bid.Id)
+	}
+	lotIds, err := lotRepo.GetLotIdsByBidIds(ctx, repo.db, ids)
+	if err != nil {
+		return err
+	}
+	for _, bid := range bids {
+		bid.LotIds = lotIds[bid.Id]
+	}
 	return nil
 }
 
-func (repo *BidRepo) UpdateRejects(bidId uuid.UUID, rejects int) error {
+func (repo *BidRepo) UpdateRejects(ctx context.Context, bidId uuid.UUID, rejects int) error {
+	defer metrics.ObserveQuery("bid", "UpdateRejects", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.UpdateRejects")
+	defer span.End()
 	updateQuery := `
 		UPDATE bid 
 		SET rejects = $1
 		WHERE id = $2;
 	`
-	_, err := repo.db.Exec(updateQuery, rejects, bidId)
+	_, err := repo.db.ExecContext(ctx, updateQuery, rejects, bidId)
 	return err
 }
 
@@ -615,8 +1390,91 @@ func NewRepo() (repo *BidRepo, err error) {
 		return
 	}
 	repo = &BidRepo{db: db}
+	return
+}
+
+func Migrate() (err error) {
+	repo, err := NewRepo()
+	if err != nil {
+		return
+	}
+
+	table, err := repo.tableExists("bid")
+	if err != nil {
+		return
+	}
+
+	if !table {
+		err = repo.createTable()
+		if err == nil {
+			slog.Info("Tables 'bid', 'bid_histrory' and 'review' are created")
+		} else {
+			slog.Info("Can not create tables 'bid', 'bid_histrory' and 'review'")
+			return
+		}
+	}
 
-	table, err := repo.tableExists()
+	table, err = repo.tableExists("review_reply")
+	if err != nil {
+		return
+	}
+
+	if !table {
+		err = repo.migrateReviewTable()
+		if err == nil {
+			slog.Info("Table 'review' is extended, tables 'review_history' and 'review_reply' are created")
+		} else {
+			slog.Info("Can not extend table 'review'")
+			return
+		}
+	}
+
+	column, err := repo.columnExists("bid", "creator_user_id")
+	if err != nil {
+		return
+	}
+
+	if !column {
+		err = repo.addCreatorColumn()
+		if err == nil {
+			slog.Info("Column 'creator_user_id' is added to tables 'bid' and 'bid_history'")
+		} else {
+			slog.Info("Can not add column 'creator_user_id'")
+			return
+		}
+	}
+
+	column, err = repo.columnExists("bid", "amount")
+	if err != nil {
+		return
+	}
+
+	if !column {
+		err = repo.addAmountColumn()
+		if err == nil {
+			slog.Info("Column 'amount' is added to tables 'bid' and 'bid_history'")
+		} else {
+			slog.Info("Can not add column 'amount'")
+			return
+		}
+	}
+
+	column, err = repo.columnExists("bid", "stage")
+	if err != nil {
+		return
+	}
+
+	if !column {
+		err = repo.addStageColumn()
+		if err == nil {
+			slog.Info("Column 'stage' is added to tables 'bid' and 'bid_history'")
+		} else {
+			slog.Info("Can not add column 'stage'")
+			return
+		}
+	}
+
+	table, err = repo.tableExists("reputation")
 	if err != nil {
 		return
 	}
@@ -625,20 +1483,20 @@ func NewRepo() (repo *BidRepo, err error) {
 		return
 	}
 
-	err = repo.createTable()
+	err = repo.createReputationTable()
 	if err == nil {
-		slog.Info("Tables 'bid', 'bid_histrory' and 'review' are created")
+		slog.Info("Table 'reputation' is created")
 	} else {
-		slog.Info("Can not create tables 'bid', 'bid_histrory' and 'review'")
+		slog.Info("Can not create table 'reputation'")
 	}
-
 	return
 }
 
-func (repo *BidRepo) tableExists() (table bool, err error) {
+func (repo *BidRepo) tableExists(name string) (table bool, err error) {
 	rows, err := repo.db.Query(
 		`SELECT EXISTS (SELECT FROM information_schema.tables 
-		WHERE table_name = 'bid');`,
+		WHERE table_name = $1);`,
+		name,
 	)
 	if err != nil {
 		return
@@ -654,11 +1512,25 @@ func (repo *BidRepo) tableExists() (table bool, err error) {
 	return
 }
 
+func (repo *BidRepo) columnExists(table string, name string) (column bool, err error) {
+	err = repo.db.QueryRow(
+		`SELECT EXISTS (SELECT FROM information_schema.columns 
+		WHERE table_name = $1 AND column_name = $2);`,
+		table,
+		name,
+	).Scan(&column)
+	return
+}
+
 func (repo *BidRepo) createTable() error {
 	createBidStatus := `
 		CREATE TYPE bid_status 
 		AS ENUM ('Created', 'Published', 'Canceled');
 	`
+	createBidStage := `
+		CREATE TYPE bid_stage
+		AS ENUM ('Prequalification', 'Commercial');
+	`
 	createBidAuthorType := `
 		CREATE TYPE bid_author_type 
 		AS ENUM ('User', 'Organization');
@@ -674,6 +1546,9 @@ func (repo *BidRepo) createTable() error {
 		author_id UUID NOT NULL,
 		version INT DEFAULT 1,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		creator_user_id UUID,
+		amount NUMERIC(15,2),
+		stage bid_stage DEFAULT 'Commercial',
 		rejects INT DEFAULT 0,
 		approves INT DEFAULT 0);
 	`
@@ -688,6 +1563,9 @@ func (repo *BidRepo) createTable() error {
 		author_id UUID NOT NULL,
 		version INT NOT NULL,
 		created_at TIMESTAMP NOT NULL,
+		creator_user_id UUID,
+		amount NUMERIC(15,2),
+		stage bid_stage DEFAULT 'Commercial',
 		PRIMARY KEY (id, version));
 	`
 	createReviewTable := `
@@ -700,9 +1578,95 @@ func (repo *BidRepo) createTable() error {
 	_, err := repo.db.Exec(
 		createBidAuthorType +
 			createBidStatus +
+			createBidStage +
 			createBidHistoryTable +
 			createBidTable +
 			createReviewTable,
 	)
 	return err
 }
+
+func (repo *BidRepo) migrateReviewTable() error {
+	alterReviewTable := `
+		ALTER TABLE review
+		ADD COLUMN IF NOT EXISTS reviewer_id UUID,
+		ADD COLUMN IF NOT EXISTS rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+		ADD COLUMN IF NOT EXISTS tags TEXT[] DEFAULT '{}',
+		ADD COLUMN IF NOT EXISTS version INT DEFAULT 1,
+		ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+	`
+	createReviewHistoryTable := `
+		CREATE TABLE IF NOT EXISTS review_history (
+		id UUID NOT NULL,
+		bid_id UUID NOT NULL,
+		reviewer_id UUID,
+		description VARCHAR(1000) NOT NULL,
+		rating SMALLINT,
+		tags TEXT[],
+		version INT NOT NULL,
+		updated_at TIMESTAMP,
+		PRIMARY KEY (id, version));
+	`
+	createReviewReplyTable := `
+		CREATE TABLE IF NOT EXISTS review_reply (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		review_id UUID REFERENCES review(id),
+		author_id UUID NOT NULL,
+		text VARCHAR(1000) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+	`
+	_, err := repo.db.Exec(
+		alterReviewTable +
+			createReviewHistoryTable +
+			createReviewReplyTable,
+	)
+	return err
+}
+
+func (repo *BidRepo) addCreatorColumn() error {
+	alterBidTable := `
+		ALTER TABLE bid
+		ADD COLUMN IF NOT EXISTS creator_user_id UUID;
+	`
+	alterBidHistoryTable := `
+		ALTER TABLE bid_history
+		ADD COLUMN IF NOT EXISTS creator_user_id UUID;
+	`
+	_, err := repo.db.Exec(alterBidTable + alterBidHistoryTable)
+	return err
+}
+
+func (repo *BidRepo) addAmountColumn() error {
+	alterBidTable := `
+		ALTER TABLE bid
+		ADD COLUMN IF NOT EXISTS amount NUMERIC(15,2);
+	`
+	alterBidHistoryTable := `
+		ALTER TABLE bid_history
+		ADD COLUMN IF NOT EXISTS amount NUMERIC(15,2);
+	`
+	_, err := repo.db.Exec(alterBidTable + alterBidHistoryTable)
+	return err
+}
+
+func (repo *BidRepo) addStageColumn() error {
+	createBidStage := `
+		DO $$ BEGIN
+			CREATE TYPE bid_stage
+			AS ENUM ('Prequalification', 'Commercial');
+		EXCEPTION
+			WHEN duplicate_object THEN NULL;
+		END $$;
+	`
+	alterBidTable := `
+		ALTER TABLE bid
+		ADD COLUMN IF NOT EXISTS stage bid_stage DEFAULT 'Commercial';
+	`
+	alterBidHistoryTable := `
+		ALTER TABLE bid_history
+		ADD COLUMN IF NOT EXISTS stage bid_stage DEFAULT 'Commercial';
+	`
+	_, err := repo.db.Exec(createBidStage + alterBidTable + alterBidHistoryTable)
+	return err
+}
@@ -0,0 +1,182 @@
+package bid
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"avi/internal/metrics"
+	"avi/internal/model"
+	"avi/internal/tracing"
+)
+
+func (repo *BidRepo) GetReputation(
+	ctx context.Context, authorType model.BidAuthorType, authorId uuid.UUID,
+) (reputation *model.Reputation, err error) {
+	defer metrics.ObserveQuery("bid", "GetReputation", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.GetReputation")
+	defer span.End()
+	reputation = &model.Reputation{
+		AuthorType: authorType,
+		AuthorId:   authorId,
+	}
+
+	selectQuery := `
+		SELECT bids, wins, cancellations,
+		rating_sum, rating_count, updated_at
+		FROM reputation
+		WHERE author_type = $1 AND author_id = $2;
+	`
+	var ratingSum int
+	var updatedAt sql.NullTime
+	err = repo.db.QueryRowContext(ctx, selectQuery, authorType, authorId).Scan(
+		&reputation.Bids,
+		&reputation.Wins,
+		&reputation.Cancellations,
+		&ratingSum,
+		&reputation.Ratings,
+		&updatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	if reputation.Ratings > 0 {
+		average := float64(ratingSum) / float64(reputation.Ratings)
+		reputation.AverageRating = &average
+	}
+	if updatedAt.Valid {
+		reputation.UpdatedAt = &updatedAt.Time
+	}
+	return
+}
+
+func (repo *BidRepo) GetRecentReviews(
+	ctx context.Context, authorType model.BidAuthorType, authorId uuid.UUID, limit int,
+) (reviews []*model.Review, err error) {
+	defer metrics.ObserveQuery("bid", "GetRecentReviews", time.Now())
+	ctx, span := tracing.Start(ctx, "BidRepo.GetRecentReviews")
+	defer span.End()
+	selectQuery := `
+		SELECT review.id, review.bid_id, review.reviewer_id,
+		review.description, review.rating, review.tags,
+		review.version, review.created_at, review.updated_at
+		FROM bid
+		INNER JOIN review on bid.id = review.bid_id
+		WHERE bid.author_type = $1 AND bid.author_id = $2
+		AND review.deleted_at IS NULL
+		ORDER BY review.created_at DESC
+		LIMIT $3;
+	`
+	rows, err := repo.db.QueryContext(ctx, selectQuery, authorType, authorId, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	reviews = []*model.Review{}
+	for rows.Next() {
+		var review *model.Review
+		review, err = scanReview(rows)
+		if err != nil {
+			return
+		}
+		reviews = append(reviews, review)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	err = repo.loadReplies(ctx, reviews)
+	return
+}
+
+func refreshReputationTx(ctx context.Context, tx *sql.Tx, bidId uuid.UUID) error {
+	refreshQuery := `
+		WITH author AS (
+			SELECT author_type, author_id FROM bid WHERE id = $1
+		), author_bids AS (
+			SELECT bid.id, bid.status, bid.won FROM bid
+			INNER JOIN author ON bid.author_type = author.author_type
+			AND bid.author_id = author.author_id
+		), author_ratings AS (
+			SELECT review.rating FROM review
+			INNER JOIN author_bids ON review.bid_id = author_bids.id
+			WHERE review.deleted_at IS NULL AND review.rating IS NOT NULL
+		)
+		INSERT INTO reputation
+		(author_type, author_id, bids, wins,
+		cancellations, rating_sum, rating_count, updated_at)
+		SELECT author.author_type, author.author_id,
+		(SELECT count(*) FROM author_bids),
+		(SELECT count(*) FROM author_bids WHERE won),
+		(SELECT count(*) FROM author_bids WHERE status = 'Canceled'),
+		(SELECT coalesce(sum(rating), 0) FROM author_ratings),
+		(SELECT count(*) FROM author_ratings),
+		CURRENT_TIMESTAMP
+		FROM author
+		ON CONFLICT (author_type, author_id) DO UPDATE
+		SET bids = EXCLUDED.bids,
+		wins = EXCLUDED.wins,
+		cancellations = EXCLUDED.cancellations,
+		rating_sum = EXCLUDED.rating_sum,
+		rating_count = EXCLUDED.rating_count,
+		updated_at = EXCLUDED.updated_at;
+	`
+	_, err := tx.ExecContext(ctx, refreshQuery, bidId)
+	return err
+}
+
+func (repo *BidRepo) createReputationTable() error {
+	alterBidTable := `
+		ALTER TABLE bid
+		ADD COLUMN IF NOT EXISTS won BOOLEAN DEFAULT false;
+	`
+	markWonBids := `
+		UPDATE bid SET won = true
+		FROM tender
+		WHERE bid.tender_id = tender.id
+		AND tender.status = 'Closed' AND bid.approves > 0;
+	`
+	createReputationTable := `
+		CREATE TABLE reputation (
+		author_type bid_author_type NOT NULL,
+		author_id UUID NOT NULL,
+		bids INT NOT NULL DEFAULT 0,
+		wins INT NOT NULL DEFAULT 0,
+		cancellations INT NOT NULL DEFAULT 0,
+		rating_sum INT NOT NULL DEFAULT 0,
+		rating_count INT NOT NULL DEFAULT 0,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (author_type, author_id));
+	`
+	fillReputationTable := `
+		INSERT INTO reputation
+		(author_type, author_id, bids, wins,
+		cancellations, rating_sum, rating_count)
+		SELECT bid.author_type, bid.author_id,
+		count(DISTINCT bid.id),
+		count(DISTINCT bid.id) FILTER (WHERE bid.won),
+		count(DISTINCT bid.id) FILTER (WHERE bid.status = 'Canceled'),
+		coalesce(sum(review.rating), 0),
+		count(review.rating)
+		FROM bid
+		LEFT JOIN review ON review.bid_id = bid.id
+		AND review.deleted_at IS NULL
+		GROUP BY bid.author_type, bid.author_id;
+	`
+	_, err := repo.db.Exec(
+		alterBidTable +
+			markWonBids +
+			createReputationTable +
+			fillReputationTable,
+	)
+	return err
+}
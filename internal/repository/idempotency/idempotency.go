@@ -0,0 +1,241 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"avi/internal/database"
+	"avi/internal/metrics"
+	"avi/internal/model"
+	"avi/internal/tracing"
+)
+
+type IdempotencyRepo struct {
+	db *sql.DB
+}
+
+func (repo *IdempotencyRepo) Acquire(
+	ctx context.Context,
+	key string,
+	username string,
+	method string,
+	path string,
+	requestHash string,
+	lockTimeout time.Duration,
+) (acquired bool, err error) {
+	defer metrics.ObserveQuery("idempotency", "Acquire", time.Now())
+	ctx, span := tracing.Start(ctx, "IdempotencyRepo.Acquire")
+	defer span.End()
+	acquireQuery := `
+		INSERT INTO idempotency_key (key, username, method, path, request_hash)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (key, username, method, path) DO UPDATE SET
+		request_hash = EXCLUDED.request_hash, locked_at = now(), created_at = now()
+		WHERE idempotency_key.status_code IS NULL
+		AND idempotency_key.locked_at < now() - $6 * INTERVAL '1 millisecond'
+		RETURNING TRUE;
+	`
+	err = repo.db.QueryRowContext(
+		ctx, acquireQuery, key, username, method, path, requestHash, lockTimeout.Milliseconds(),
+	).Scan(&acquired)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return
+}
+
+func (repo *IdempotencyRepo) GetRequest(
+	ctx context.Context, key string, username string, method string, path string,
+) (request *model.IdempotentRequest, err error) {
+	defer metrics.ObserveQuery("idempotency", "GetRequest", time.Now())
+	ctx, span := tracing.Start(ctx, "IdempotencyRepo.GetRequest")
+	defer span.End()
+	selectQuery := `
+		SELECT key, username, method, path, request_hash, status_code,
+		COALESCE(content_type, ''), response, created_at
+		FROM idempotency_key
+		WHERE key = $1 AND username = $2 AND method = $3 AND path = $4;
+	`
+	request = &model.IdempotentRequest{}
+	var statusCode sql.NullInt32
+	err = repo.db.QueryRowContext(ctx, selectQuery, key, username, method, path).Scan(
+		&request.Key,
+		&request.Username,
+		&request.Method,
+		&request.Path,
+		&request.RequestHash,
+		&statusCode,
+		&request.ContentType,
+		&request.Response,
+		&request.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode.Valid {
+		status := int(statusCode.Int32)
+		request.StatusCode = &status
+	}
+	return
+}
+
+func (repo *IdempotencyRepo) SaveResponse(
+	ctx context.Context,
+	key string,
+	username string,
+	method string,
+	path string,
+	statusCode int,
+	contentType string,
+	response []byte,
+) error {
+	defer metrics.ObserveQuery("idempotency", "SaveResponse", time.Now())
+	ctx, span := tracing.Start(ctx, "IdempotencyRepo.SaveResponse")
+	defer span.End()
+	updateQuery := `
+		UPDATE idempotency_key
+		SET status_code = $5, content_type = $6, response = $7
+		WHERE key = $1 AND username = $2 AND method = $3 AND path = $4;
+	`
+	_, err := repo.db.ExecContext(
+		ctx, updateQuery, key, username, method, path, statusCode, contentType, response,
+	)
+	return err
+}
+
+func (repo *IdempotencyRepo) Release(
+	ctx context.Context, key string, username string, method string, path string,
+) error {
+	defer metrics.ObserveQuery("idempotency", "Release", time.Now())
+	ctx, span := tracing.Start(ctx, "IdempotencyRepo.Release")
+	defer span.End()
+	deleteQuery := `
+		DELETE FROM idempotency_key
+		WHERE key = $1 AND username = $2 AND method = $3 AND path = $4
+		AND status_code IS NULL;
+	`
+	_, err := repo.db.ExecContext(ctx, deleteQuery, key, username, method, path)
+	return err
+}
+
+func (repo *IdempotencyRepo) DeleteExpired(
+	ctx context.Context, retention time.Duration,
+) (deleted int64, err error) {
+	defer metrics.ObserveQuery("idempotency", "DeleteExpired", time.Now())
+	ctx, span := tracing.Start(ctx, "IdempotencyRepo.DeleteExpired")
+	defer span.End()
+	deleteQuery := `
+		DELETE FROM idempotency_key
+		WHERE created_at < now() - $1 * INTERVAL '1 millisecond';
+	`
+	result, err := repo.db.ExecContext(ctx, deleteQuery, retention.Milliseconds())
+	if err != nil {
+		return
+	}
+	return result.RowsAffected()
+}
+
+func NewRepo() (repo *IdempotencyRepo, err error) {
+	db, err := database.Connect()
+	if err != nil {
+		return
+	}
+	repo = &IdempotencyRepo{db: db}
+	return
+}
+
+func Migrate() (err error) {
+	repo, err := NewRepo()
+	if err != nil {
+		return
+	}
+
+	table, err := repo.tableExists()
+	if err != nil {
+		return
+	}
+
+	if !table {
+		err = repo.createTable()
+		if err == nil {
+			slog.Info("Table 'idempotency_key' is created")
+		} else {
+			slog.Info("Can not create table 'idempotency_key'")
+		}
+		return
+	}
+
+	column, err := repo.columnExists("username")
+	if err != nil || column {
+		return
+	}
+
+	err = repo.addUsernameColumn()
+	if err == nil {
+		slog.Info("Column 'username' is added to primary key of table 'idempotency_key'")
+	} else {
+		slog.Info("Can not add column 'username' to table 'idempotency_key'")
+	}
+	return
+}
+
+func (repo *IdempotencyRepo) tableExists() (table bool, err error) {
+	rows, err := repo.db.Query(
+		`SELECT EXISTS (SELECT FROM information_schema.tables
+		WHERE table_name = 'idempotency_key');`,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&table)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (repo *IdempotencyRepo) columnExists(name string) (column bool, err error) {
+	err = repo.db.QueryRow(
+		`SELECT EXISTS (SELECT FROM information_schema.columns
+		WHERE table_name = 'idempotency_key' AND column_name = $1);`,
+		name,
+	).Scan(&column)
+	return
+}
+
+func (repo *IdempotencyRepo) addUsernameColumn() error {
+	_, err := repo.db.Exec(`
+		ALTER TABLE idempotency_key
+		ADD COLUMN IF NOT EXISTS username TEXT NOT NULL DEFAULT '',
+		DROP CONSTRAINT idempotency_key_pkey,
+		ADD PRIMARY KEY (key, username, method, path);
+	`)
+	return err
+}
+
+func (repo *IdempotencyRepo) createTable() error {
+	createIdempotencyTable := `
+		CREATE TABLE idempotency_key (
+		key VARCHAR(255) NOT NULL,
+		username TEXT NOT NULL DEFAULT '',
+		method VARCHAR(10) NOT NULL,
+		path TEXT NOT NULL,
+		request_hash VARCHAR(64) NOT NULL,
+		status_code INT,
+		content_type TEXT,
+		response BYTEA,
+		locked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (key, username, method, path));
+		CREATE INDEX idempotency_key_created_at_idx ON idempotency_key (created_at);
+	`
+	_, err := repo.db.Exec(createIdempotencyTable)
+	return err
+}
@@ -0,0 +1,219 @@
+package invitation
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"avi/internal/database"
+	"avi/internal/metrics"
+	"avi/internal/model"
+	"avi/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+type InvitationRepo struct {
+	db *sql.DB
+}
+
+func (repo *InvitationRepo) CreateInvitation(
+	ctx context.Context,
+	tenderId uuid.UUID,
+	inviteeType model.InviteeType,
+	inviteeId uuid.UUID,
+	invitedBy uuid.UUID,
+) (invitation *model.Invitation, err error) {
+	defer metrics.ObserveQuery("invitation", "CreateInvitation", time.Now())
+	ctx, span := tracing.Start(ctx, "InvitationRepo.CreateInvitation")
+	defer span.End()
+	createQuery := `
+		INSERT INTO invitation
+		(tender_id, invitee_type, invitee_id, invited_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tender_id, invitee_type, invitee_id)
+		DO UPDATE SET invitee_id = EXCLUDED.invitee_id
+		RETURNING id, invited_by, created_at;
+	`
+	invitation = &model.Invitation{
+		TenderId:    tenderId,
+		InviteeType: inviteeType,
+		InviteeId:   inviteeId,
+	}
+	err = repo.db.QueryRowContext(
+		ctx, createQuery, tenderId, inviteeType, inviteeId, invitedBy,
+	).Scan(&invitation.Id, &invitation.InvitedBy, &invitation.CreatedAt)
+	return
+}
+
+func (repo *InvitationRepo) GetInvitations(
+	ctx context.Context, tenderId uuid.UUID,
+) (invitations []*model.Invitation, err error) {
+	defer metrics.ObserveQuery("invitation", "GetInvitations", time.Now())
+	ctx, span := tracing.Start(ctx, "InvitationRepo.GetInvitations")
+	defer span.End()
+	selectQuery := `
+		SELECT id, tender_id, invitee_type,
+		invitee_id, invited_by, created_at
+		FROM invitation
+		WHERE tender_id = $1
+		ORDER BY created_at ASC;
+	`
+	rows, err := repo.db.QueryContext(ctx, selectQuery, tenderId)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	invitations = []*model.Invitation{}
+	for rows.Next() {
+		var invitation model.Invitation
+		err = rows.Scan(
+			&invitation.Id,
+			&invitation.TenderId,
+			&invitation.InviteeType,
+			&invitation.InviteeId,
+			&invitation.InvitedBy,
+			&invitation.CreatedAt,
+		)
+		if err != nil {
+			return
+		}
+		invitations = append(invitations, &invitation)
+	}
+	err = rows.Err()
+	return
+}
+
+func (repo *InvitationRepo) DeleteInvitation(
+	ctx context.Context, tenderId uuid.UUID, id uuid.UUID,
+) error {
+	defer metrics.ObserveQuery("invitation", "DeleteInvitation", time.Now())
+	ctx, span := tracing.Start(ctx, "InvitationRepo.DeleteInvitation")
+	defer span.End()
+	deleteQuery := `
+		DELETE FROM invitation
+		WHERE tender_id = $1 AND id = $2;
+	`
+	res, err := repo.db.ExecContext(ctx, deleteQuery, tenderId, id)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (repo *InvitationRepo) IsInvited(
+	ctx context.Context, tenderId uuid.UUID, inviteeType model.InviteeType, inviteeId uuid.UUID,
+) (invited bool, err error) {
+	defer metrics.ObserveQuery("invitation", "IsInvited", time.Now())
+	ctx, span := tracing.Start(ctx, "InvitationRepo.IsInvited")
+	defer span.End()
+	selectQuery := `
+		SELECT EXISTS (SELECT FROM invitation
+		WHERE tender_id = $1 AND invitee_type = $2 AND invitee_id = $3);
+	`
+	err = repo.db.QueryRowContext(
+		ctx, selectQuery, tenderId, inviteeType, inviteeId,
+	).Scan(&invited)
+	return
+}
+
+func (repo *InvitationRepo) IsUserInvited(
+	ctx context.Context, tenderId uuid.UUID, userId uuid.UUID,
+) (invited bool, err error) {
+	defer metrics.ObserveQuery("invitation", "IsUserInvited", time.Now())
+	ctx, span := tracing.Start(ctx, "InvitationRepo.IsUserInvited")
+	defer span.End()
+	selectQuery := `
+		SELECT EXISTS (SELECT FROM invitation
+		WHERE tender_id = $1 AND (
+			invitee_type = 'User' AND invitee_id = $2
+			OR invitee_type = 'Organization' AND invitee_id IN (
+				SELECT organization_id
+				FROM organization_responsible
+				WHERE user_id = $2
+			)
+		));
+	`
+	err = repo.db.QueryRowContext(ctx, selectQuery, tenderId, userId).Scan(&invited)
+	return
+}
+
+func NewRepo() (repo *InvitationRepo, err error) {
+	db, err := database.Connect()
+	if err != nil {
+		return
+	}
+	repo = &InvitationRepo{db: db}
+	return
+}
+
+func Migrate() (err error) {
+	repo, err := NewRepo()
+	if err != nil {
+		return
+	}
+
+	table, err := repo.tableExists()
+	if err != nil {
+		return
+	}
+
+	if table {
+		return
+	}
+
+	err = repo.createTable()
+	if err == nil {
+		slog.Info("Table 'invitation' is created")
+	} else {
+		slog.Info("Can not create table 'invitation'")
+	}
+	return
+}
+
+func (repo *InvitationRepo) tableExists() (table bool, err error) {
+	rows, err := repo.db.Query(
+		`SELECT EXISTS (SELECT FROM information_schema.tables 
+		WHERE table_name = 'invitation');`,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&table)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (repo *InvitationRepo) createTable() error {
+	createInviteeType := `
+		CREATE TYPE invitee_type
+		AS ENUM ('User', 'Organization');
+	`
+	createInvitationTable := `
+		CREATE TABLE invitation (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tender_id UUID NOT NULL,
+		invitee_type invitee_type NOT NULL,
+		invitee_id UUID NOT NULL,
+		invited_by UUID NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (tender_id, invitee_type, invitee_id));
+	`
+	_, err := repo.db.Exec(createInviteeType + createInvitationTable)
+	return err
+}
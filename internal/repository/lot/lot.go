@@ -0,0 +1,398 @@
+package lot
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"avi/internal/database"
+	"avi/internal/metrics"
+	"avi/internal/model"
+	tenderVersion "avi/internal/repository/tenderversion"
+	"avi/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type LotRepo struct {
+	db *sql.DB
+}
+
+const selectColumns = `
+	SELECT id, tender_id, name, description, quantity,
+	budget, status, awarded_bid_id, created_at
+	FROM lot
+`
+
+func (repo *LotRepo) CreateLot(
+	ctx context.Context,
+	tenderId uuid.UUID,
+	name string,
+	description string,
+	quantity int,
+	budget *float64,
+) (lot *model.Lot, err error) {
+	defer metrics.ObserveQuery("lot", "CreateLot", time.Now())
+	ctx, span := tracing.Start(ctx, "LotRepo.CreateLot")
+	defer span.End()
+	createQuery := `
+		INSERT INTO lot
+		(tender_id, name, description, quantity, budget)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, status, created_at;
+	`
+	lot = &model.Lot{
+		TenderId:    tenderId,
+		Name:        name,
+		Description: description,
+		Quantity:    quantity,
+		Budget:      budget,
+	}
+	err = repo.db.QueryRowContext(
+		ctx, createQuery, tenderId, name, description, quantity, budget,
+	).Scan(&lot.Id, &lot.Status, &lot.CreatedAt)
+	return
+}
+
+func (repo *LotRepo) GetLotById(ctx context.Context, id uuid.UUID) (*model.Lot, error) {
+	defer metrics.ObserveQuery("lot", "GetLotById", time.Now())
+	ctx, span := tracing.Start(ctx, "LotRepo.GetLotById")
+	defer span.End()
+	row := repo.db.QueryRowContext(ctx, selectColumns+" WHERE id = $1;", id)
+	return scanLot(row)
+}
+
+func (repo *LotRepo) GetLotsByTenderId(
+	ctx context.Context, tenderId uuid.UUID,
+) (lots []*model.Lot, err error) {
+	defer metrics.ObserveQuery("lot", "GetLotsByTenderId", time.Now())
+	ctx, span := tracing.Start(ctx, "LotRepo.GetLotsByTenderId")
+	defer span.End()
+	rows, err := repo.db.QueryContext(
+		ctx,
+		selectColumns+" WHERE tender_id = $1 ORDER BY created_at ASC;",
+		tenderId,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	lots = []*model.Lot{}
+	for rows.Next() {
+		var lot *model.Lot
+		lot, err = scanLot(rows)
+		if err != nil {
+			return
+		}
+		lots = append(lots, lot)
+	}
+	err = rows.Err()
+	return
+}
+
+func (repo *LotRepo) CancelLot(ctx context.Context, id uuid.UUID) (lot *model.Lot, err error) {
+	defer metrics.ObserveQuery("lot", "CancelLot", time.Now())
+	ctx, span := tracing.Start(ctx, "LotRepo.CancelLot")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	updateQuery := `
+		UPDATE lot
+		SET status = 'Canceled'
+		WHERE id = $1 AND status = 'Open'
+		RETURNING id, tender_id, name, description, quantity,
+		budget, status, awarded_bid_id, created_at;
+	`
+	lot, err = scanLot(tx.QueryRowContext(ctx, updateQuery, id))
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = CloseTenderIfLotsDoneTx(ctx, tx, lot.TenderId)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+func (repo *LotRepo) GetDecisions(
+	ctx context.Context, bidId uuid.UUID, lotId uuid.UUID,
+) (rejects int, approves int, err error) {
+	defer metrics.ObserveQuery("lot", "GetDecisions", time.Now())
+	ctx, span := tracing.Start(ctx, "LotRepo.GetDecisions")
+	defer span.End()
+	selectQuery := `
+		SELECT rejects, approves
+		FROM bid_lot
+		WHERE bid_id = $1 AND lot_id = $2;
+	`
+	err = repo.db.QueryRowContext(ctx, selectQuery, bidId, lotId).Scan(&rejects, &approves)
+	return
+}
+
+func (repo *LotRepo) UpdateRejects(
+	ctx context.Context, bidId uuid.UUID, lotId uuid.UUID, rejects int,
+) error {
+	defer metrics.ObserveQuery("lot", "UpdateRejects", time.Now())
+	ctx, span := tracing.Start(ctx, "LotRepo.UpdateRejects")
+	defer span.End()
+	updateQuery := `
+		UPDATE bid_lot
+		SET rejects = $1
+		WHERE bid_id = $2 AND lot_id = $3;
+	`
+	_, err := repo.db.ExecContext(ctx, updateQuery, rejects, bidId, lotId)
+	return err
+}
+
+func GetLotIdsByBidIds(
+	ctx context.Context, db *sql.DB, bidIds []uuid.UUID,
+) (lotIds map[uuid.UUID][]uuid.UUID, err error) {
+	lotIds = make(map[uuid.UUID][]uuid.UUID, len(bidIds))
+	if len(bidIds) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(bidIds))
+	for _, id := range bidIds {
+		ids = append(ids, id.String())
+	}
+
+	selectQuery := `
+		SELECT bid_lot.bid_id, bid_lot.lot_id
+		FROM bid_lot
+		INNER JOIN lot ON lot.id = bid_lot.lot_id
+		WHERE bid_lot.bid_id = ANY($1::uuid[])
+		ORDER BY lot.created_at ASC;
+	`
+	rows, err := db.QueryContext(ctx, selectQuery, pq.Array(ids))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bidId, lotId uuid.UUID
+		err = rows.Scan(&bidId, &lotId)
+		if err != nil {
+			return
+		}
+		lotIds[bidId] = append(lotIds[bidId], lotId)
+	}
+	err = rows.Err()
+	return
+}
+
+func CopyLotsTx(
+	ctx context.Context, tx *sql.Tx, fromTenderId uuid.UUID, toTenderId uuid.UUID,
+) error {
+	copyQuery := `
+		INSERT INTO lot
+		(tender_id, name, description, quantity, budget)
+		SELECT $2::uuid, name, description, quantity, budget
+		FROM lot
+		WHERE tender_id = $1 AND status <> 'Canceled'
+		ORDER BY created_at ASC;
+	`
+	_, err := tx.ExecContext(ctx, copyQuery, fromTenderId, toTenderId)
+	return err
+}
+
+func CreateBidLotsTx(ctx context.Context, tx *sql.Tx, bidId uuid.UUID, lotIds []uuid.UUID) error {
+	createQuery := `
+		INSERT INTO bid_lot
+		(bid_id, lot_id)
+		VALUES ($1, $2);
+	`
+	for _, lotId := range lotIds {
+		_, err := tx.ExecContext(ctx, createQuery, bidId, lotId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func UpdateApprovesTx(
+	ctx context.Context, tx *sql.Tx, bidId uuid.UUID, lotId uuid.UUID, approves int, award bool,
+) error {
+	updateQuery := `
+		UPDATE bid_lot
+		SET approves = $1
+		WHERE bid_id = $2 AND lot_id = $3;
+	`
+	_, err := tx.ExecContext(ctx, updateQuery, approves, bidId, lotId)
+	if err != nil || !award {
+		return err
+	}
+
+	awardQuery := `
+		UPDATE lot
+		SET status = 'Awarded', awarded_bid_id = $1
+		WHERE id = $2 AND status = 'Open';
+	`
+	res, err := tx.ExecContext(ctx, awardQuery, bidId, lotId)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func CloseTenderIfLotsDoneTx(ctx context.Context, tx *sql.Tx, tenderId uuid.UUID) error {
+	var open bool
+	err := tx.QueryRowContext(
+		ctx, `SELECT EXISTS (SELECT FROM lot 
+		WHERE tender_id = $1 AND status = 'Open');`,
+		tenderId,
+	).Scan(&open)
+	if err != nil || open {
+		return err
+	}
+
+	var status model.TenderStatus
+	err = tx.QueryRowContext(
+		ctx, `SELECT status FROM tender WHERE id = $1 FOR UPDATE;`, tenderId,
+	).Scan(&status)
+	if err != nil || status == model.TenderStatusClosed {
+		return err
+	}
+
+	_, err = tenderVersion.UpdateStatusTx(ctx, tx, tenderId, model.TenderStatusClosed)
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanLot(row scanner) (*model.Lot, error) {
+	lot := &model.Lot{}
+	var budget sql.NullFloat64
+	var awardedBidId uuid.NullUUID
+	err := row.Scan(
+		&lot.Id,
+		&lot.TenderId,
+		&lot.Name,
+		&lot.Description,
+		&lot.Quantity,
+		&budget,
+		&lot.Status,
+		&awardedBidId,
+		&lot.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if budget.Valid {
+		lot.Budget = &budget.Float64
+	}
+	if awardedBidId.Valid {
+		lot.AwardedBidId = &awardedBidId.UUID
+	}
+	return lot, nil
+}
+
+func NewRepo() (repo *LotRepo, err error) {
+	db, err := database.Connect()
+	if err != nil {
+		return
+	}
+	repo = &LotRepo{db: db}
+	return
+}
+
+func Migrate() (err error) {
+	repo, err := NewRepo()
+	if err != nil {
+		return
+	}
+
+	table, err := repo.tableExists()
+	if err != nil {
+		return
+	}
+
+	if table {
+		return
+	}
+
+	err = repo.createTable()
+	if err == nil {
+		slog.Info("Tables 'lot' and 'bid_lot' are created")
+	} else {
+		slog.Info("Can not create tables 'lot' and 'bid_lot'")
+	}
+	return
+}
+
+func (repo *LotRepo) tableExists() (table bool, err error) {
+	rows, err := repo.db.Query(
+		`SELECT EXISTS (SELECT FROM information_schema.tables 
+		WHERE table_name = 'lot');`,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&table)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (repo *LotRepo) createTable() error {
+	createLotStatus := `
+		CREATE TYPE lot_status
+		AS ENUM ('Open', 'Awarded', 'Canceled');
+	`
+	createLotTable := `
+		CREATE TABLE lot (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tender_id UUID NOT NULL,
+		name VARCHAR(100) NOT NULL,
+		description VARCHAR(500) NOT NULL,
+		quantity INT NOT NULL CHECK (quantity > 0),
+		budget NUMERIC(15, 2),
+		status lot_status DEFAULT 'Open',
+		awarded_bid_id UUID,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+	`
+	createBidLotTable := `
+		CREATE TABLE bid_lot (
+		bid_id UUID NOT NULL,
+		lot_id UUID REFERENCES lot(id),
+		approves INT DEFAULT 0,
+		rejects INT DEFAULT 0,
+		PRIMARY KEY (bid_id, lot_id));
+	`
+	createLotIndex := `
+		CREATE INDEX lot_tender_id_idx ON lot (tender_id);
+	`
+	_, err := repo.db.Exec(
+		createLotStatus +
+			createLotTable +
+			createBidLotTable +
+			createLotIndex,
+	)
+	return err
+}
@@ -0,0 +1,391 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+	bidRepo "avi/internal/repository/bid"
+)
+
+type BidRepo struct {
+	store *Store
+}
+
+func (repo *BidRepo) CreateBid(
+	ctx context.Context,
+	name string,
+	description string,
+	tenderId uuid.UUID,
+	authorType model.BidAuthorType,
+	authorId uuid.UUID,
+	creatorUserId uuid.UUID,
+	lotIds []uuid.UUID,
+	amount *float64,
+	stage model.BidStage,
+	maxBids int,
+) (bid *model.Bid, err error) {
+	err = repo.store.update("CreateBid", func(st *state) error {
+		_, ok := st.tenders[tenderId]
+		if !ok {
+			return ErrorForeignKeyViolation
+		}
+		if maxBids > 0 && countBidsByAuthor(st, tenderId, authorType, authorId, stage) >= maxBids {
+			return bidRepo.ErrorBidLimitExceeded
+		}
+		for _, row := range st.bids {
+			if row.Name == name {
+				return ErrorUniqueViolation
+			}
+		}
+
+		row := bidRow{Bid: model.Bid{
+			Id:            uuid.New(),
+			Name:          name,
+			Description:   description,
+			Status:        model.CreatedBidStatus,
+			TenderId:      tenderId,
+			AuthorType:    authorType,
+			AuthorId:      authorId,
+			Stage:         stage,
+			CreatorUserId: &creatorUserId,
+			Amount:        amount,
+			Version:       1,
+			CreatedAt:     time.Now(),
+		}}
+		st.bids[row.Id] = row
+
+		for _, lotId := range lotIds {
+			_, ok = st.lots[lotId]
+			if !ok {
+				return ErrorForeignKeyViolation
+			}
+			key := bidLotKey{bidId: row.Id, lotId: lotId}
+			_, ok = st.bidLots[key]
+			if ok {
+				return ErrorUniqueViolation
+			}
+			st.bidLots[key] = bidLotRow{}
+		}
+
+		refreshReputation(st, row.Id)
+
+		bid = &row.Bid
+		bid.LotIds = lotIds
+		return nil
+	})
+	if err != nil {
+		bid = nil
+	}
+	return
+}
+
+func (repo *BidRepo) GetBidsByUserId(
+	ctx context.Context,
+	offset int,
+	limit int,
+	userId uuid.UUID,
+) (bids []*model.Bid, err error) {
+	err = repo.store.view(func(st *state) error {
+		for _, row := range st.bids {
+			if row.AuthorType == model.UserBidAuthorType && row.AuthorId == userId ||
+				row.AuthorType == model.OrgBidAuthorType &&
+					slices.Contains(st.responsibles[row.AuthorId], userId) {
+				bids = append(bids, loadBid(st, row.Bid))
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(bids, func(a, b *model.Bid) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	bids = paginate(bids, offset, limit)
+	return
+}
+
+func (repo *BidRepo) GetBidsByTenderId(
+	ctx context.Context,
+	offset int,
+	limit int,
+	tenderId uuid.UUID,
+	stage model.BidStage,
+) (bids []*model.Bid, err error) {
+	err = repo.store.view(func(st *state) error {
+		for _, row := range st.bids {
+			if row.TenderId == tenderId && (stage == "" || row.Stage == stage) {
+				bids = append(bids, loadBid(st, row.Bid))
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(bids, func(a, b *model.Bid) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	bids = paginate(bids, offset, limit)
+	return
+}
+
+func countBidsByAuthor(
+	st *state,
+	tenderId uuid.UUID,
+	authorType model.BidAuthorType,
+	authorId uuid.UUID,
+	stage model.BidStage,
+) (count int) {
+	for _, row := range st.bids {
+		if row.TenderId == tenderId && row.AuthorType == authorType && row.AuthorId == authorId &&
+			row.Stage == stage && row.Status != model.CanceledBidStatus {
+			count++
+		}
+	}
+	return
+}
+
+func (repo *BidRepo) GetBidById(ctx context.Context, id uuid.UUID) (bid *model.Bid, err error) {
+	err = repo.store.view(func(st *state) error {
+		row, ok := st.bids[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		bid = loadBid(st, row.Bid)
+		return nil
+	})
+	return
+}
+
+func (repo *BidRepo) GetBidHistory(
+	ctx context.Context, id uuid.UUID,
+) (bids []*model.Bid, err error) {
+	err = repo.store.view(func(st *state) error {
+		for _, bid := range st.bidHistory[id] {
+			bids = append(bids, loadBid(st, bid))
+		}
+		return nil
+	})
+	return
+}
+
+func (repo *BidRepo) UpdateBidStatusById(
+	ctx context.Context, id uuid.UUID, status model.BidStatus,
+) (bid *model.Bid, err error) {
+	err = repo.store.update("UpdateBidStatusById", func(st *state) error {
+		row, err := newBidVersion(st, id)
+		if err != nil {
+			return err
+		}
+		row.Status = status
+		st.bids[id] = row
+		refreshReputation(st, id)
+
+		bid = loadBid(st, row.Bid)
+		return nil
+	})
+	if err != nil {
+		bid = nil
+	}
+	return
+}
+
+func (repo *BidRepo) EditBidById(
+	ctx context.Context, id uuid.UUID, name string, description string, amount *float64,
+) (bid *model.Bid, err error) {
+	err = repo.store.update("EditBidById", func(st *state) error {
+		for _, other := range st.bids {
+			if other.Id != id && other.Name == name {
+				return ErrorUniqueViolation
+			}
+		}
+		row, err := newBidVersion(st, id)
+		if err != nil {
+			return err
+		}
+		row.Name = name
+		row.Description = description
+		row.Amount = amount
+		st.bids[id] = row
+
+		bid = loadBid(st, row.Bid)
+		return nil
+	})
+	if err != nil {
+		bid = nil
+	}
+	return
+}
+
+func (repo *BidRepo) RollbackById(
+	ctx context.Context, id uuid.UUID, version int32,
+) (bid *model.Bid, err error) {
+	err = repo.store.update("RollbackById", func(st *state) error {
+		row, err := newBidVersion(st, id)
+		if err != nil {
+			return err
+		}
+
+		index := slices.IndexFunc(st.bidHistory[id], func(old model.Bid) bool {
+			return old.Version == version
+		})
+		if index < 0 {
+			return sql.ErrNoRows
+		}
+		old := st.bidHistory[id][index]
+		row.Name = old.Name
+		row.Description = old.Description
+		row.Status = old.Status
+		row.TenderId = old.TenderId
+		row.AuthorType = old.AuthorType
+		row.AuthorId = old.AuthorId
+		row.Amount = old.Amount
+		st.bids[id] = row
+		refreshReputation(st, id)
+
+		bid = loadBid(st, row.Bid)
+		return nil
+	})
+	if err != nil {
+		bid = nil
+	}
+	return
+}
+
+func (repo *BidRepo) GetDisicions(
+	ctx context.Context, id uuid.UUID,
+) (rejects int, approves int, err error) {
+	err = repo.store.view(func(st *state) error {
+		row, ok := st.bids[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		rejects, approves = row.rejects, row.approves
+		return nil
+	})
+	return
+}
+
+func (repo *BidRepo) UpdateApproves(
+	ctx context.Context, bidId uuid.UUID, approves int, tenderId uuid.UUID, closeTenderFlag bool,
+) error {
+	return repo.store.update("UpdateApproves", func(st *state) error {
+		row, ok := st.bids[bidId]
+		if !ok {
+			return nil
+		}
+		row.approves = approves
+		if closeTenderFlag {
+			row.won = true
+		}
+		st.bids[bidId] = row
+
+		if closeTenderFlag {
+			refreshReputation(st, bidId)
+			closeTenderIfLotsDone(st, tenderId)
+		}
+		return nil
+	})
+}
+
+func (repo *BidRepo) UpdateLotApproves(
+	ctx context.Context, bidId uuid.UUID, lotId uuid.UUID, approves int,
+	tenderId uuid.UUID, award bool,
+) error {
+	return repo.store.update("UpdateLotApproves", func(st *state) error {
+		err := updateLotApproves(st, bidId, lotId, approves, award)
+		if err != nil || !award {
+			return err
+		}
+
+		row, ok := st.bids[bidId]
+		if ok {
+			row.won = true
+			st.bids[bidId] = row
+		}
+		refreshReputation(st, bidId)
+		closeTenderIfLotsDone(st, tenderId)
+		return nil
+	})
+}
+
+func (repo *BidRepo) UpdateRejects(ctx context.Context, bidId uuid.UUID, rejects int) error {
+	return repo.store.update("UpdateRejects", func(st *state) error {
+		row, ok := st.bids[bidId]
+		if !ok {
+			return nil
+		}
+		row.rejects = rejects
+		st.bids[bidId] = row
+		return nil
+	})
+}
+
+func (repo *BidRepo) GetReputation(
+	ctx context.Context, authorType model.BidAuthorType, authorId uuid.UUID,
+) (reputation *model.Reputation, err error) {
+	err = repo.store.view(func(st *state) error {
+		found, ok := st.reputations[authorKey{authorType: authorType, authorId: authorId}]
+		if !ok {
+			found = model.Reputation{AuthorType: authorType, AuthorId: authorId}
+		}
+		reputation = &found
+		return nil
+	})
+	return
+}
+
+func newBidVersion(st *state, id uuid.UUID) (bidRow, error) {
+	row, ok := st.bids[id]
+	if !ok {
+		return row, sql.ErrNoRows
+	}
+	st.bidHistory[id] = append(st.bidHistory[id], row.Bid)
+	row.Version += 1
+	return row, nil
+}
+
+func loadBid(st *state, bid model.Bid) *model.Bid {
+	bid.LotIds = bidLotIds(st, bid.Id)
+	return &bid
+}
+
+func refreshReputation(st *state, bidId uuid.UUID) {
+	bid, ok := st.bids[bidId]
+	if !ok {
+		return
+	}
+	key := authorKey{authorType: bid.AuthorType, authorId: bid.AuthorId}
+	now := time.Now()
+	reputation := model.Reputation{
+		AuthorType: key.authorType,
+		AuthorId:   key.authorId,
+		UpdatedAt:  &now,
+	}
+
+	ratingSum := 0
+	for _, row := range st.bids {
+		if row.AuthorType != key.authorType || row.AuthorId != key.authorId {
+			continue
+		}
+		reputation.Bids += 1
+		if row.won {
+			reputation.Wins += 1
+		}
+		if row.Status == model.CanceledBidStatus {
+			reputation.Cancellations += 1
+		}
+		for _, review := range st.reviews {
+			if review.BidId == row.Id && review.deletedAt == nil && review.Rating != nil {
+				ratingSum += *review.Rating
+				reputation.Ratings += 1
+			}
+		}
+	}
+	if reputation.Ratings > 0 {
+		average := float64(ratingSum) / float64(reputation.Ratings)
+		reputation.AverageRating = &average
+	}
+	st.reputations[key] = reputation
+}
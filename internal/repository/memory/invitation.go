@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type InvitationRepo struct {
+	store *Store
+}
+
+func (repo *InvitationRepo) IsInvited(
+	ctx context.Context, tenderId uuid.UUID, inviteeType model.InviteeType, inviteeId uuid.UUID,
+) (invited bool, err error) {
+	err = repo.store.view(func(st *state) error {
+		invited = slices.ContainsFunc(st.invitations, func(invitation model.Invitation) bool {
+			return invitation.TenderId == tenderId &&
+				invitation.InviteeType == inviteeType &&
+				invitation.InviteeId == inviteeId
+		})
+		return nil
+	})
+	return
+}
+
+func (repo *InvitationRepo) IsUserInvited(
+	ctx context.Context, tenderId uuid.UUID, userId uuid.UUID,
+) (invited bool, err error) {
+	err = repo.store.view(func(st *state) error {
+		invited = isUserInvited(st, tenderId, userId)
+		return nil
+	})
+	return
+}
+
+func isUserInvited(st *state, tenderId uuid.UUID, userId uuid.UUID) bool {
+	return slices.ContainsFunc(st.invitations, func(invitation model.Invitation) bool {
+		if invitation.TenderId != tenderId {
+			return false
+		}
+		switch invitation.InviteeType {
+		case model.UserInviteeType:
+			return invitation.InviteeId == userId
+		case model.OrgInviteeType:
+			return slices.Contains(st.responsibles[invitation.InviteeId], userId)
+		}
+		return false
+	})
+}
+
+type ShortlistRepo struct {
+	store *Store
+}
+
+func (repo *ShortlistRepo) IsShortlisted(
+	ctx context.Context, tenderId uuid.UUID, authorType model.BidAuthorType, authorId uuid.UUID,
+) (shortlisted bool, err error) {
+	err = repo.store.view(func(st *state) error {
+		shortlisted = slices.ContainsFunc(st.shortlist, func(entry model.ShortlistEntry) bool {
+			return entry.TenderId == tenderId &&
+				entry.AuthorType == authorType &&
+				entry.AuthorId == authorId
+		})
+		return nil
+	})
+	return
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type LotRepo struct {
+	store *Store
+}
+
+func (repo *LotRepo) GetLotById(ctx context.Context, id uuid.UUID) (lot *model.Lot, err error) {
+	err = repo.store.view(func(st *state) error {
+		found, ok := st.lots[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		lot = &found
+		return nil
+	})
+	return
+}
+
+func (repo *LotRepo) GetLotsByTenderId(
+	ctx context.Context, tenderId uuid.UUID,
+) (lots []*model.Lot, err error) {
+	err = repo.store.view(func(st *state) error {
+		for _, lot := range sortedLots(st, tenderId) {
+			lots = append(lots, &lot)
+		}
+		return nil
+	})
+	return
+}
+
+func (repo *LotRepo) GetDecisions(
+	ctx context.Context, bidId uuid.UUID, lotId uuid.UUID,
+) (rejects int, approves int, err error) {
+	err = repo.store.view(func(st *state) error {
+		row, ok := st.bidLots[bidLotKey{bidId: bidId, lotId: lotId}]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		rejects, approves = row.rejects, row.approves
+		return nil
+	})
+	return
+}
+
+func (repo *LotRepo) UpdateRejects(
+	ctx context.Context, bidId uuid.UUID, lotId uuid.UUID, rejects int,
+) error {
+	return repo.store.update("UpdateLotRejects", func(st *state) error {
+		key := bidLotKey{bidId: bidId, lotId: lotId}
+		row, ok := st.bidLots[key]
+		if !ok {
+			return nil
+		}
+		row.rejects = rejects
+		st.bidLots[key] = row
+		return nil
+	})
+}
+
+func sortedLots(st *state, tenderId uuid.UUID) []model.Lot {
+	lots := []model.Lot{}
+	for _, lot := range st.lots {
+		if lot.TenderId == tenderId {
+			lots = append(lots, lot)
+		}
+	}
+	slices.SortFunc(lots, func(a, b model.Lot) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return lots
+}
+
+func bidLotIds(st *state, bidId uuid.UUID) []uuid.UUID {
+	lots := []model.Lot{}
+	for key := range st.bidLots {
+		if key.bidId == bidId {
+			lots = append(lots, st.lots[key.lotId])
+		}
+	}
+	if len(lots) == 0 {
+		return nil
+	}
+	slices.SortFunc(lots, func(a, b model.Lot) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	ids := make([]uuid.UUID, 0, len(lots))
+	for _, lot := range lots {
+		ids = append(ids, lot.Id)
+	}
+	return ids
+}
+
+func updateLotApproves(
+	st *state, bidId uuid.UUID, lotId uuid.UUID, approves int, award bool,
+) error {
+	key := bidLotKey{bidId: bidId, lotId: lotId}
+	row, ok := st.bidLots[key]
+	if ok {
+		row.approves = approves
+		st.bidLots[key] = row
+	}
+	if !award {
+		return nil
+	}
+
+	lot, ok := st.lots[lotId]
+	if !ok || lot.Status != model.OpenLotStatus {
+		return sql.ErrNoRows
+	}
+	lot.Status = model.AwardedLotStatus
+	lot.AwardedBidId = &bidId
+	st.lots[lotId] = lot
+	return nil
+}
+
+func closeTenderIfLotsDone(st *state, tenderId uuid.UUID) {
+	for _, lot := range st.lots {
+		if lot.TenderId == tenderId && lot.Status == model.OpenLotStatus {
+			return
+		}
+	}
+	row, ok := st.tenders[tenderId]
+	if !ok || row.Status == model.TenderStatusClosed {
+		return
+	}
+	pushTenderHistory(st, row.Tender)
+	row.Version += 1
+	st.tenders[tenderId] = row
+	closeTender(st, tenderId)
+}
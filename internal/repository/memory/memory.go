@@ -0,0 +1,297 @@
+package memory
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+var (
+	ErrorUniqueViolation     = errors.New("unique constraint violation")
+	ErrorForeignKeyViolation = errors.New("foreign key constraint violation")
+)
+
+type Store struct {
+	mu       sync.RWMutex
+	state    *state
+	failures map[string]error
+}
+
+type authorKey struct {
+	authorType model.BidAuthorType
+	authorId   uuid.UUID
+}
+
+type bidLotKey struct {
+	bidId uuid.UUID
+	lotId uuid.UUID
+}
+
+type tenderRow struct {
+	model.Tender
+	userId uuid.UUID
+}
+
+type bidRow struct {
+	model.Bid
+	rejects  int
+	approves int
+	won      bool
+}
+
+type bidLotRow struct {
+	rejects  int
+	approves int
+}
+
+type reviewRow struct {
+	model.Review
+	deletedAt *time.Time
+}
+
+type state struct {
+	users         map[uuid.UUID]model.User
+	organizations map[uuid.UUID]model.Organization
+	responsibles  map[uuid.UUID][]uuid.UUID
+	serviceTypes  map[model.TenderServiceType]model.ServiceType
+	tenders       map[uuid.UUID]tenderRow
+	tenderHistory map[uuid.UUID][]model.Tender
+	bids          map[uuid.UUID]bidRow
+	bidHistory    map[uuid.UUID][]model.Bid
+	lots          map[uuid.UUID]model.Lot
+	bidLots       map[bidLotKey]bidLotRow
+	reviews       map[uuid.UUID]reviewRow
+	reviewHistory map[uuid.UUID][]model.Review
+	replies       map[uuid.UUID][]model.ReviewReply
+	reputations   map[authorKey]model.Reputation
+	invitations   []model.Invitation
+	shortlist     []model.ShortlistEntry
+}
+
+func NewStore() *Store {
+	now := time.Now()
+	serviceTypes := map[model.TenderServiceType]model.ServiceType{}
+	for _, code := range []model.TenderServiceType{"Construction", "Delivery", "Manufacture"} {
+		serviceTypes[code] = model.ServiceType{Code: code, Name: string(code), CreatedAt: now}
+	}
+	return &Store{
+		state: &state{
+			users:         map[uuid.UUID]model.User{},
+			organizations: map[uuid.UUID]model.Organization{},
+			responsibles:  map[uuid.UUID][]uuid.UUID{},
+			serviceTypes:  serviceTypes,
+			tenders:       map[uuid.UUID]tenderRow{},
+			tenderHistory: map[uuid.UUID][]model.Tender{},
+			bids:          map[uuid.UUID]bidRow{},
+			bidHistory:    map[uuid.UUID][]model.Bid{},
+			lots:          map[uuid.UUID]model.Lot{},
+			bidLots:       map[bidLotKey]bidLotRow{},
+			reviews:       map[uuid.UUID]reviewRow{},
+			reviewHistory: map[uuid.UUID][]model.Review{},
+			replies:       map[uuid.UUID][]model.ReviewReply{},
+			reputations:   map[authorKey]model.Reputation{},
+		},
+		failures: map[string]error{},
+	}
+}
+
+func (store *Store) Fail(operation string, err error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.failures[operation] = err
+}
+
+func (store *Store) Tenders() *TenderRepo {
+	return &TenderRepo{store: store}
+}
+
+func (store *Store) Bids() *BidRepo {
+	return &BidRepo{store: store}
+}
+
+func (store *Store) Lots() *LotRepo {
+	return &LotRepo{store: store}
+}
+
+func (store *Store) Organizations() *OrganizationRepo {
+	return &OrganizationRepo{store: store}
+}
+
+func (store *Store) Users() *UserRepo {
+	return &UserRepo{store: store}
+}
+
+func (store *Store) Invitations() *InvitationRepo {
+	return &InvitationRepo{store: store}
+}
+
+func (store *Store) Shortlists() *ShortlistRepo {
+	return &ShortlistRepo{store: store}
+}
+
+func (store *Store) ServiceTypes() *ServiceTypeRepo {
+	return &ServiceTypeRepo{store: store}
+}
+
+func (store *Store) update(operation string, fn func(st *state) error) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	next := store.state.clone()
+	err := fn(next)
+	if err != nil {
+		return err
+	}
+
+	err, ok := store.failures[operation]
+	if ok {
+		delete(store.failures, operation)
+		return err
+	}
+
+	store.state = next
+	return nil
+}
+
+func (store *Store) view(fn func(st *state) error) error {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return fn(store.state)
+}
+
+func (st *state) clone() *state {
+	return &state{
+		users:         maps.Clone(st.users),
+		organizations: maps.Clone(st.organizations),
+		responsibles:  cloneSlices(st.responsibles),
+		serviceTypes:  maps.Clone(st.serviceTypes),
+		tenders:       maps.Clone(st.tenders),
+		tenderHistory: cloneSlices(st.tenderHistory),
+		bids:          maps.Clone(st.bids),
+		bidHistory:    cloneSlices(st.bidHistory),
+		lots:          maps.Clone(st.lots),
+		bidLots:       maps.Clone(st.bidLots),
+		reviews:       maps.Clone(st.reviews),
+		reviewHistory: cloneSlices(st.reviewHistory),
+		replies:       cloneSlices(st.replies),
+		reputations:   maps.Clone(st.reputations),
+		invitations:   slices.Clone(st.invitations),
+		shortlist:     slices.Clone(st.shortlist),
+	}
+}
+
+func cloneSlices[K comparable, V any](src map[K][]V) map[K][]V {
+	dst := make(map[K][]V, len(src))
+	for key, values := range src {
+		dst[key] = slices.Clone(values)
+	}
+	return dst
+}
+
+func (store *Store) AddUser(user model.User) model.User {
+	if user.Id == uuid.Nil {
+		user.Id = uuid.New()
+	}
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+		user.UpdatedAt = user.CreatedAt
+	}
+	store.update("AddUser", func(st *state) error {
+		st.users[user.Id] = user
+		return nil
+	})
+	return user
+}
+
+func (store *Store) AddOrganization(
+	org model.Organization, responsibles ...uuid.UUID,
+) model.Organization {
+	if org.Id == uuid.Nil {
+		org.Id = uuid.New()
+	}
+	if org.CreatedAt.IsZero() {
+		org.CreatedAt = time.Now()
+		org.UpdatedAt = org.CreatedAt
+	}
+	store.update("AddOrganization", func(st *state) error {
+		st.organizations[org.Id] = org
+		st.responsibles[org.Id] = append(st.responsibles[org.Id], responsibles...)
+		return nil
+	})
+	return org
+}
+
+func (store *Store) AddServiceType(serviceType model.ServiceType) model.ServiceType {
+	if serviceType.CreatedAt.IsZero() {
+		serviceType.CreatedAt = time.Now()
+	}
+	store.update("AddServiceType", func(st *state) error {
+		st.serviceTypes[serviceType.Code] = serviceType
+		return nil
+	})
+	return serviceType
+}
+
+func (store *Store) AddLot(lot model.Lot) model.Lot {
+	if lot.Id == uuid.Nil {
+		lot.Id = uuid.New()
+	}
+	if lot.Status == "" {
+		lot.Status = model.OpenLotStatus
+	}
+	if lot.CreatedAt.IsZero() {
+		lot.CreatedAt = time.Now()
+	}
+	store.update("AddLot", func(st *state) error {
+		st.lots[lot.Id] = lot
+		return nil
+	})
+	return lot
+}
+
+func (store *Store) AddInvitation(invitation model.Invitation) model.Invitation {
+	if invitation.Id == uuid.Nil {
+		invitation.Id = uuid.New()
+	}
+	if invitation.CreatedAt.IsZero() {
+		invitation.CreatedAt = time.Now()
+	}
+	store.update("AddInvitation", func(st *state) error {
+		st.invitations = append(st.invitations, invitation)
+		return nil
+	})
+	return invitation
+}
+
+func (store *Store) AddShortlistEntry(entry model.ShortlistEntry) model.ShortlistEntry {
+	if entry.Id == uuid.Nil {
+		entry.Id = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	store.update("AddShortlistEntry", func(st *state) error {
+		st.shortlist = append(st.shortlist, entry)
+		return nil
+	})
+	return entry
+}
+
+func paginate[T any](items []T, offset int, limit int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return nil
+		}
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type OrganizationRepo struct {
+	store *Store
+}
+
+func (repo *OrganizationRepo) GetOrganizationById(
+	ctx context.Context, id uuid.UUID,
+) (organization *model.Organization, err error) {
+	err = repo.store.view(func(st *state) error {
+		org, ok := st.organizations[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		organization = &org
+		return nil
+	})
+	return
+}
+
+func (repo *OrganizationRepo) GetOrganizationsByUserId(
+	ctx context.Context, userId uuid.UUID,
+) (orgs []*model.Organization, err error) {
+	err = repo.store.view(func(st *state) error {
+		for orgId, users := range st.responsibles {
+			if !slices.Contains(users, userId) {
+				continue
+			}
+			org, ok := st.organizations[orgId]
+			if ok {
+				orgs = append(orgs, &org)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (repo *OrganizationRepo) GetResponsibleUsersId(
+	ctx context.Context, orgId uuid.UUID,
+) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	err := repo.store.view(func(st *state) error {
+		ids = append(ids, st.responsibles[orgId]...)
+		return nil
+	})
+	return ids, err
+}
+
+type UserRepo struct {
+	store *Store
+}
+
+func (repo *UserRepo) GetUserByName(
+	ctx context.Context, name string,
+) (user *model.User, err error) {
+	err = repo.store.view(func(st *state) error {
+		for _, found := range st.users {
+			if found.Username == name {
+				user = &found
+				return nil
+			}
+		}
+		return sql.ErrNoRows
+	})
+	return
+}
+
+func (repo *UserRepo) GetUserById(ctx context.Context, id uuid.UUID) (user *model.User, err error) {
+	err = repo.store.view(func(st *state) error {
+		found, ok := st.users[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		user = &found
+		return nil
+	})
+	return
+}
@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+func (repo *BidRepo) CreateReviewById(
+	ctx context.Context,
+	id uuid.UUID,
+	reviewerId uuid.UUID,
+	description string,
+	rating *int,
+	tags []model.ReviewTag,
+) (bid *model.Bid, err error) {
+	err = repo.store.update("CreateReviewById", func(st *state) error {
+		row, ok := st.bids[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+
+		now := time.Now()
+		review := reviewRow{Review: model.Review{
+			Id:          uuid.New(),
+			BidId:       id,
+			ReviewerId:  &reviewerId,
+			Description: description,
+			Rating:      rating,
+			Tags:        slices.Clone(tags),
+			Version:     1,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}}
+		if review.Tags == nil {
+			review.Tags = []model.ReviewTag{}
+		}
+		st.reviews[review.Id] = review
+		refreshReputation(st, id)
+
+		bid = &row.Bid
+		return nil
+	})
+	if err != nil {
+		bid = nil
+	}
+	return
+}
+
+func (repo *BidRepo) GetReviews(
+	ctx context.Context,
+	offset int,
+	limit int,
+	authorId uuid.UUID,
+	tenderId uuid.UUID,
+) (reviews []*model.Review, err error) {
+	err = repo.store.view(func(st *state) error {
+		for _, review := range st.reviews {
+			bid := st.bids[review.BidId]
+			if review.deletedAt == nil && bid.TenderId == tenderId && bid.AuthorId == authorId {
+				reviews = append(reviews, loadReview(st, review.Review))
+			}
+		}
+		return nil
+	})
+	sortReviews(reviews)
+	reviews = paginate(reviews, offset, limit)
+	return
+}
+
+func (repo *BidRepo) GetRecentReviews(
+	ctx context.Context, authorType model.BidAuthorType, authorId uuid.UUID, limit int,
+) (reviews []*model.Review, err error) {
+	reviews = []*model.Review{}
+	err = repo.store.view(func(st *state) error {
+		for _, review := range st.reviews {
+			bid := st.bids[review.BidId]
+			if review.deletedAt == nil && bid.AuthorType == authorType && bid.AuthorId == authorId {
+				reviews = append(reviews, loadReview(st, review.Review))
+			}
+		}
+		return nil
+	})
+	sortReviews(reviews)
+	slices.Reverse(reviews)
+	reviews = paginate(reviews, 0, limit)
+	return
+}
+
+func (repo *BidRepo) GetReviewById(
+	ctx context.Context, id uuid.UUID,
+) (review *model.Review, err error) {
+	err = repo.store.view(func(st *state) error {
+		row, ok := st.reviews[id]
+		if !ok || row.deletedAt != nil {
+			return sql.ErrNoRows
+		}
+		review = loadReview(st, row.Review)
+		return nil
+	})
+	return
+}
+
+func (repo *BidRepo) EditReview(
+	ctx context.Context, reviewUpd *model.Review,
+) (review *model.Review, err error) {
+	err = repo.store.update("EditReview", func(st *state) error {
+		row, err := newReviewVersion(st, reviewUpd.Id)
+		if err != nil {
+			return err
+		}
+		row.Description = reviewUpd.Description
+		row.Rating = reviewUpd.Rating
+		row.Tags = slices.Clone(reviewUpd.Tags)
+		st.reviews[row.Id] = row
+		refreshReputation(st, row.BidId)
+
+		review = loadReview(st, row.Review)
+		return nil
+	})
+	if err != nil {
+		review = nil
+	}
+	return
+}
+
+func (repo *BidRepo) DeleteReview(ctx context.Context, id uuid.UUID) error {
+	return repo.store.update("DeleteReview", func(st *state) error {
+		row, err := newReviewVersion(st, id)
+		if err != nil {
+			return err
+		}
+		row.deletedAt = &row.UpdatedAt
+		st.reviews[id] = row
+		refreshReputation(st, row.BidId)
+		return nil
+	})
+}
+
+func (repo *BidRepo) CreateReply(
+	ctx context.Context, reviewId uuid.UUID, authorId uuid.UUID, text string,
+) (reply *model.ReviewReply, err error) {
+	err = repo.store.update("CreateReply", func(st *state) error {
+		_, ok := st.reviews[reviewId]
+		if !ok {
+			return ErrorForeignKeyViolation
+		}
+		created := model.ReviewReply{
+			Id:        uuid.New(),
+			ReviewId:  reviewId,
+			AuthorId:  authorId,
+			Text:      text,
+			CreatedAt: time.Now(),
+		}
+		st.replies[reviewId] = append(st.replies[reviewId], created)
+		reply = &created
+		return nil
+	})
+	if err != nil {
+		reply = nil
+	}
+	return
+}
+
+func newReviewVersion(st *state, id uuid.UUID) (reviewRow, error) {
+	row, ok := st.reviews[id]
+	if !ok || row.deletedAt != nil {
+		return row, sql.ErrNoRows
+	}
+	st.reviewHistory[id] = append(st.reviewHistory[id], row.Review)
+	row.Version += 1
+	row.UpdatedAt = time.Now()
+	return row, nil
+}
+
+func loadReview(st *state, review model.Review) *model.Review {
+	review.Tags = slices.Clone(review.Tags)
+	review.Replies = []*model.ReviewReply{}
+	for _, reply := range st.replies[review.Id] {
+		review.Replies = append(review.Replies, &reply)
+	}
+	return &review
+}
+
+func sortReviews(reviews []*model.Review) {
+	slices.SortFunc(reviews, func(a, b *model.Review) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+}
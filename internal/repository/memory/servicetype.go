@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	"avi/internal/model"
+)
+
+type ServiceTypeRepo struct {
+	store *Store
+}
+
+func (repo *ServiceTypeRepo) GetServiceTypeByCode(
+	ctx context.Context, code model.TenderServiceType,
+) (*model.ServiceType, error) {
+	var serviceType *model.ServiceType
+	err := repo.store.view(func(st *state) error {
+		found, ok := st.serviceTypes[code]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		serviceType = &found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return serviceType, nil
+}
+
+func (repo *ServiceTypeRepo) GetSubtree(
+	ctx context.Context, code model.TenderServiceType,
+) ([]*model.ServiceType, error) {
+	serviceTypes := []*model.ServiceType{}
+	err := repo.store.view(func(st *state) error {
+		root, ok := st.serviceTypes[code]
+		if !ok {
+			return nil
+		}
+		queue := []model.ServiceType{root}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			serviceTypes = append(serviceTypes, &current)
+			for _, child := range st.serviceTypes {
+				if child.ParentCode != nil && *child.ParentCode == current.Code {
+					queue = append(queue, child)
+				}
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(serviceTypes, func(a, b *model.ServiceType) int {
+		return strings.Compare(string(a.Code), string(b.Code))
+	})
+	return serviceTypes, err
+}
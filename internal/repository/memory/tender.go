@@ -0,0 +1,271 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type TenderRepo struct {
+	store *Store
+}
+
+func (repo *TenderRepo) CreateTender(
+	ctx context.Context, tender *model.Tender, userId uuid.UUID,
+) (*model.Tender, error) {
+	err := repo.store.update("CreateTender", func(st *state) error {
+		return createTender(st, tender, userId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+func (repo *TenderRepo) CloneTender(
+	ctx context.Context, source *model.Tender, name string, userId uuid.UUID,
+) (*model.Tender, error) {
+	tender := *source
+	tender.Name = name
+	err := repo.store.update("CloneTender", func(st *state) error {
+		err := createTender(st, &tender, userId)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i, lot := range sortedLots(st, source.Id) {
+			if lot.Status == model.CanceledLotStatus {
+				continue
+			}
+			id := uuid.New()
+			st.lots[id] = model.Lot{
+				Id:          id,
+				TenderId:    tender.Id,
+				Name:        lot.Name,
+				Description: lot.Description,
+				Quantity:    lot.Quantity,
+				Budget:      lot.Budget,
+				Status:      model.OpenLotStatus,
+				CreatedAt:   now.Add(time.Duration(i)),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tender, nil
+}
+
+func createTender(st *state, tender *model.Tender, userId uuid.UUID) error {
+	_, ok := st.serviceTypes[tender.ServiceType]
+	if !ok {
+		return ErrorForeignKeyViolation
+	}
+	for _, row := range st.tenders {
+		if row.Name == tender.Name {
+			return ErrorUniqueViolation
+		}
+	}
+
+	tender.Id = uuid.New()
+	tender.Status = model.TenderStatusCreated
+	tender.Version = 1
+	tender.CreatedAt = time.Now()
+	if tender.Criteria == nil {
+		tender.Criteria = []string{}
+	}
+	st.tenders[tender.Id] = tenderRow{Tender: copyTender(*tender), userId: userId}
+	return nil
+}
+
+func (repo *TenderRepo) GetTendersByUserId(
+	ctx context.Context,
+	offset int,
+	limit int,
+	userId uuid.UUID,
+) (tenders []*model.Tender, err error) {
+	err = repo.store.view(func(st *state) error {
+		for _, row := range st.tenders {
+			if row.userId == userId {
+				tenders = append(tenders, ptrTender(row.Tender))
+			}
+		}
+		return nil
+	})
+	sortTenders(tenders)
+	tenders = paginate(tenders, offset, limit)
+	return
+}
+
+func (repo *TenderRepo) GetTenders(
+	ctx context.Context,
+	serviceTypesFlt []string,
+	offsetFlt string,
+	limitFlt string,
+	orgsIdFlt []uuid.UUID,
+	visibleToFlt *uuid.UUID,
+) (tenders []*model.Tender, err error) {
+	var offset, limit int
+	if offsetFlt != "" {
+		offset, err = strconv.Atoi(offsetFlt)
+		if err != nil {
+			return
+		}
+	}
+	if limitFlt != "" {
+		limit, err = strconv.Atoi(limitFlt)
+		if err != nil {
+			return
+		}
+	}
+
+	err = repo.store.view(func(st *state) error {
+		for _, row := range st.tenders {
+			if len(orgsIdFlt) != 0 && !slices.Contains(orgsIdFlt, row.OrganizationId) {
+				continue
+			}
+			if len(serviceTypesFlt) != 0 &&
+				!slices.Contains(serviceTypesFlt, string(row.ServiceType)) {
+				continue
+			}
+			if visibleToFlt != nil && row.InviteOnly &&
+				!slices.Contains(st.responsibles[row.OrganizationId], *visibleToFlt) &&
+				!isUserInvited(st, row.Id, *visibleToFlt) {
+				continue
+			}
+			tenders = append(tenders, ptrTender(row.Tender))
+		}
+		return nil
+	})
+	sortTenders(tenders)
+	tenders = paginate(tenders, offset, limit)
+	return
+}
+
+func (repo *TenderRepo) GetTenderById(
+	ctx context.Context, id uuid.UUID,
+) (tender *model.Tender, err error) {
+	err = repo.store.view(func(st *state) error {
+		row, ok := st.tenders[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		tender = ptrTender(row.Tender)
+		return nil
+	})
+	return
+}
+
+func (repo *TenderRepo) GetTenderHistory(
+	ctx context.Context, id uuid.UUID,
+) (tenders []*model.Tender, err error) {
+	err = repo.store.view(func(st *state) error {
+		for _, tender := range st.tenderHistory[id] {
+			tenders = append(tenders, ptrTender(tender))
+		}
+		return nil
+	})
+	return
+}
+
+func (repo *TenderRepo) UpdateTender(
+	ctx context.Context, tenderUpd *model.Tender,
+) (*model.Tender, error) {
+	err := repo.store.update("UpdateTender", func(st *state) error {
+		row, ok := st.tenders[tenderUpd.Id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		_, ok = st.serviceTypes[tenderUpd.ServiceType]
+		if !ok {
+			return ErrorForeignKeyViolation
+		}
+		for _, other := range st.tenders {
+			if other.Id != tenderUpd.Id && other.Name == tenderUpd.Name {
+				return ErrorUniqueViolation
+			}
+		}
+		pushTenderHistory(st, row.Tender)
+
+		tenderUpd.Version = row.Version + 1
+		tenderUpd.CreatedAt = row.CreatedAt
+		row.Tender = copyTender(*tenderUpd)
+		st.tenders[row.Id] = row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tenderUpd, nil
+}
+
+func (repo *TenderRepo) RollBackTender(
+	ctx context.Context, id uuid.UUID, version int32,
+) (*model.Tender, error) {
+	var tender model.Tender
+	err := repo.store.update("RollBackTender", func(st *state) error {
+		row, ok := st.tenders[id]
+		if !ok {
+			return sql.ErrNoRows
+		}
+		pushTenderHistory(st, row.Tender)
+
+		index := slices.IndexFunc(st.tenderHistory[id], func(old model.Tender) bool {
+			return old.Version == version
+		})
+		if index < 0 {
+			return sql.ErrNoRows
+		}
+		tender = copyTender(st.tenderHistory[id][index])
+		tender.Version = row.Version + 1
+		tender.InviteOnly = row.InviteOnly
+
+		row.Tender = copyTender(tender)
+		st.tenders[id] = row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tender, nil
+}
+
+func pushTenderHistory(st *state, tender model.Tender) {
+	tender = copyTender(tender)
+	tender.InviteOnly = false
+	st.tenderHistory[tender.Id] = append(st.tenderHistory[tender.Id], tender)
+}
+
+func closeTender(st *state, tenderId uuid.UUID) {
+	row, ok := st.tenders[tenderId]
+	if !ok {
+		return
+	}
+	row.Status = model.TenderStatusClosed
+	st.tenders[tenderId] = row
+}
+
+func copyTender(tender model.Tender) model.Tender {
+	tender.Criteria = slices.Clone(tender.Criteria)
+	return tender
+}
+
+func ptrTender(tender model.Tender) *model.Tender {
+	tender = copyTender(tender)
+	return &tender
+}
+
+func sortTenders(tenders []*model.Tender) {
+	slices.SortFunc(tenders, func(a, b *model.Tender) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
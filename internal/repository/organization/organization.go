@@ -1,10 +1,14 @@
 package organization
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"avi/internal/database"
+	"avi/internal/metrics"
 	"avi/internal/model"
+	"avi/internal/tracing"
 
 	"github.com/google/uuid"
 )
@@ -14,15 +18,18 @@ type OrganizationRepo struct {
 }
 
 func (repo *OrganizationRepo) GetOrganizationById(
-	id uuid.UUID,
+	ctx context.Context, id uuid.UUID,
 ) (organization *model.Organization, err error) {
+	defer metrics.ObserveQuery("organization", "GetOrganizationById", time.Now())
+	ctx, span := tracing.Start(ctx, "OrganizationRepo.GetOrganizationById")
+	defer span.End()
 	selectQuery := `
 		SELECT id, name, description, type, 
 		created_at, updated_at
 		FROM organization WHERE id = $1;
 	`
 	organization = &model.Organization{}
-	row := repo.db.QueryRow(selectQuery, id)
+	row := repo.db.QueryRowContext(ctx, selectQuery, id)
 	err = row.Scan(
 		&organization.Id,
 		&organization.Name,
@@ -35,8 +42,11 @@ func (repo *OrganizationRepo) GetOrganizationById(
 }
 
 func (repo *OrganizationRepo) GetOrganizationsByUserId(
-	user_id uuid.UUID,
+	ctx context.Context, user_id uuid.UUID,
 ) (orgs []*model.Organization, err error) {
+	defer metrics.ObserveQuery("organization", "GetOrganizationsByUserId", time.Now())
+	ctx, span := tracing.Start(ctx, "OrganizationRepo.GetOrganizationsByUserId")
+	defer span.End()
 	selectQuery := `
 		SELECT organization.id, organization.name, 
 		organization.description, organization.type, 
@@ -46,7 +56,7 @@ func (repo *OrganizationRepo) GetOrganizationsByUserId(
 		ON organization.id = organization_responsible.organization_id
 		WHERE organization_responsible.user_id = $1;
 	`
-	rows, err := repo.db.Query(selectQuery, user_id)
+	rows, err := repo.db.QueryContext(ctx, selectQuery, user_id)
 	if err != nil {
 		return
 	}
@@ -70,13 +80,18 @@ func (repo *OrganizationRepo) GetOrganizationsByUserId(
 	return
 }
 
-func (repo *OrganizationRepo) GetResponsibleUsersId(orgId uuid.UUID) ([]uuid.UUID, error) {
+func (repo *OrganizationRepo) GetResponsibleUsersId(
+	ctx context.Context, orgId uuid.UUID,
+) ([]uuid.UUID, error) {
+	defer metrics.ObserveQuery("organization", "GetResponsibleUsersId", time.Now())
+	ctx, span := tracing.Start(ctx, "OrganizationRepo.GetResponsibleUsersId")
+	defer span.End()
 	selectQuery := `
 		SELECT user_id 
 		FROM organization_responsible
 		WHERE organization_id = $1;
 	`
-	rows, err := repo.db.Query(selectQuery, orgId)
+	rows, err := repo.db.QueryContext(ctx, selectQuery, orgId)
 	ids := []uuid.UUID{}
 	if err != nil {
 		return ids, err
@@ -94,6 +109,39 @@ func (repo *OrganizationRepo) GetResponsibleUsersId(orgId uuid.UUID) ([]uuid.UUI
 	return ids, nil
 }
 
+func (repo *OrganizationRepo) AddResponsible(
+	ctx context.Context, orgId uuid.UUID, userId uuid.UUID,
+) error {
+	defer metrics.ObserveQuery("organization", "AddResponsible", time.Now())
+	ctx, span := tracing.Start(ctx, "OrganizationRepo.AddResponsible")
+	defer span.End()
+	insertQuery := `
+		INSERT INTO organization_responsible
+		(organization_id, user_id)
+		SELECT $1, $2
+		WHERE NOT EXISTS (
+			SELECT 1 FROM organization_responsible
+			WHERE organization_id = $1 AND user_id = $2
+		);
+	`
+	_, err := repo.db.ExecContext(ctx, insertQuery, orgId, userId)
+	return err
+}
+
+func (repo *OrganizationRepo) RemoveResponsible(
+	ctx context.Context, orgId uuid.UUID, userId uuid.UUID,
+) error {
+	defer metrics.ObserveQuery("organization", "RemoveResponsible", time.Now())
+	ctx, span := tracing.Start(ctx, "OrganizationRepo.RemoveResponsible")
+	defer span.End()
+	deleteQuery := `
+		DELETE FROM organization_responsible
+		WHERE organization_id = $1 AND user_id = $2;
+	`
+	_, err := repo.db.ExecContext(ctx, deleteQuery, orgId, userId)
+	return err
+}
+
 func NewRepo() (repo *OrganizationRepo, err error) {
 	db, err := database.Connect()
 	if err != nil {
@@ -0,0 +1,269 @@
+package question
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"avi/internal/database"
+	"avi/internal/metrics"
+	"avi/internal/model"
+	"avi/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+type QuestionRepo struct {
+	db *sql.DB
+}
+
+const selectColumns = `
+	SELECT id, tender_id, author_id, text, status,
+	COALESCE(answer, ''), COALESCE(visibility::text, ''),
+	answered_by, answered_at, created_at
+	FROM question
+`
+
+func (repo *QuestionRepo) CreateQuestion(
+	ctx context.Context, tenderId uuid.UUID, authorId uuid.UUID, text string,
+) (question *model.Question, err error) {
+	defer metrics.ObserveQuery("question", "CreateQuestion", time.Now())
+	ctx, span := tracing.Start(ctx, "QuestionRepo.CreateQuestion")
+	defer span.End()
+	createQuery := `
+		INSERT INTO question
+		(tender_id, author_id, text)
+		VALUES ($1, $2, $3)
+		RETURNING id, status, created_at;
+	`
+	question = &model.Question{
+		TenderId: tenderId,
+		AuthorId: authorId,
+		Text:     text,
+	}
+	err = repo.db.QueryRowContext(
+		ctx, createQuery, tenderId, authorId, text,
+	).Scan(&question.Id, &question.Status, &question.CreatedAt)
+	return
+}
+
+func (repo *QuestionRepo) GetQuestionById(
+	ctx context.Context, id uuid.UUID,
+) (question *model.Question, err error) {
+	defer metrics.ObserveQuery("question", "GetQuestionById", time.Now())
+	ctx, span := tracing.Start(ctx, "QuestionRepo.GetQuestionById")
+	defer span.End()
+	row := repo.db.QueryRowContext(ctx, selectColumns+" WHERE id = $1;", id)
+	return scanQuestion(row)
+}
+
+func (repo *QuestionRepo) GetQuestionsByTenderId(
+	ctx context.Context, offset int, limit int, tenderId uuid.UUID,
+) (questions []*model.Question, err error) {
+	defer metrics.ObserveQuery("question", "GetQuestionsByTenderId", time.Now())
+	ctx, span := tracing.Start(ctx, "QuestionRepo.GetQuestionsByTenderId")
+	defer span.End()
+	selectQuery := selectColumns + `
+		WHERE tender_id = $1
+		ORDER BY created_at ASC
+	`
+	return repo.query(ctx, selectQuery, offset, limit, tenderId)
+}
+
+func (repo *QuestionRepo) GetVisibleQuestions(
+	ctx context.Context, offset int, limit int, tenderId uuid.UUID, userId uuid.UUID,
+) (questions []*model.Question, err error) {
+	defer metrics.ObserveQuery("question", "GetVisibleQuestions", time.Now())
+	ctx, span := tracing.Start(ctx, "QuestionRepo.GetVisibleQuestions")
+	defer span.End()
+	selectQuery := selectColumns + `
+		WHERE tender_id = $1
+		AND (author_id = $2 OR (answer IS NOT NULL AND visibility = 'Public'))
+		ORDER BY created_at ASC
+	`
+	return repo.query(ctx, selectQuery, offset, limit, tenderId, userId)
+}
+
+func (repo *QuestionRepo) AnswerQuestion(
+	ctx context.Context,
+	id uuid.UUID,
+	answeredBy uuid.UUID,
+	answer string,
+	visibility model.AnswerVisibility,
+) (question *model.Question, err error) {
+	defer metrics.ObserveQuery("question", "AnswerQuestion", time.Now())
+	ctx, span := tracing.Start(ctx, "QuestionRepo.AnswerQuestion")
+	defer span.End()
+	updateQuery := `
+		UPDATE question
+		SET answer = $1, visibility = $2, answered_by = $3,
+		answered_at = CURRENT_TIMESTAMP, status = 'Answered'
+		WHERE id = $4 AND status = 'Open'
+		RETURNING id, tender_id, author_id, text, status,
+		answer, visibility::text, answered_by, answered_at, created_at;
+	`
+	row := repo.db.QueryRowContext(ctx, updateQuery, answer, visibility, answeredBy, id)
+	return scanQuestion(row)
+}
+
+func CloseTenderQuestionsTx(ctx context.Context, tx *sql.Tx, tenderId uuid.UUID) error {
+	updateQuery := `
+		UPDATE question
+		SET status = 'Closed'
+		WHERE tender_id = $1 AND status <> 'Closed';
+	`
+	_, err := tx.ExecContext(ctx, updateQuery, tenderId)
+	return err
+}
+
+func (repo *QuestionRepo) query(
+	ctx context.Context, selectQuery string, offset int, limit int, args ...any,
+) (questions []*model.Question, err error) {
+	if limit > 0 {
+		selectQuery += " LIMIT " + strconv.Itoa(limit)
+	}
+
+	if offset > 0 {
+		selectQuery += " OFFSET " + strconv.Itoa(offset)
+	}
+
+	selectQuery += ";"
+
+	rows, err := repo.db.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var question *model.Question
+		question, err = scanQuestion(rows)
+		if err != nil {
+			return
+		}
+		questions = append(questions, question)
+	}
+	return
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanQuestion(row scanner) (*model.Question, error) {
+	question := &model.Question{}
+	var visibility string
+	var answeredBy uuid.NullUUID
+	var answeredAt sql.NullTime
+	err := row.Scan(
+		&question.Id,
+		&question.TenderId,
+		&question.AuthorId,
+		&question.Text,
+		&question.Status,
+		&question.Answer,
+		&visibility,
+		&answeredBy,
+		&answeredAt,
+		&question.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	question.Visibility = model.AnswerVisibility(visibility)
+	if answeredBy.Valid {
+		question.AnsweredBy = &answeredBy.UUID
+	}
+	if answeredAt.Valid {
+		question.AnsweredAt = &answeredAt.Time
+	}
+	return question, nil
+}
+
+func NewRepo() (repo *QuestionRepo, err error) {
+	db, err := database.Connect()
+	if err != nil {
+		return
+	}
+	repo = &QuestionRepo{db: db}
+	return
+}
+
+func Migrate() (err error) {
+	repo, err := NewRepo()
+	if err != nil {
+		return
+	}
+
+	table, err := repo.tableExists()
+	if err != nil {
+		return
+	}
+
+	if table {
+		return
+	}
+
+	err = repo.createTable()
+	if err == nil {
+		slog.Info("Table 'question' is created")
+	} else {
+		slog.Info("Can not create table 'question'")
+	}
+	return
+}
+
+func (repo *QuestionRepo) tableExists() (table bool, err error) {
+	rows, err := repo.db.Query(
+		`SELECT EXISTS (SELECT FROM information_schema.tables 
+		WHERE table_name = 'question');`,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&table)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (repo *QuestionRepo) createTable() error {
+	createQuestionStatus := `
+		CREATE TYPE question_status
+		AS ENUM ('Open', 'Answered', 'Closed');
+	`
+	createAnswerVisibility := `
+		CREATE TYPE answer_visibility
+		AS ENUM ('Public', 'Private');
+	`
+	createQuestionTable := `
+		CREATE TABLE question (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tender_id UUID NOT NULL,
+		author_id UUID NOT NULL,
+		text VARCHAR(1000) NOT NULL,
+		status question_status DEFAULT 'Open',
+		answer VARCHAR(2000),
+		visibility answer_visibility,
+		answered_by UUID,
+		answered_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+	`
+	createQuestionIndex := `
+		CREATE INDEX question_tender_id_idx ON question (tender_id);
+	`
+	_, err := repo.db.Exec(
+		createQuestionStatus +
+			createAnswerVisibility +
+			createQuestionTable +
+			createQuestionIndex,
+	)
+	return err
+}
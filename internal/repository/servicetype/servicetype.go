@@ -0,0 +1,265 @@
+package servicetype
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"avi/internal/database"
+	"avi/internal/metrics"
+	"avi/internal/model"
+	"avi/internal/tracing"
+)
+
+type ServiceTypeRepo struct {
+	db *sql.DB
+}
+
+func (repo *ServiceTypeRepo) CreateServiceType(
+	ctx context.Context, serviceType *model.ServiceType,
+) (*model.ServiceType, error) {
+	defer metrics.ObserveQuery("servicetype", "CreateServiceType", time.Now())
+	ctx, span := tracing.Start(ctx, "ServiceTypeRepo.CreateServiceType")
+	defer span.End()
+	createQuery := `
+		INSERT INTO service_type
+		(code, name, parent_code)
+		VALUES ($1, $2, $3)
+		RETURNING created_at;
+	`
+	err := repo.db.QueryRowContext(
+		ctx,
+		createQuery,
+		serviceType.Code,
+		serviceType.Name,
+		serviceType.ParentCode,
+	).Scan(&serviceType.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return serviceType, nil
+}
+
+func (repo *ServiceTypeRepo) GetServiceTypes(ctx context.Context) ([]*model.ServiceType, error) {
+	defer metrics.ObserveQuery("servicetype", "GetServiceTypes", time.Now())
+	ctx, span := tracing.Start(ctx, "ServiceTypeRepo.GetServiceTypes")
+	defer span.End()
+	selectQuery := `
+		SELECT code, name, parent_code, created_at
+		FROM service_type
+		ORDER BY code ASC;
+	`
+	rows, err := repo.db.QueryContext(ctx, selectQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanServiceTypes(rows)
+}
+
+func (repo *ServiceTypeRepo) GetSubtree(
+	ctx context.Context, code model.TenderServiceType,
+) ([]*model.ServiceType, error) {
+	defer metrics.ObserveQuery("servicetype", "GetSubtree", time.Now())
+	ctx, span := tracing.Start(ctx, "ServiceTypeRepo.GetSubtree")
+	defer span.End()
+	selectQuery := `
+		WITH RECURSIVE subtree AS (
+			SELECT code, name, parent_code, created_at
+			FROM service_type
+			WHERE code = $1
+			UNION ALL
+			SELECT service_type.code, service_type.name,
+			service_type.parent_code, service_type.created_at
+			FROM service_type
+			INNER JOIN subtree
+			ON service_type.parent_code = subtree.code
+		)
+		SELECT code, name, parent_code, created_at
+		FROM subtree
+		ORDER BY code ASC;
+	`
+	rows, err := repo.db.QueryContext(ctx, selectQuery, code)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanServiceTypes(rows)
+}
+
+func (repo *ServiceTypeRepo) GetServiceTypeByCode(
+	ctx context.Context, code model.TenderServiceType,
+) (*model.ServiceType, error) {
+	defer metrics.ObserveQuery("servicetype", "GetServiceTypeByCode", time.Now())
+	ctx, span := tracing.Start(ctx, "ServiceTypeRepo.GetServiceTypeByCode")
+	defer span.End()
+	selectQuery := `
+		SELECT code, name, parent_code, created_at
+		FROM service_type
+		WHERE code = $1;
+	`
+	serviceType := &model.ServiceType{}
+	err := repo.db.QueryRowContext(ctx, selectQuery, code).Scan(
+		&serviceType.Code,
+		&serviceType.Name,
+		&serviceType.ParentCode,
+		&serviceType.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return serviceType, nil
+}
+
+func (repo *ServiceTypeRepo) UpdateServiceType(
+	ctx context.Context, serviceType *model.ServiceType,
+) (*model.ServiceType, error) {
+	defer metrics.ObserveQuery("servicetype", "UpdateServiceType", time.Now())
+	ctx, span := tracing.Start(ctx, "ServiceTypeRepo.UpdateServiceType")
+	defer span.End()
+	updateQuery := `
+		UPDATE service_type
+		SET name = $1, parent_code = $2
+		WHERE code = $3;
+	`
+	_, err := repo.db.ExecContext(
+		ctx,
+		updateQuery,
+		serviceType.Name,
+		serviceType.ParentCode,
+		serviceType.Code,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return serviceType, nil
+}
+
+func (repo *ServiceTypeRepo) IsUsed(
+	ctx context.Context, code model.TenderServiceType,
+) (used bool, err error) {
+	defer metrics.ObserveQuery("servicetype", "IsUsed", time.Now())
+	ctx, span := tracing.Start(ctx, "ServiceTypeRepo.IsUsed")
+	defer span.End()
+	err = repo.db.QueryRowContext(
+		ctx, `SELECT EXISTS (SELECT FROM service_type WHERE parent_code = $1)
+		OR EXISTS (SELECT FROM tender WHERE service_type = $1)
+		OR EXISTS (SELECT FROM tender_history WHERE service_type = $1)
+		OR EXISTS (SELECT FROM tender_template WHERE service_type = $1);`,
+		code,
+	).Scan(&used)
+	return
+}
+
+func (repo *ServiceTypeRepo) DeleteServiceType(
+	ctx context.Context, code model.TenderServiceType,
+) error {
+	defer metrics.ObserveQuery("servicetype", "DeleteServiceType", time.Now())
+	ctx, span := tracing.Start(ctx, "ServiceTypeRepo.DeleteServiceType")
+	defer span.End()
+	result, err := repo.db.ExecContext(
+		ctx, `DELETE FROM service_type WHERE code = $1;`, code,
+	)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanServiceTypes(rows *sql.Rows) (serviceTypes []*model.ServiceType, err error) {
+	serviceTypes = []*model.ServiceType{}
+	for rows.Next() {
+		var serviceType model.ServiceType
+		err = rows.Scan(
+			&serviceType.Code,
+			&serviceType.Name,
+			&serviceType.ParentCode,
+			&serviceType.CreatedAt,
+		)
+		if err != nil {
+			return
+		}
+		serviceTypes = append(serviceTypes, &serviceType)
+	}
+	err = rows.Err()
+	return
+}
+
+func NewRepo() (repo *ServiceTypeRepo, err error) {
+	db, err := database.Connect()
+	if err != nil {
+		return
+	}
+	repo = &ServiceTypeRepo{db: db}
+	return
+}
+
+func Migrate() (err error) {
+	repo, err := NewRepo()
+	if err != nil {
+		return
+	}
+
+	table, err := repo.tableExists()
+	if err != nil {
+		return
+	}
+
+	if table {
+		return
+	}
+
+	err = repo.createTable()
+	if err == nil {
+		slog.Info("Table 'service_type' is created")
+	} else {
+		slog.Info("Can not create table 'service_type'")
+	}
+	return
+}
+
+func (repo *ServiceTypeRepo) tableExists() (table bool, err error) {
+	rows, err := repo.db.Query(
+		`SELECT EXISTS (SELECT FROM information_schema.tables 
+		WHERE table_name = 'service_type');`,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&table)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (repo *ServiceTypeRepo) createTable() error {
+	createServiceTypeTable := `
+		CREATE TABLE service_type (
+		code VARCHAR(50) PRIMARY KEY,
+		name VARCHAR(200) NOT NULL,
+		parent_code VARCHAR(50) REFERENCES service_type(code),
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+	`
+	insertDefaultServiceTypes := `
+		INSERT INTO service_type (code, name)
+		VALUES ('Construction', 'Construction'),
+		('Delivery', 'Delivery'),
+		('Manufacture', 'Manufacture')
+		ON CONFLICT DO NOTHING;
+	`
+	_, err := repo.db.Exec(createServiceTypeTable + insertDefaultServiceTypes)
+	return err
+}
@@ -0,0 +1,198 @@
+package shortlist
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"avi/internal/database"
+	"avi/internal/metrics"
+	"avi/internal/model"
+	"avi/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+type ShortlistRepo struct {
+	db *sql.DB
+}
+
+func (repo *ShortlistRepo) CreateEntry(
+	ctx context.Context,
+	tenderId uuid.UUID,
+	bidId uuid.UUID,
+	authorType model.BidAuthorType,
+	authorId uuid.UUID,
+	addedBy uuid.UUID,
+) (entry *model.ShortlistEntry, err error) {
+	defer metrics.ObserveQuery("shortlist", "CreateEntry", time.Now())
+	ctx, span := tracing.Start(ctx, "ShortlistRepo.CreateEntry")
+	defer span.End()
+	createQuery := `
+		INSERT INTO shortlist
+		(tender_id, bid_id, author_type, author_id, added_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tender_id, author_type, author_id)
+		DO UPDATE SET bid_id = EXCLUDED.bid_id
+		RETURNING id, added_by, created_at;
+	`
+	entry = &model.ShortlistEntry{
+		TenderId:   tenderId,
+		BidId:      bidId,
+		AuthorType: authorType,
+		AuthorId:   authorId,
+	}
+	err = repo.db.QueryRowContext(
+		ctx, createQuery, tenderId, bidId, authorType, authorId, addedBy,
+	).Scan(&entry.Id, &entry.AddedBy, &entry.CreatedAt)
+	return
+}
+
+func (repo *ShortlistRepo) GetEntries(
+	ctx context.Context, tenderId uuid.UUID,
+) (entries []*model.ShortlistEntry, err error) {
+	defer metrics.ObserveQuery("shortlist", "GetEntries", time.Now())
+	ctx, span := tracing.Start(ctx, "ShortlistRepo.GetEntries")
+	defer span.End()
+	selectQuery := `
+		SELECT id, tender_id, bid_id, author_type,
+		author_id, added_by, created_at
+		FROM shortlist
+		WHERE tender_id = $1
+		ORDER BY created_at ASC;
+	`
+	rows, err := repo.db.QueryContext(ctx, selectQuery, tenderId)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	entries = []*model.ShortlistEntry{}
+	for rows.Next() {
+		var entry model.ShortlistEntry
+		err = rows.Scan(
+			&entry.Id,
+			&entry.TenderId,
+			&entry.BidId,
+			&entry.AuthorType,
+			&entry.AuthorId,
+			&entry.AddedBy,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return
+		}
+		entries = append(entries, &entry)
+	}
+	err = rows.Err()
+	return
+}
+
+func (repo *ShortlistRepo) DeleteEntry(
+	ctx context.Context, tenderId uuid.UUID, id uuid.UUID,
+) error {
+	defer metrics.ObserveQuery("shortlist", "DeleteEntry", time.Now())
+	ctx, span := tracing.Start(ctx, "ShortlistRepo.DeleteEntry")
+	defer span.End()
+	deleteQuery := `
+		DELETE FROM shortlist
+		WHERE tender_id = $1 AND id = $2;
+	`
+	res, err := repo.db.ExecContext(ctx, deleteQuery, tenderId, id)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (repo *ShortlistRepo) IsShortlisted(
+	ctx context.Context, tenderId uuid.UUID, authorType model.BidAuthorType, authorId uuid.UUID,
+) (shortlisted bool, err error) {
+	defer metrics.ObserveQuery("shortlist", "IsShortlisted", time.Now())
+	ctx, span := tracing.Start(ctx, "ShortlistRepo.IsShortlisted")
+	defer span.End()
+	selectQuery := `
+		SELECT EXISTS (SELECT FROM shortlist
+		WHERE tender_id = $1 AND author_type = $2 AND author_id = $3);
+	`
+	err = repo.db.QueryRowContext(
+		ctx, selectQuery, tenderId, authorType, authorId,
+	).Scan(&shortlisted)
+	return
+}
+
+func NewRepo() (repo *ShortlistRepo, err error) {
+	db, err := database.Connect()
+	if err != nil {
+		return
+	}
+	repo = &ShortlistRepo{db: db}
+	return
+}
+
+func Migrate() (err error) {
+	repo, err := NewRepo()
+	if err != nil {
+		return
+	}
+
+	table, err := repo.tableExists()
+	if err != nil {
+		return
+	}
+
+	if table {
+		return
+	}
+
+	err = repo.createTable()
+	if err == nil {
+		slog.Info("Table 'shortlist' is created")
+	} else {
+		slog.Info("Can not create table 'shortlist'")
+	}
+	return
+}
+
+func (repo *ShortlistRepo) tableExists() (table bool, err error) {
+	rows, err := repo.db.Query(
+		`SELECT EXISTS (SELECT FROM information_schema.tables 
+		WHERE table_name = 'shortlist');`,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&table)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (repo *ShortlistRepo) createTable() error {
+	createShortlistTable := `
+		CREATE TABLE shortlist (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		tender_id UUID NOT NULL,
+		bid_id UUID NOT NULL,
+		author_type bid_author_type NOT NULL,
+		author_id UUID NOT NULL,
+		added_by UUID NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (tender_id, author_type, author_id));
+	`
+	_, err := repo.db.Exec(createShortlistTable)
+	return err
+}
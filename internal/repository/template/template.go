@@ -0,0 +1,356 @@
+package template
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"avi/internal/database"
+	"avi/internal/metrics"
+	"avi/internal/model"
+	attachmentRepo "avi/internal/repository/attachment"
+	"avi/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type TemplateRepo struct {
+	db *sql.DB
+}
+
+func (repo *TemplateRepo) CreateTemplate(
+	ctx context.Context, template *model.TenderTemplate, userId uuid.UUID,
+) (*model.TenderTemplate, error) {
+	defer metrics.ObserveQuery("template", "CreateTemplate", time.Now())
+	ctx, span := tracing.Start(ctx, "TemplateRepo.CreateTemplate")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = createTemplateTx(ctx, tx, template, userId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return template, err
+}
+
+func createTemplateTx(
+	ctx context.Context, tx *sql.Tx, template *model.TenderTemplate, userId uuid.UUID,
+) error {
+	createQuery := `
+		INSERT INTO tender_template
+		(organization_id, name, description,
+		service_type, criteria, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, version, created_at;
+	`
+	return tx.QueryRowContext(
+		ctx,
+		createQuery,
+		template.OrganizationId,
+		template.Name,
+		template.Description,
+		template.ServiceType,
+		pq.Array(template.Criteria),
+		userId,
+	).Scan(&template.Id, &template.Version, &template.CreatedAt)
+}
+
+func (repo *TemplateRepo) GetTemplates(
+	ctx context.Context, orgsId []uuid.UUID,
+) (templates []*model.TenderTemplate, err error) {
+	defer metrics.ObserveQuery("template", "GetTemplates", time.Now())
+	ctx, span := tracing.Start(ctx, "TemplateRepo.GetTemplates")
+	defer span.End()
+	selectQuery := `
+		SELECT id, organization_id, name, description,
+		service_type, criteria, version, created_at
+		FROM tender_template
+		WHERE organization_id = ANY($1)
+		ORDER BY name ASC;
+	`
+	rows, err := repo.db.QueryContext(ctx, selectQuery, pq.Array(orgsId))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	templates = []*model.TenderTemplate{}
+	for rows.Next() {
+		var template *model.TenderTemplate
+		template, err = scanTemplate(rows)
+		if err != nil {
+			return
+		}
+		templates = append(templates, template)
+	}
+	err = rows.Err()
+	return
+}
+
+func (repo *TemplateRepo) GetTemplateById(
+	ctx context.Context, id uuid.UUID,
+) (*model.TenderTemplate, error) {
+	defer metrics.ObserveQuery("template", "GetTemplateById", time.Now())
+	ctx, span := tracing.Start(ctx, "TemplateRepo.GetTemplateById")
+	defer span.End()
+	selectQuery := `
+		SELECT id, organization_id, name, description,
+		service_type, criteria, version, created_at
+		FROM tender_template
+		WHERE id = $1;
+	`
+	return scanTemplate(repo.db.QueryRowContext(ctx, selectQuery, id))
+}
+
+func (repo *TemplateRepo) UpdateTemplate(
+	ctx context.Context, template *model.TenderTemplate,
+) (*model.TenderTemplate, error) {
+	defer metrics.ObserveQuery("template", "UpdateTemplate", time.Now())
+	ctx, span := tracing.Start(ctx, "TemplateRepo.UpdateTemplate")
+	defer span.End()
+	updateQuery := `
+		UPDATE tender_template
+		SET name = $1, description = $2,
+		service_type = $3, criteria = $4
+		WHERE id = $5;
+	`
+	_, err := repo.db.ExecContext(
+		ctx,
+		updateQuery,
+		template.Name,
+		template.Description,
+		template.ServiceType,
+		pq.Array(template.Criteria),
+		template.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (repo *TemplateRepo) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	defer metrics.ObserveQuery("template", "DeleteTemplate", time.Now())
+	ctx, span := tracing.Start(ctx, "TemplateRepo.DeleteTemplate")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM attachment_version WHERE object_id = $1;`, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM tender_template WHERE id = $1;`, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (repo *TemplateRepo) AddAttachment(
+	ctx context.Context, templateId uuid.UUID, attachment *model.Attachment,
+) (*model.TenderTemplate, error) {
+	defer metrics.ObserveQuery("template", "AddAttachment", time.Now())
+	ctx, span := tracing.Start(ctx, "TemplateRepo.AddAttachment")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := newVersionTx(ctx, tx, templateId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, templateId, template.Version-1, template.Version, uuid.Nil,
+	)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = attachmentRepo.CreateAttachmentTx(ctx, tx, attachment, template.Version)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return template, err
+}
+
+func (repo *TemplateRepo) RemoveAttachment(
+	ctx context.Context, templateId uuid.UUID, attachmentId uuid.UUID,
+) (*model.TenderTemplate, error) {
+	defer metrics.ObserveQuery("template", "RemoveAttachment", time.Now())
+	ctx, span := tracing.Start(ctx, "TemplateRepo.RemoveAttachment")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := newVersionTx(ctx, tx, templateId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, templateId, template.Version-1, template.Version, attachmentId,
+	)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return template, err
+}
+
+func newVersionTx(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*model.TenderTemplate, error) {
+	updateQuery := `
+		UPDATE tender_template
+		SET version = version + 1
+		WHERE id = $1
+		RETURNING id, organization_id, name, description,
+		service_type, criteria, version, created_at;
+	`
+	return scanTemplate(tx.QueryRowContext(ctx, updateQuery, id))
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTemplate(row scanner) (*model.TenderTemplate, error) {
+	template := &model.TenderTemplate{}
+	err := row.Scan(
+		&template.Id,
+		&template.OrganizationId,
+		&template.Name,
+		&template.Description,
+		&template.ServiceType,
+		pq.Array(&template.Criteria),
+		&template.Version,
+		&template.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func NewRepo() (repo *TemplateRepo, err error) {
+	db, err := database.Connect()
+	if err != nil {
+		return
+	}
+	repo = &TemplateRepo{db: db}
+	return
+}
+
+func Migrate() (err error) {
+	repo, err := NewRepo()
+	if err != nil {
+		return
+	}
+
+	table, err := repo.tableExists()
+	if err != nil {
+		return
+	}
+
+	if !table {
+		err = repo.createTable()
+		if err == nil {
+			slog.Info("Table 'tender_template' is created")
+		} else {
+			slog.Info("Can not create table 'tender_template'")
+		}
+		return
+	}
+
+	enum, err := repo.serviceTypeIsEnum()
+	if err != nil {
+		return
+	}
+
+	if !enum {
+		return
+	}
+
+	_, err = repo.db.Exec(`
+		ALTER TABLE tender_template
+		ALTER COLUMN service_type TYPE VARCHAR(50) USING service_type::text,
+		ADD FOREIGN KEY (service_type) REFERENCES service_type(code);
+	`)
+	if err == nil {
+		slog.Info("Column 'service_type' of table 'tender_template' references table 'service_type'")
+	} else {
+		slog.Info("Can not convert column 'service_type' of table 'tender_template'")
+	}
+	return
+}
+
+func (repo *TemplateRepo) serviceTypeIsEnum() (enum bool, err error) {
+	err = repo.db.QueryRow(
+		`SELECT EXISTS (SELECT FROM information_schema.columns 
+		WHERE table_name = 'tender_template' AND column_name = 'service_type'
+		AND data_type = 'USER-DEFINED');`,
+	).Scan(&enum)
+	return
+}
+
+func (repo *TemplateRepo) tableExists() (table bool, err error) {
+	rows, err := repo.db.Query(
+		`SELECT EXISTS (SELECT FROM information_schema.tables 
+		WHERE table_name = 'tender_template');`,
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&table)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (repo *TemplateRepo) createTable() error {
+	createTemplateTable := `
+		CREATE TABLE tender_template (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		organization_id UUID REFERENCES organization(id),
+		name VARCHAR(100) NOT NULL,
+		description VARCHAR(500) NOT NULL,
+		service_type VARCHAR(50) NOT NULL REFERENCES service_type(code),
+		criteria TEXT[] DEFAULT '{}',
+		version INT DEFAULT 1,
+		created_by UUID NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (organization_id, name));
+	`
+	_, err := repo.db.Exec(createTemplateTable)
+	return err
+}
@@ -1,6 +1,7 @@
 package tender
 
 import (
+	"context"
 	"database/sql"
 	"log/slog"
 	"strconv"
@@ -8,9 +9,16 @@ import (
 	"time"
 
 	"avi/internal/database"
+	"avi/internal/metrics"
 	"avi/internal/model"
+	attachmentRepo "avi/internal/repository/attachment"
+	lotRepo "avi/internal/repository/lot"
+	questionRepo "avi/internal/repository/question"
+	tenderVersion "avi/internal/repository/tenderversion"
+	"avi/internal/tracing"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type TenderRepo struct {
@@ -18,59 +26,146 @@ type TenderRepo struct {
 }
 
 func (repo *TenderRepo) CreateTender(
-	name string,
-	description string,
-	serviceType model.TenderServiceType,
-	organizarionId uuid.UUID,
-	userId uuid.UUID,
-) (tender *model.Tender, err error) {
-	var id uuid.UUID
-	var version int32
-	var createdAt time.Time
-	var status model.TenderStatus
+	ctx context.Context, tender *model.Tender, userId uuid.UUID,
+) (*model.Tender, error) {
+	defer metrics.ObserveQuery("tender", "CreateTender", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.CreateTender")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = createTenderTx(ctx, tx, tender, userId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return tender, err
+}
+
+func (repo *TenderRepo) CloneTender(
+	ctx context.Context, source *model.Tender, name string, userId uuid.UUID,
+) (*model.Tender, error) {
+	defer metrics.ObserveQuery("tender", "CloneTender", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.CloneTender")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tender := *source
+	tender.Name = name
+	err = createTenderTx(ctx, tx, &tender, userId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = attachmentRepo.CopyAttachmentsTx(
+		ctx,
+		tx,
+		source.Id,
+		source.Version,
+		model.TenderAttachmentObjectType,
+		tender.Id,
+		tender.Version,
+	)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = lotRepo.CopyLotsTx(ctx, tx, source.Id, tender.Id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
+	err = tx.Commit()
+	return &tender, err
+}
+
+func (repo *TenderRepo) CreateTenderFromTemplate(
+	ctx context.Context, template *model.TenderTemplate, tender *model.Tender, userId uuid.UUID,
+) (*model.Tender, error) {
+	defer metrics.ObserveQuery("tender", "CreateTenderFromTemplate", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.CreateTenderFromTemplate")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = createTenderTx(ctx, tx, tender, userId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = attachmentRepo.CopyAttachmentsTx(
+		ctx,
+		tx,
+		template.Id,
+		template.Version,
+		model.TenderAttachmentObjectType,
+		tender.Id,
+		tender.Version,
+	)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return tender, err
+}
+
+func createTenderTx(ctx context.Context, tx *sql.Tx, tender *model.Tender, userId uuid.UUID) error {
 	createQuery := `
 		INSERT INTO tender 
 		(name, description, service_type, 
-		organization_id, user_id)
-		VALUES ($1, $2, $3, $4, $5) 
+		organization_id, user_id, invite_only,
+		budget, reserve, currency, strict_budget,
+		criteria)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) 
 		RETURNING id, status, version, created_at;
 	`
-	err = repo.db.QueryRow(
+	return tx.QueryRowContext(
+		ctx,
 		createQuery,
-		name,
-		description,
-		serviceType,
-		organizarionId,
+		tender.Name,
+		tender.Description,
+		tender.ServiceType,
+		tender.OrganizationId,
 		userId,
-	).Scan(&id, &status, &version, &createdAt)
-
-	if err != nil {
-		return
-	}
-
-	tender = &model.Tender{
-		Id:             id,
-		Name:           name,
-		Description:    description,
-		ServiceType:    serviceType,
-		Status:         status,
-		OrganizationId: organizarionId,
-		Version:        version,
-		CreatedAt:      createdAt,
-	}
-	return
+		tender.InviteOnly,
+		tender.Budget,
+		tender.Reserve,
+		tender.Currency,
+		tender.StrictBudget,
+		pq.Array(tender.Criteria),
+	).Scan(&tender.Id, &tender.Status, &tender.Version, &tender.CreatedAt)
 }
 
 func (repo *TenderRepo) GetTendersByUserId(
+	ctx context.Context,
 	offset int,
 	limit int,
 	userId uuid.UUID,
 ) (tenders []*model.Tender, err error) {
+	defer metrics.ObserveQuery("tender", "GetTendersByUserId", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.GetTendersByUserId")
+	defer span.End()
 	selectQuery := `
 		SELECT id, name, description,
 		service_type, status, organization_id,
-		version, created_at
+		version, created_at, invite_only, budget, reserve,
+		currency, strict_budget,
+		criteria
 		FROM tender 
 		WHERE user_id = $1
 		ORDER BY name ASC
@@ -85,7 +180,7 @@ func (repo *TenderRepo) GetTendersByUserId(
 
 	selectQuery += ";"
 
-	rows, err := repo.db.Query(selectQuery, userId)
+	rows, err := repo.db.QueryContext(ctx, selectQuery, userId)
 	if err != nil {
 		return
 	}
@@ -102,6 +197,12 @@ func (repo *TenderRepo) GetTendersByUserId(
 			&tender.OrganizationId,
 			&tender.Version,
 			&tender.CreatedAt,
+			&tender.InviteOnly,
+			&tender.Budget,
+			&tender.Reserve,
+			&tender.Currency,
+			&tender.StrictBudget,
+			pq.Array(&tender.Criteria),
 		)
 		if err != nil {
 			return
@@ -112,11 +213,16 @@ func (repo *TenderRepo) GetTendersByUserId(
 }
 
 func (repo *TenderRepo) GetTenders(
-	serviceTypeFlt string,
+	ctx context.Context,
+	serviceTypesFlt []string,
 	offsetFlt string,
 	limitFlt string,
 	orgsIdFlt []uuid.UUID,
+	visibleToFlt *uuid.UUID,
 ) (tenders []*model.Tender, err error) {
+	defer metrics.ObserveQuery("tender", "GetTenders", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.GetTenders")
+	defer span.End()
 	whereClauses := []string{}
 	if len(orgsIdFlt) != 0 {
 		orgsIdStr := []string{}
@@ -126,14 +232,34 @@ func (repo *TenderRepo) GetTenders(
 		whereClauses = append(whereClauses, "organization_id IN ("+strings.Join(orgsIdStr, ",")+")")
 	}
 
-	if serviceTypeFlt != "" {
-		whereClauses = append(whereClauses, "service_type = '"+serviceTypeFlt+"'")
+	if visibleToFlt != nil {
+		viewerId := "'" + visibleToFlt.String() + "'"
+		whereClauses = append(whereClauses, `(invite_only = false
+			OR organization_id IN (
+				SELECT organization_id FROM organization_responsible
+				WHERE user_id = `+viewerId+`)
+			OR id IN (
+				SELECT tender_id FROM invitation
+				WHERE invitee_type = 'User' AND invitee_id = `+viewerId+`
+				OR invitee_type = 'Organization' AND invitee_id IN (
+					SELECT organization_id FROM organization_responsible
+					WHERE user_id = `+viewerId+`)))`)
+	}
+
+	if len(serviceTypesFlt) != 0 {
+		serviceTypesStr := []string{}
+		for _, serviceType := range serviceTypesFlt {
+			serviceTypesStr = append(serviceTypesStr, pq.QuoteLiteral(serviceType))
+		}
+		whereClauses = append(whereClauses, "service_type IN ("+strings.Join(serviceTypesStr, ",")+")")
 	}
 
 	selectQuery := `
 		SELECT id, name, description,
 		service_type, status, organization_id,
-		version, created_at
+		version, created_at, invite_only, budget, reserve,
+		currency, strict_budget,
+		criteria
 		FROM tender 
 	`
 	if len(whereClauses) > 0 {
@@ -152,7 +278,7 @@ func (repo *TenderRepo) GetTenders(
 
 	selectQuery += ";"
 
-	rows, err := repo.db.Query(selectQuery)
+	rows, err := repo.db.QueryContext(ctx, selectQuery)
 	if err != nil {
 		return
 	}
@@ -169,6 +295,12 @@ func (repo *TenderRepo) GetTenders(
 			&tender.OrganizationId,
 			&tender.Version,
 			&tender.CreatedAt,
+			&tender.InviteOnly,
+			&tender.Budget,
+			&tender.Reserve,
+			&tender.Currency,
+			&tender.StrictBudget,
+			pq.Array(&tender.Criteria),
 		)
 		if err != nil {
 			return
@@ -178,17 +310,24 @@ func (repo *TenderRepo) GetTenders(
 	return
 }
 
-func (repo *TenderRepo) GetTenderById(id uuid.UUID) (tender *model.Tender, err error) {
+func (repo *TenderRepo) GetTenderById(
+	ctx context.Context, id uuid.UUID,
+) (tender *model.Tender, err error) {
+	defer metrics.ObserveQuery("tender", "GetTenderById", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.GetTenderById")
+	defer span.End()
 	selectQuery := `
 		SELECT id, name, description,
 		service_type, status, organization_id,
-		version, created_at
+		version, created_at, invite_only, budget, reserve,
+		currency, strict_budget,
+		criteria
 		FROM tender 
 		WHERE id = $1
 	`
 	tender = &model.Tender{}
-	err = repo.db.QueryRow(
-		selectQuery, id,
+	err = repo.db.QueryRowContext(
+		ctx, selectQuery, id,
 	).Scan(
 		&tender.Id,
 		&tender.Name,
@@ -198,25 +337,38 @@ func (repo *TenderRepo) GetTenderById(id uuid.UUID) (tender *model.Tender, err e
 		&tender.OrganizationId,
 		&tender.Version,
 		&tender.CreatedAt,
+		&tender.InviteOnly,
+		&tender.Budget,
+		&tender.Reserve,
+		&tender.Currency,
+		&tender.StrictBudget,
+		pq.Array(&tender.Criteria),
 	)
 
 	return
 }
 
-func (repo *TenderRepo) UpdateTender(tenderUpd *model.Tender) (*model.Tender, error) {
-	tx, err := repo.db.Begin()
+func (repo *TenderRepo) UpdateTender(
+	ctx context.Context, tenderUpd *model.Tender,
+) (*model.Tender, error) {
+	defer metrics.ObserveQuery("tender", "UpdateTender", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.UpdateTender")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	selectOldQuery := `
 		SELECT id, name, description, service_type,
-		status, organization_id, version, created_at
+		status, organization_id, version, created_at,
+		budget, reserve, currency, strict_budget,
+		criteria
 		FROM tender 
 		WHERE id = $1;
 	`
 	tenderOld := model.Tender{}
-	err = tx.QueryRow(
-		selectOldQuery, tenderUpd.Id,
+	err = tx.QueryRowContext(
+		ctx, selectOldQuery, tenderUpd.Id,
 	).Scan(
 		&tenderOld.Id,
 		&tenderOld.Name,
@@ -226,6 +378,11 @@ func (repo *TenderRepo) UpdateTender(tenderUpd *model.Tender) (*model.Tender, er
 		&tenderOld.OrganizationId,
 		&tenderOld.Version,
 		&tenderOld.CreatedAt,
+		&tenderOld.Budget,
+		&tenderOld.Reserve,
+		&tenderOld.Currency,
+		&tenderOld.StrictBudget,
+		pq.Array(&tenderOld.Criteria),
 	)
 	if err != nil {
 		tx.Rollback()
@@ -235,10 +392,12 @@ func (repo *TenderRepo) UpdateTender(tenderUpd *model.Tender) (*model.Tender, er
 	createHistoryQuery := `
 		INSERT INTO tender_history 
 		(id, name, description, service_type,
-		status, organization_id, version, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+		status, organization_id, version, created_at,
+		budget, reserve, currency, strict_budget, criteria)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
 	`
-	_, err = tx.Exec(
+	_, err = tx.ExecContext(
+		ctx,
 		createHistoryQuery,
 		&tenderOld.Id,
 		&tenderOld.Name,
@@ -248,6 +407,11 @@ func (repo *TenderRepo) UpdateTender(tenderUpd *model.Tender) (*model.Tender, er
 		&tenderOld.OrganizationId,
 		&tenderOld.Version,
 		&tenderOld.CreatedAt,
+		&tenderOld.Budget,
+		&tenderOld.Reserve,
+		&tenderOld.Currency,
+		&tenderOld.StrictBudget,
+		pq.Array(tenderOld.Criteria),
 	)
 	if err != nil {
 		tx.Rollback()
@@ -258,11 +422,15 @@ func (repo *TenderRepo) UpdateTender(tenderUpd *model.Tender) (*model.Tender, er
 		UPDATE tender 
 		SET name = $1, description = $2,
 		service_type = $3, status = $4,
-		organization_id = $5, version = $6
-		WHERE id = $7;
+		organization_id = $5, version = $6,
+		invite_only = $7, budget = $8, reserve = $9,
+		currency = $10, strict_budget = $11,
+		criteria = $12
+		WHERE id = $13;
 	`
 	tenderUpd.Version += 1
-	_, err = tx.Exec(
+	_, err = tx.ExecContext(
+		ctx,
 		updateQuery,
 		tenderUpd.Name,
 		tenderUpd.Description,
@@ -270,30 +438,63 @@ func (repo *TenderRepo) UpdateTender(tenderUpd *model.Tender) (*model.Tender, er
 		tenderUpd.Status,
 		tenderUpd.OrganizationId,
 		tenderUpd.Version,
+		tenderUpd.InviteOnly,
+		tenderUpd.Budget,
+		tenderUpd.Reserve,
+		tenderUpd.Currency,
+		tenderUpd.StrictBudget,
+		pq.Array(tenderUpd.Criteria),
 		tenderUpd.Id,
 	)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
 	}
+
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, tenderUpd.Id, tenderOld.Version, tenderUpd.Version, uuid.Nil,
+	)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if tenderUpd.Status == model.TenderStatusClosed {
+		err = questionRepo.CloseTenderQuestionsTx(ctx, tx, tenderUpd.Id)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
 	err = tx.Commit()
-	return tenderUpd, err
+	if err != nil {
+		return nil, err
+	}
+	return tenderUpd, nil
 }
 
-func (repo *TenderRepo) RollBackTender(id uuid.UUID, version int32) (*model.Tender, error) {
-	tx, err := repo.db.Begin()
+func (repo *TenderRepo) RollBackTender(
+	ctx context.Context, id uuid.UUID, version int32,
+) (*model.Tender, error) {
+	defer metrics.ObserveQuery("tender", "RollBackTender", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.RollBackTender")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	selectQuery := `
 		SELECT id, name, description, service_type,
-		status, organization_id, version, created_at
+		status, organization_id, version, created_at,
+		invite_only, budget, reserve,
+		currency, strict_budget,
+		criteria
 		FROM tender 
 		WHERE id = $1;
 	`
 	tender := model.Tender{}
-	err = tx.QueryRow(
-		selectQuery, id,
+	err = tx.QueryRowContext(
+		ctx, selectQuery, id,
 	).Scan(
 		&tender.Id,
 		&tender.Name,
@@ -303,6 +504,12 @@ func (repo *TenderRepo) RollBackTender(id uuid.UUID, version int32) (*model.Tend
 		&tender.OrganizationId,
 		&tender.Version,
 		&tender.CreatedAt,
+		&tender.InviteOnly,
+		&tender.Budget,
+		&tender.Reserve,
+		&tender.Currency,
+		&tender.StrictBudget,
+		pq.Array(&tender.Criteria),
 	)
 	if err != nil {
 		tx.Rollback()
@@ -312,10 +519,12 @@ func (repo *TenderRepo) RollBackTender(id uuid.UUID, version int32) (*model.Tend
 	createHistoryQuery := `
 		INSERT INTO tender_history 
 		(id, name, description, service_type,
-		status, organization_id, version, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+		status, organization_id, version, created_at,
+		budget, reserve, currency, strict_budget, criteria)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
 	`
-	_, err = tx.Exec(
+	_, err = tx.ExecContext(
+		ctx,
 		createHistoryQuery,
 		&tender.Id,
 		&tender.Name,
@@ -325,6 +534,11 @@ func (repo *TenderRepo) RollBackTender(id uuid.UUID, version int32) (*model.Tend
 		&tender.OrganizationId,
 		&tender.Version,
 		&tender.CreatedAt,
+		&tender.Budget,
+		&tender.Reserve,
+		&tender.Currency,
+		&tender.StrictBudget,
+		pq.Array(tender.Criteria),
 	)
 	if err != nil {
 		tx.Rollback()
@@ -333,13 +547,15 @@ func (repo *TenderRepo) RollBackTender(id uuid.UUID, version int32) (*model.Tend
 
 	selectVersionQuery := `
 		SELECT id, name, description, service_type,
-		status, organization_id, version, created_at
+		status, organization_id, version, created_at,
+		budget, reserve, currency, strict_budget,
+		criteria
 		FROM tender_history
 		WHERE id = $1 AND version = $2;
 	`
 	tenderOld := model.Tender{}
-	err = tx.QueryRow(
-		selectVersionQuery, id, version,
+	err = tx.QueryRowContext(
+		ctx, selectVersionQuery, id, version,
 	).Scan(
 		&tenderOld.Id,
 		&tenderOld.Name,
@@ -349,6 +565,11 @@ func (repo *TenderRepo) RollBackTender(id uuid.UUID, version int32) (*model.Tend
 		&tenderOld.OrganizationId,
 		&tenderOld.Version,
 		&tenderOld.CreatedAt,
+		&tenderOld.Budget,
+		&tenderOld.Reserve,
+		&tenderOld.Currency,
+		&tenderOld.StrictBudget,
+		pq.Array(&tenderOld.Criteria),
 	)
 	if err != nil {
 		tx.Rollback()
@@ -359,11 +580,16 @@ func (repo *TenderRepo) RollBackTender(id uuid.UUID, version int32) (*model.Tend
 		UPDATE tender 
 		SET name = $1, description = $2,
 		service_type = $3, status = $4,
-		organization_id = $5, version = $6
-		WHERE id = $7;
+		organization_id = $5, version = $6,
+		budget = $7, reserve = $8,
+		currency = $9, strict_budget = $10,
+		criteria = $11
+		WHERE id = $12;
 	`
 	tenderOld.Version = tender.Version + 1
-	_, err = tx.Exec(
+	tenderOld.InviteOnly = tender.InviteOnly
+	_, err = tx.ExecContext(
+		ctx,
 		updateQuery,
 		&tenderOld.Name,
 		&tenderOld.Description,
@@ -371,6 +597,11 @@ func (repo *TenderRepo) RollBackTender(id uuid.UUID, version int32) (*model.Tend
 		&tenderOld.Status,
 		&tenderOld.OrganizationId,
 		&tenderOld.Version,
+		&tenderOld.Budget,
+		&tenderOld.Reserve,
+		&tenderOld.Currency,
+		&tenderOld.StrictBudget,
+		pq.Array(tenderOld.Criteria),
 		&tenderOld.Id,
 	)
 	if err != nil {
@@ -378,9 +609,138 @@ func (repo *TenderRepo) RollBackTender(id uuid.UUID, version int32) (*model.Tend
 		return nil, err
 	}
 
-	tx.Commit()
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, id, version, tenderOld.Version, uuid.Nil,
+	)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if tenderOld.Status == model.TenderStatusClosed {
+		err = questionRepo.CloseTenderQuestionsTx(ctx, tx, id)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return &tenderOld, nil
+}
+
+func (repo *TenderRepo) AddAttachment(
+	ctx context.Context, tenderId uuid.UUID, attachment *model.Attachment,
+) (*model.Tender, error) {
+	defer metrics.ObserveQuery("tender", "AddAttachment", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.AddAttachment")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := tenderVersion.NewVersionTx(ctx, tx, tenderId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, tenderId, tender.Version-1, tender.Version, uuid.Nil,
+	)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = attachmentRepo.CreateAttachmentTx(ctx, tx, attachment, tender.Version)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return tender, err
+}
+
+func (repo *TenderRepo) RemoveAttachment(
+	ctx context.Context, tenderId uuid.UUID, attachmentId uuid.UUID,
+) (*model.Tender, error) {
+	defer metrics.ObserveQuery("tender", "RemoveAttachment", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.RemoveAttachment")
+	defer span.End()
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tender, err := tenderVersion.NewVersionTx(ctx, tx, tenderId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, tenderId, tender.Version-1, tender.Version, attachmentId,
+	)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return tender, err
+}
+
+func (repo *TenderRepo) GetTenderHistory(
+	ctx context.Context, id uuid.UUID,
+) (tenders []*model.Tender, err error) {
+	defer metrics.ObserveQuery("tender", "GetTenderHistory", time.Now())
+	ctx, span := tracing.Start(ctx, "TenderRepo.GetTenderHistory")
+	defer span.End()
+	selectQuery := `
+		SELECT id, name, description,
+		service_type, status, organization_id,
+		version, created_at, budget, reserve,
+		currency, strict_budget,
+		criteria
+		FROM tender_history
+		WHERE id = $1
+		ORDER BY version ASC;
+	`
+	rows, err := repo.db.QueryContext(ctx, selectQuery, id)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
 
-	return &tenderOld, err
+	for rows.Next() {
+		var tender model.Tender
+		err = rows.Scan(
+			&tender.Id,
+			&tender.Name,
+			&tender.Description,
+			&tender.ServiceType,
+			&tender.Status,
+			&tender.OrganizationId,
+			&tender.Version,
+			&tender.CreatedAt,
+			&tender.Budget,
+			&tender.Reserve,
+			&tender.Currency,
+			&tender.StrictBudget,
+			pq.Array(&tender.Criteria),
+		)
+		if err != nil {
+			return
+		}
+		tenders = append(tenders, &tender)
+	}
+	return
 }
 
 func NewRepo() (repo *TenderRepo, err error) {
@@ -389,21 +749,121 @@ func NewRepo() (repo *TenderRepo, err error) {
 		return
 	}
 	repo = &TenderRepo{db: db}
+	return
+}
+
+func Migrate() (err error) {
+	repo, err := NewRepo()
+	if err != nil {
+		return
+	}
 
 	table, err := repo.tableExists()
 	if err != nil {
 		return
 	}
 
-	if table {
+	if !table {
+		err = repo.createTable()
+		if err == nil {
+			slog.Info("Table 'bid' and 'bid_histrory' is created")
+		} else {
+			slog.Info("Can not create Table 'bid' and 'bid_histrory'")
+			return
+		}
+	}
+
+	column, err := repo.columnExists("invite_only")
+	if err != nil {
+		return
+	}
+
+	if !column {
+		_, err = repo.db.Exec(`
+			ALTER TABLE tender
+			ADD COLUMN IF NOT EXISTS invite_only BOOLEAN DEFAULT false;
+		`)
+		if err == nil {
+			slog.Info("Column 'invite_only' is added to table 'tender'")
+		} else {
+			slog.Info("Can not add column 'invite_only' to table 'tender'")
+			return
+		}
+	}
+
+	column, err = repo.columnExists("budget")
+	if err != nil {
+		return
+	}
+
+	if !column {
+		err = repo.addBudgetColumns()
+		if err == nil {
+			slog.Info("Budget columns are added to tables 'tender' and 'tender_history'")
+		} else {
+			slog.Info("Can not add budget columns to table 'tender'")
+			return
+		}
+	}
+
+	status, err := repo.statusExists(model.TenderStatusCommercial)
+	if err != nil {
+		return
+	}
+
+	if !status {
+		_, err = repo.db.Exec(`
+			ALTER TYPE tender_status ADD VALUE IF NOT EXISTS 'Prequalification';
+			ALTER TYPE tender_status ADD VALUE IF NOT EXISTS 'Commercial';
+		`)
+		if err == nil {
+			slog.Info("Stage statuses are added to type 'tender_status'")
+		} else {
+			slog.Info("Can not add stage statuses to type 'tender_status'")
+			return
+		}
+	}
+
+	column, err = repo.columnExists("criteria")
+	if err != nil {
+		return
+	}
+
+	if !column {
+		_, err = repo.db.Exec(`
+			ALTER TABLE tender
+			ADD COLUMN IF NOT EXISTS criteria TEXT[] DEFAULT '{}';
+			ALTER TABLE tender_history
+			ADD COLUMN IF NOT EXISTS criteria TEXT[] DEFAULT '{}';
+		`)
+		if err == nil {
+			slog.Info("Column 'criteria' is added to tables 'tender' and 'tender_history'")
+		} else {
+			slog.Info("Can not add column 'criteria' to table 'tender'")
+			return
+		}
+	}
+
+	enum, err := repo.serviceTypeIsEnum()
+	if err != nil {
 		return
 	}
 
-	err = repo.createTable()
+	if !enum {
+		return
+	}
+
+	_, err = repo.db.Exec(`
+		ALTER TABLE tender
+		ALTER COLUMN service_type TYPE VARCHAR(50) USING service_type::text,
+		ADD FOREIGN KEY (service_type) REFERENCES service_type(code);
+		ALTER TABLE tender_history
+		ALTER COLUMN service_type TYPE VARCHAR(50) USING service_type::text;
+	`)
 	if err == nil {
-		slog.Info("Table 'bid' and 'bid_histrory' is created")
+		slog.Info("Column 'service_type' of table 'tender' references table 'service_type'")
 	} else {
-		slog.Info("Can not create Table 'bid' and 'bid_histrory'")
+		slog.Info("Can not convert column 'service_type' of table 'tender'")
 	}
 	return
 }
@@ -427,44 +887,99 @@ func (repo *TenderRepo) tableExists() (table bool, err error) {
 	return
 }
 
+func (repo *TenderRepo) columnExists(name string) (column bool, err error) {
+	err = repo.db.QueryRow(
+		`SELECT EXISTS (SELECT FROM information_schema.columns 
+		WHERE table_name = 'tender' AND column_name = $1);`,
+		name,
+	).Scan(&column)
+	return
+}
+
+func (repo *TenderRepo) serviceTypeIsEnum() (enum bool, err error) {
+	err = repo.db.QueryRow(
+		`SELECT EXISTS (SELECT FROM information_schema.columns 
+		WHERE table_name = 'tender' AND column_name = 'service_type'
+		AND data_type = 'USER-DEFINED');`,
+	).Scan(&enum)
+	return
+}
+
+func (repo *TenderRepo) statusExists(
+	status model.TenderStatus,
+) (exists bool, err error) {
+	err = repo.db.QueryRow(
+		`SELECT EXISTS (SELECT FROM pg_enum
+		WHERE enumtypid = 'tender_status'::regtype AND enumlabel = $1);`,
+		status,
+	).Scan(&exists)
+	return
+}
+
 func (repo *TenderRepo) createTable() error {
 	createTenderStatus := `
 		CREATE TYPE tender_status 
-		AS ENUM ('Created', 'Published', 'Closed');
-	`
-	createTenderServiceType := `
-		CREATE TYPE tender_service_type 
-		AS ENUM ('Construction', 'Delivery', 'Manufacture');
+		AS ENUM ('Created', 'Published', 'Prequalification',
+		'Commercial', 'Closed');
 	`
 	createTenderTable := `
 		CREATE TABLE tender (
 		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 		name VARCHAR(100) UNIQUE NOT NULL,
 		description VARCHAR(500) NOT NULL,
-		service_type tender_service_type NOT NULL,
+		service_type VARCHAR(50) NOT NULL REFERENCES service_type(code),
 		status tender_status DEFAULT 'Created',
 		user_id UUID REFERENCES employee(id),
 		organization_id UUID REFERENCES organization(id),
 		version INT DEFAULT 1,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		invite_only BOOLEAN DEFAULT false,
+		budget NUMERIC(15,2),
+		reserve NUMERIC(15,2),
+		currency VARCHAR(3) DEFAULT '',
+		strict_budget BOOLEAN DEFAULT false,
+		criteria TEXT[] DEFAULT '{}');
 	`
 	createTenderHistoryTable := `
 		CREATE TABLE tender_history (
 		id UUID NOT NULL,
 		name VARCHAR(100) NOT NULL,
 		description VARCHAR(500) NOT NULL,
-		service_type tender_service_type NOT NULL,
+		service_type VARCHAR(50) NOT NULL,
 		status tender_status NOT NULL,
 		organization_id UUID NOT NULL,
 		version INT NOT NULL,
 		created_at TIMESTAMP NOT NULL,
+		budget NUMERIC(15,2),
+		reserve NUMERIC(15,2),
+		currency VARCHAR(3) DEFAULT '',
+		strict_budget BOOLEAN DEFAULT false,
+		criteria TEXT[] DEFAULT '{}',
 		PRIMARY KEY (id, version));
 	`
 	_, err := repo.db.Exec(
 		createTenderStatus +
-			createTenderServiceType +
 			createTenderTable +
 			createTenderHistoryTable,
 	)
 	return err
 }
+
+func (repo *TenderRepo) addBudgetColumns() error {
+	alterTenderTable := `
+		ALTER TABLE tender
+		ADD COLUMN IF NOT EXISTS budget NUMERIC(15,2),
+		ADD COLUMN IF NOT EXISTS reserve NUMERIC(15,2),
+		ADD COLUMN IF NOT EXISTS currency VARCHAR(3) DEFAULT '',
+		ADD COLUMN IF NOT EXISTS strict_budget BOOLEAN DEFAULT false;
+	`
+	alterTenderHistoryTable := `
+		ALTER TABLE tender_history
+		ADD COLUMN IF NOT EXISTS budget NUMERIC(15,2),
+		ADD COLUMN IF NOT EXISTS reserve NUMERIC(15,2),
+		ADD COLUMN IF NOT EXISTS currency VARCHAR(3) DEFAULT '',
+		ADD COLUMN IF NOT EXISTS strict_budget BOOLEAN DEFAULT false;
+	`
+	_, err := repo.db.Exec(alterTenderTable + alterTenderHistoryTable)
+	return err
+}
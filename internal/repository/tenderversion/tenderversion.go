@@ -0,0 +1,125 @@
+package tenderversion
+
+import (
+	"context"
+	"database/sql"
+
+	"avi/internal/model"
+	attachmentRepo "avi/internal/repository/attachment"
+	questionRepo "avi/internal/repository/question"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func NewVersionTx(
+	ctx context.Context, tx *sql.Tx, id uuid.UUID,
+) (*model.Tender, error) {
+	selectQuery := `
+		SELECT id, name, description, service_type,
+		status, organization_id, version, created_at,
+		invite_only, budget, reserve,
+		currency, strict_budget,
+		criteria
+		FROM tender 
+		WHERE id = $1
+		FOR UPDATE;
+	`
+	tender := model.Tender{}
+	err := tx.QueryRowContext(
+		ctx, selectQuery, id,
+	).Scan(
+		&tender.Id,
+		&tender.Name,
+		&tender.Description,
+		&tender.ServiceType,
+		&tender.Status,
+		&tender.OrganizationId,
+		&tender.Version,
+		&tender.CreatedAt,
+		&tender.InviteOnly,
+		&tender.Budget,
+		&tender.Reserve,
+		&tender.Currency,
+		&tender.StrictBudget,
+		pq.Array(&tender.Criteria),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	createHistoryQuery := `
+		INSERT INTO tender_history 
+		(id, name, description, service_type,
+		status, organization_id, version, created_at,
+		budget, reserve, currency, strict_budget, criteria)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+	`
+	_, err = tx.ExecContext(
+		ctx,
+		createHistoryQuery,
+		tender.Id,
+		tender.Name,
+		tender.Description,
+		tender.ServiceType,
+		tender.Status,
+		tender.OrganizationId,
+		tender.Version,
+		tender.CreatedAt,
+		tender.Budget,
+		tender.Reserve,
+		tender.Currency,
+		tender.StrictBudget,
+		pq.Array(tender.Criteria),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tender.Version += 1
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE tender SET version = $1 WHERE id = $2;`,
+		tender.Version,
+		tender.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &tender, nil
+}
+
+func UpdateStatusTx(
+	ctx context.Context, tx *sql.Tx, id uuid.UUID, status model.TenderStatus,
+) (*model.Tender, error) {
+	tender, err := NewVersionTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tender.Status = status
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE tender SET status = $1 WHERE id = $2;`,
+		tender.Status,
+		tender.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = attachmentRepo.CopyVersionTx(
+		ctx, tx, tender.Id, tender.Version-1, tender.Version, uuid.Nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if tender.Status == model.TenderStatusClosed {
+		err = questionRepo.CloseTenderQuestionsTx(ctx, tx, tender.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tender, nil
+}
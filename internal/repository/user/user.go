@@ -1,10 +1,14 @@
 package user
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"avi/internal/database"
+	"avi/internal/metrics"
 	"avi/internal/model"
+	"avi/internal/tracing"
 
 	"github.com/google/uuid"
 )
@@ -13,13 +17,18 @@ type UserRepo struct {
 	db *sql.DB
 }
 
-func (repo *UserRepo) GetUserByName(name string) (user *model.User, err error) {
+func (repo *UserRepo) GetUserByName(
+	ctx context.Context, name string,
+) (user *model.User, err error) {
+	defer metrics.ObserveQuery("user", "GetUserByName", time.Now())
+	ctx, span := tracing.Start(ctx, "UserRepo.GetUserByName")
+	defer span.End()
 	selectQuery := `
 		SELECT id, username, first_name, last_name, created_at, updated_at
 		FROM employee WHERE username = $1 
 	`
 	user = &model.User{}
-	row := repo.db.QueryRow(selectQuery, name)
+	row := repo.db.QueryRowContext(ctx, selectQuery, name)
 	err = row.Scan(
 		&user.Id,
 		&user.Username,
@@ -31,13 +40,16 @@ func (repo *UserRepo) GetUserByName(name string) (user *model.User, err error) {
 	return
 }
 
-func (repo *UserRepo) GetUserById(id uuid.UUID) (user *model.User, err error) {
+func (repo *UserRepo) GetUserById(ctx context.Context, id uuid.UUID) (user *model.User, err error) {
+	defer metrics.ObserveQuery("user", "GetUserById", time.Now())
+	ctx, span := tracing.Start(ctx, "UserRepo.GetUserById")
+	defer span.End()
 	selectQuery := `
 		SELECT id, username, first_name, last_name, created_at, updated_at
 		FROM employee WHERE id = $1 
 	`
 	user = &model.User{}
-	row := repo.db.QueryRow(selectQuery, id)
+	row := repo.db.QueryRowContext(ctx, selectQuery, id)
 	err = row.Scan(
 		&user.Id,
 		&user.Username,
@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/lib/pq"
+
+	attachmentRepo "avi/internal/repository/attachment"
+	bidRepo "avi/internal/repository/bid"
+	idempotencyRepo "avi/internal/repository/idempotency"
+	invitationRepo "avi/internal/repository/invitation"
+	lotRepo "avi/internal/repository/lot"
+	questionRepo "avi/internal/repository/question"
+	serviceTypeRepo "avi/internal/repository/servicetype"
+	shortlistRepo "avi/internal/repository/shortlist"
+	templateRepo "avi/internal/repository/template"
+	tenderRepo "avi/internal/repository/tender"
+)
+
+var Tables = []string{
+	"employee",
+	"organization",
+	"organization_responsible",
+	"service_type",
+	"tender",
+	"tender_history",
+	"tender_template",
+	"lot",
+	"bid_lot",
+	"invitation",
+	"question",
+	"attachment",
+	"attachment_version",
+	"bid",
+	"bid_history",
+	"review",
+	"review_history",
+	"review_reply",
+	"reputation",
+	"shortlist",
+	"idempotency_key",
+}
+
+func Apply() error {
+	migrations := []func() error{
+		serviceTypeRepo.Migrate,
+		attachmentRepo.Migrate,
+		questionRepo.Migrate,
+		invitationRepo.Migrate,
+		tenderRepo.Migrate,
+		lotRepo.Migrate,
+		bidRepo.Migrate,
+		shortlistRepo.Migrate,
+		templateRepo.Migrate,
+		idempotencyRepo.Migrate,
+	}
+	for _, migrate := range migrations {
+		err := migrate()
+		if err != nil {
+			return fmt.Errorf("can not apply schema: %w", err)
+		}
+	}
+	return nil
+}
+
+func Pending(ctx context.Context, db *sql.DB) (pending []string, err error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT table_name FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_name = ANY($1);`,
+		pq.Array(Tables),
+	)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var existing []string
+	for rows.Next() {
+		var table string
+		err = rows.Scan(&table)
+		if err != nil {
+			return
+		}
+		existing = append(existing, table)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	for _, table := range Tables {
+		if !slices.Contains(existing, table) {
+			pending = append(pending, table)
+		}
+	}
+	return
+}
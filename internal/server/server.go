@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"avi/internal/config"
+)
+
+var ErrorWorkerStopped = errors.New("worker stopped")
+
+type Worker interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type WorkerStatus struct {
+	Name    string
+	Running bool
+	Err     error
+}
+
+type Server struct {
+	cfg          config.Server
+	http         *http.Server
+	workers      []Worker
+	shuttingDown atomic.Bool
+
+	mu       sync.RWMutex
+	statuses map[string]WorkerStatus
+}
+
+func New(cfg config.Server, handler http.Handler, workers ...Worker) *Server {
+	return &Server{
+		cfg: cfg,
+		http: &http.Server{
+			Addr:              cfg.Address,
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+		},
+		workers:  workers,
+		statuses: map[string]WorkerStatus{},
+	}
+}
+
+func (s *Server) ShuttingDown() bool {
+	return s.shuttingDown.Load()
+}
+
+func (s *Server) Workers() []WorkerStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]WorkerStatus, 0, len(s.workers))
+	for _, worker := range s.workers {
+		statuses = append(statuses, s.statuses[worker.Name()])
+	}
+	return statuses
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	workersDone := s.startWorkers(workersCtx)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server is listening", "address", s.cfg.Address)
+		serveErr <- s.http.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		s.shuttingDown.Store(true)
+		stopWorkers()
+		<-workersDone
+		return err
+	case <-ctx.Done():
+	}
+
+	s.shuttingDown.Store(true)
+	if s.cfg.DrainDelay > 0 {
+		drainCtx, stopDrain := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		s.drain(drainCtx)
+		stopDrain()
+	}
+
+	slog.Info("shutting down server", "timeout", s.cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+
+	err := s.http.Shutdown(shutdownCtx)
+	if err != nil {
+		slog.Error("server shutdown: " + err.Error())
+		s.http.Close()
+	}
+
+	stopWorkers()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		err = errors.Join(err, errors.New("background workers did not stop before shutdown deadline"))
+	}
+
+	if serveErr := <-serveErr; !errors.Is(serveErr, http.ErrServerClosed) {
+		err = errors.Join(err, serveErr)
+	}
+	return err
+}
+
+func (s *Server) drain(ctx context.Context) {
+	slog.Info("draining server before shutdown", "delay", s.cfg.DrainDelay)
+	timer := time.NewTimer(s.cfg.DrainDelay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		slog.Warn("drain is interrupted by repeated signal")
+	}
+}
+
+func (s *Server) startWorkers(ctx context.Context) <-chan struct{} {
+	var wg sync.WaitGroup
+	for _, worker := range s.workers {
+		s.setStatus(WorkerStatus{Name: worker.Name(), Running: true})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := worker.Run(ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("worker " + worker.Name() + " stopped: " + err.Error())
+			}
+			if err == nil || errors.Is(err, context.Canceled) {
+				err = ErrorWorkerStopped
+			}
+			s.setStatus(WorkerStatus{Name: worker.Name(), Err: err})
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
+
+func (s *Server) setStatus(status WorkerStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses[status.Name] = status
+}
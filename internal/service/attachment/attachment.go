@@ -0,0 +1,420 @@
+package attachment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+
+	"avi/internal/config"
+	"avi/internal/logging"
+	"avi/internal/model"
+	"avi/internal/repository/attachment"
+	"avi/internal/repository/bid"
+	"avi/internal/repository/template"
+	"avi/internal/repository/tender"
+	"avi/internal/storage"
+	"avi/internal/tracing"
+)
+
+var ErrorTenderNotFound = errors.New("tender does not exist")
+var ErrorBidNotFound = errors.New("bid does not exist")
+var ErrorTemplateNotFound = errors.New("template does not exist")
+var ErrorAttachmentNotFound = errors.New("attachment does not exist")
+var ErrorAttachmentTooLarge = errors.New("attachment is too large")
+var ErrorAttachmentEmpty = errors.New("attachment is empty")
+var ErrorAttachmentTypeNotAllowed = errors.New("attachment type is not allowed")
+var ErrorIncorrectFileName = errors.New("incorrect file name")
+
+var ErrorStorageNotConfigured = errors.New("attachment storage is not configured")
+
+var current atomic.Pointer[settings]
+
+type settings struct {
+	storage storage.BlobStorage
+	maxSize int64
+}
+
+var allowedContentTypes = []string{
+	"application/pdf",
+	"application/zip",
+	"application/msword",
+	"application/vnd.ms-excel",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.oasis.opendocument.text",
+	"application/vnd.oasis.opendocument.spreadsheet",
+	"image/png",
+	"image/jpeg",
+	"text/plain",
+	"text/csv",
+}
+
+type AttachmentService struct {
+	tenderRepo     *tender.TenderRepo
+	bidRepo        *bid.BidRepo
+	templateRepo   *template.TemplateRepo
+	attachmentRepo *attachment.AttachmentRepo
+	storage        storage.BlobStorage
+	maxSize        int64
+}
+
+func (service *AttachmentService) MaxSize() int64 {
+	return service.maxSize
+}
+
+func (service *AttachmentService) UploadTenderAttachment(
+	ctx context.Context, tenderId uuid.UUID, fileName string, body io.Reader,
+) (*model.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.UploadTenderAttachment")
+	defer span.End()
+	_, err := service.tenderRepo.GetTenderById(ctx, tenderId)
+	if err != nil {
+		return nil, ErrorTenderNotFound
+	}
+
+	attachment, err := service.store(
+		ctx, model.TenderAttachmentObjectType, tenderId, fileName, body,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = service.tenderRepo.AddAttachment(ctx, tenderId, attachment)
+	if err != nil {
+		service.discard(ctx, attachment)
+		return nil, errors.New("can not add attachment")
+	}
+	return attachment, nil
+}
+
+func (service *AttachmentService) UploadBidAttachment(
+	ctx context.Context, bidId uuid.UUID, fileName string, body io.Reader,
+) (*model.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.UploadBidAttachment")
+	defer span.End()
+	_, err := service.bidRepo.GetBidById(ctx, bidId)
+	if err != nil {
+		return nil, ErrorBidNotFound
+	}
+
+	attachment, err := service.store(
+		ctx, model.BidAttachmentObjectType, bidId, fileName, body,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = service.bidRepo.AddAttachment(ctx, bidId, attachment)
+	if err != nil {
+		service.discard(ctx, attachment)
+		return nil, errors.New("can not add attachment")
+	}
+	return attachment, nil
+}
+
+func (service *AttachmentService) UploadTemplateAttachment(
+	ctx context.Context, templateId uuid.UUID, fileName string, body io.Reader,
+) (*model.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.UploadTemplateAttachment")
+	defer span.End()
+	_, err := service.templateRepo.GetTemplateById(ctx, templateId)
+	if err != nil {
+		return nil, ErrorTemplateNotFound
+	}
+
+	attachment, err := service.store(
+		ctx, model.TemplateAttachmentObjectType, templateId, fileName, body,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = service.templateRepo.AddAttachment(ctx, templateId, attachment)
+	if err != nil {
+		service.discard(ctx, attachment)
+		return nil, errors.New("can not add attachment")
+	}
+	return attachment, nil
+}
+
+func (service *AttachmentService) GetTenderAttachments(
+	ctx context.Context, tenderId uuid.UUID, version int32,
+) ([]*model.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.GetTenderAttachments")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
+	if err != nil {
+		return nil, ErrorTenderNotFound
+	}
+	if version <= 0 {
+		version = tender.Version
+	}
+	if version > tender.Version {
+		return nil, errors.New("tender version does not exist")
+	}
+	attachments, err := service.attachmentRepo.GetAttachments(ctx, tenderId, version)
+	if err != nil {
+		return nil, errors.New("can not get attachments")
+	}
+	return attachments, nil
+}
+
+func (service *AttachmentService) GetBidAttachments(
+	ctx context.Context, bidId uuid.UUID, version int32,
+) ([]*model.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.GetBidAttachments")
+	defer span.End()
+	bid, err := service.bidRepo.GetBidById(ctx, bidId)
+	if err != nil {
+		return nil, ErrorBidNotFound
+	}
+	if version <= 0 {
+		version = bid.Version
+	}
+	if version > bid.Version {
+		return nil, errors.New("bid version does not exist")
+	}
+	attachments, err := service.attachmentRepo.GetAttachments(ctx, bidId, version)
+	if err != nil {
+		return nil, errors.New("can not get attachments")
+	}
+	return attachments, nil
+}
+
+func (service *AttachmentService) GetTemplateAttachments(
+	ctx context.Context, templateId uuid.UUID,
+) ([]*model.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.GetTemplateAttachments")
+	defer span.End()
+	template, err := service.templateRepo.GetTemplateById(ctx, templateId)
+	if err != nil {
+		return nil, ErrorTemplateNotFound
+	}
+	attachments, err := service.attachmentRepo.GetAttachments(
+		ctx, templateId, template.Version,
+	)
+	if err != nil {
+		return nil, errors.New("can not get attachments")
+	}
+	return attachments, nil
+}
+
+func (service *AttachmentService) OpenAttachment(
+	ctx context.Context, objectId uuid.UUID, attachmentId uuid.UUID,
+) (*model.Attachment, io.ReadCloser, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.OpenAttachment")
+	defer span.End()
+	attachment, err := service.attachmentRepo.GetAttachmentById(ctx, objectId, attachmentId)
+	if err != nil {
+		return nil, nil, ErrorAttachmentNotFound
+	}
+	content, err := service.storage.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrorObjectNotFound) {
+		return nil, nil, ErrorAttachmentNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err.Error())
+		return nil, nil, errors.New("can not read attachment")
+	}
+	return attachment, content, nil
+}
+
+func (service *AttachmentService) DeleteTenderAttachment(
+	ctx context.Context, tenderId uuid.UUID, attachmentId uuid.UUID,
+) (*model.Tender, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.DeleteTenderAttachment")
+	defer span.End()
+	attachments, err := service.GetTenderAttachments(ctx, tenderId, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !containsAttachment(attachments, attachmentId) {
+		return nil, ErrorAttachmentNotFound
+	}
+	tender, err := service.tenderRepo.RemoveAttachment(ctx, tenderId, attachmentId)
+	if err != nil {
+		return nil, errors.New("can not delete attachment")
+	}
+	return tender, nil
+}
+
+func (service *AttachmentService) DeleteBidAttachment(
+	ctx context.Context, bidId uuid.UUID, attachmentId uuid.UUID,
+) (*model.Bid, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.DeleteBidAttachment")
+	defer span.End()
+	attachments, err := service.GetBidAttachments(ctx, bidId, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !containsAttachment(attachments, attachmentId) {
+		return nil, ErrorAttachmentNotFound
+	}
+	bid, err := service.bidRepo.RemoveAttachment(ctx, bidId, attachmentId)
+	if err != nil {
+		return nil, errors.New("can not delete attachment")
+	}
+	return bid, nil
+}
+
+func (service *AttachmentService) DeleteTemplateAttachment(
+	ctx context.Context, templateId uuid.UUID, attachmentId uuid.UUID,
+) (*model.TenderTemplate, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.DeleteTemplateAttachment")
+	defer span.End()
+	attachments, err := service.GetTemplateAttachments(ctx, templateId)
+	if err != nil {
+		return nil, err
+	}
+	if !containsAttachment(attachments, attachmentId) {
+		return nil, ErrorAttachmentNotFound
+	}
+	template, err := service.templateRepo.RemoveAttachment(ctx, templateId, attachmentId)
+	if err != nil {
+		return nil, errors.New("can not delete attachment")
+	}
+	return template, nil
+}
+
+func (service *AttachmentService) store(
+	ctx context.Context,
+	objectType model.AttachmentObjectType,
+	objectId uuid.UUID,
+	fileName string,
+	body io.Reader,
+) (*model.Attachment, error) {
+	fileName = filepath.Base(filepath.Clean("/" + fileName))
+	if fileName == "/" || fileName == "." ||
+		!utf8.ValidString(fileName) || utf8.RuneCountInString(fileName) > 255 {
+		return nil, ErrorIncorrectFileName
+	}
+
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		logging.FromContext(ctx).Error(err.Error())
+		return nil, errors.New("can not store attachment")
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(
+		io.MultiWriter(tmp, hash),
+		io.LimitReader(body, service.maxSize+1),
+	)
+	if err != nil {
+		return nil, errors.New("can not read attachment")
+	}
+	if size > service.maxSize {
+		return nil, ErrorAttachmentTooLarge
+	}
+	if size == 0 {
+		return nil, ErrorAttachmentEmpty
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, errors.New("can not store attachment")
+	}
+	mtype, err := mimetype.DetectReader(tmp)
+	if err != nil {
+		return nil, errors.New("can not detect attachment type")
+	}
+	if !mimetype.EqualsAny(mtype.String(), allowedContentTypes...) {
+		return nil, ErrorAttachmentTypeNotAllowed
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, errors.New("can not store attachment")
+	}
+
+	id := uuid.New()
+	attachment := &model.Attachment{
+		Id:          id,
+		ObjectType:  objectType,
+		ObjectId:    objectId,
+		FileName:    fileName,
+		ContentType: mtype.String(),
+		Size:        size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  "attachments/" + objectId.String() + "/" + id.String(),
+	}
+	err = service.storage.Put(
+		ctx,
+		attachment.StorageKey,
+		tmp,
+		attachment.Size,
+		attachment.ContentType,
+	)
+	if err != nil {
+		logging.FromContext(ctx).Error(err.Error())
+		return nil, errors.New("can not store attachment")
+	}
+	return attachment, nil
+}
+
+func (service *AttachmentService) discard(ctx context.Context, attachment *model.Attachment) {
+	err := service.storage.Delete(ctx, attachment.StorageKey)
+	if err != nil {
+		logging.FromContext(ctx).Error(err.Error())
+	}
+}
+
+func containsAttachment(attachments []*model.Attachment, id uuid.UUID) bool {
+	for _, attachment := range attachments {
+		if attachment.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func Setup(cfg config.Attachments, blobStorage storage.BlobStorage) {
+	current.Store(&settings{storage: blobStorage, maxSize: cfg.MaxSize})
+}
+
+func NewService() (service *AttachmentService, err error) {
+	tenderRepository, err := tender.NewRepo()
+	if err != nil {
+		return
+	}
+	bidRepository, err := bid.NewRepo()
+	if err != nil {
+		return
+	}
+	attachmentRepository, err := attachment.NewRepo()
+	if err != nil {
+		return
+	}
+	templateRepository, err := template.NewRepo()
+	if err != nil {
+		return
+	}
+	settings := current.Load()
+	if settings == nil {
+		err = ErrorStorageNotConfigured
+		return
+	}
+
+	service = &AttachmentService{
+		tenderRepo:     tenderRepository,
+		bidRepo:        bidRepository,
+		templateRepo:   templateRepository,
+		attachmentRepo: attachmentRepository,
+		storage:        settings.storage,
+		maxSize:        settings.maxSize,
+	}
+	return
+}
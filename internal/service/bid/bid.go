@@ -1,15 +1,25 @@
 package bid
 
 import (
+	"context"
 	"errors"
+	"slices"
+	"sync/atomic"
 
 	"github.com/google/uuid"
 
+	"avi/internal/config"
+	"avi/internal/database"
+	"avi/internal/metrics"
 	"avi/internal/model"
-	"avi/internal/repository/bid"
+	bidRepo "avi/internal/repository/bid"
+	"avi/internal/repository/invitation"
+	"avi/internal/repository/lot"
 	"avi/internal/repository/organization"
+	"avi/internal/repository/shortlist"
 	"avi/internal/repository/tender"
 	"avi/internal/repository/user"
+	"avi/internal/tracing"
 )
 
 var ErrorUserNotFound = errors.New("user does not exist")
@@ -17,59 +27,155 @@ var ErrorUserIsNotOrgResponsible = errors.New("user is not organization responsi
 var ErrorTenderNotFound = errors.New("tender does not exist")
 var ErrorOrgNotFound = errors.New("organization does not exist")
 var ErrorBidNotFound = errors.New("bid does not exist")
+var ErrorAuthorIsNotInvited = errors.New("bid author is not invited to tender")
+var ErrorLotNotFound = errors.New("lot does not exist")
+var ErrorLotIsNotOpen = errors.New("lot is not open")
+var ErrorIncorrectLots = errors.New("bid lots do not match tender lots")
+var ErrorAmountIsRequired = errors.New("bid amount is required by tender budget")
+var ErrorAmountExceedsBudget = errors.New("bid amount exceeds tender budget")
+var ErrorAuthorIsNotShortlisted = errors.New("bid author is not shortlisted")
+var ErrorBidIsNotCommercial = errors.New("decisions are allowed only on commercial bids")
+var ErrorAuthorNotFound = errors.New("bid author does not exist")
+var ErrorReviewNotFound = errors.New("review does not exist")
+var ErrorUserIsNotReviewer = errors.New("user is not review author")
+var ErrorUserIsNotBidAuthor = errors.New("user is not bid author")
+var ErrorIncorrectRating = errors.New("rating must be between 1 and 5")
+var ErrorIncorrectReviewTag = errors.New("not allowed review tag")
+var ErrorBidLimitExceeded = errors.New("bid limit per author for tender is exceeded")
+var ErrorConflictCheckFailed = errors.New("can not check conflict of interest")
+
+var maxBidsPerAuthor atomic.Int64
 
 type BidService struct {
-	tenderRepo *tender.TenderRepo
-	bidRepo    *bid.BidRepo
-	userRepo   *user.UserRepo
-	orgRepo    *organization.OrganizationRepo
+	tenderRepo     TenderRepository
+	bidRepo        BidRepository
+	userRepo       UserRepository
+	orgRepo        OrganizationRepository
+	invitationRepo InvitationRepository
+	lotRepo        LotRepository
+	shortlistRepo  ShortlistRepository
+
+	maxBidsPerAuthor int
 }
 
 func (service *BidService) CreateBid(
+	ctx context.Context,
 	name string,
 	description string,
 	tenderId uuid.UUID,
 	authorType model.BidAuthorType,
 	authorId uuid.UUID,
+	creatorUsername string,
+	lotIds []uuid.UUID,
+	amount *float64,
 ) (bid *model.Bid, err error) {
-	_, err = service.tenderRepo.GetTenderById(tenderId)
+	ctx, span := tracing.Start(ctx, "BidService.CreateBid")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
 	if err != nil {
 		return nil, ErrorTenderNotFound
 	}
 
+	creator, err := service.userRepo.GetUserByName(ctx, creatorUsername)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+
 	switch authorType {
 	case model.OrgBidAuthorType:
-		_, err = service.orgRepo.GetOrganizationById(authorId)
+		_, err = service.orgRepo.GetOrganizationById(ctx, authorId)
 		if err != nil {
 			return nil, ErrorOrgNotFound
 		}
+		usersId, err := service.orgRepo.GetResponsibleUsersId(ctx, authorId)
+		if err != nil || !slices.Contains(usersId, creator.Id) {
+			return nil, ErrorUserIsNotOrgResponsible
+		}
 	case model.UserBidAuthorType:
-		_, err = service.userRepo.GetUserById(authorId)
+		_, err = service.userRepo.GetUserById(ctx, authorId)
 		if err != nil {
 			return nil, ErrorUserNotFound
 		}
+		if authorId != creator.Id {
+			return nil, ErrorUserIsNotBidAuthor
+		}
 	default:
 		return nil, errors.New("not allowed author type")
 	}
+
+	err = service.checkBidderConflict(ctx, tender, authorType, authorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if tender.InviteOnly {
+		invited, err := service.invitationRepo.IsInvited(
+			ctx, tenderId, model.InviteeType(authorType), authorId,
+		)
+		if err != nil || !invited {
+			return nil, ErrorAuthorIsNotInvited
+		}
+	}
+
+	err = service.checkBidLots(ctx, tenderId, lotIds)
+	if err != nil {
+		return nil, err
+	}
+
+	stage := model.CommercialBidStage
+	switch tender.Status {
+	case model.TenderStatusPrequalification:
+		stage = model.PrequalificationBidStage
+	case model.TenderStatusCommercial:
+		shortlisted, err := service.shortlistRepo.IsShortlisted(
+			ctx, tenderId, authorType, authorId,
+		)
+		if err != nil || !shortlisted {
+			return nil, ErrorAuthorIsNotShortlisted
+		}
+	}
+
+	err = checkBidAmount(tender, stage, amount)
+	if err != nil {
+		return nil, err
+	}
+
 	bid, err = service.bidRepo.CreateBid(
-		name, description, tenderId, authorType, authorId,
+		ctx,
+		name,
+		description,
+		tenderId,
+		authorType,
+		authorId,
+		creator.Id,
+		lotIds,
+		amount,
+		stage,
+		service.maxBidsPerAuthor,
 	)
+	if errors.Is(err, bidRepo.ErrorBidLimitExceeded) {
+		return nil, ErrorBidLimitExceeded
+	}
 	if err != nil {
 		return nil, errors.New("can not create bid")
 	}
+	metrics.BidSubmitted()
 	return
 }
 
 func (service *BidService) GetBidsByUsername(
+	ctx context.Context,
 	offset int,
 	limit int,
 	username string,
 ) (bids []*model.Bid, err error) {
-	user, err := service.userRepo.GetUserByName(username)
+	ctx, span := tracing.Start(ctx, "BidService.GetBidsByUsername")
+	defer span.End()
+	user, err := service.userRepo.GetUserByName(ctx, username)
 	if err != nil {
 		return nil, ErrorUserNotFound
 	}
-	bids, err = service.bidRepo.GetBidsByAuthorId(offset, limit, user.Id)
+	bids, err = service.bidRepo.GetBidsByUserId(ctx, offset, limit, user.Id)
 	if err != nil {
 		return nil, errors.New("can not get bids")
 	}
@@ -77,23 +183,42 @@ func (service *BidService) GetBidsByUsername(
 }
 
 func (service *BidService) GetBidsByTenderId(
+	ctx context.Context,
 	offset int,
 	limit int,
 	tenderId uuid.UUID,
+	stage model.BidStage,
 ) (bids []*model.Bid, err error) {
-	_, err = service.tenderRepo.GetTenderById(tenderId)
+	ctx, span := tracing.Start(ctx, "BidService.GetBidsByTenderId")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
 	if err != nil {
 		return nil, ErrorTenderNotFound
 	}
-	bids, err = service.bidRepo.GetBidsByTenderId(offset, limit, tenderId)
+	if stage != "" &&
+		stage != model.PrequalificationBidStage &&
+		stage != model.CommercialBidStage {
+		return nil, errors.New("not allowed stage")
+	}
+	bids, err = service.bidRepo.GetBidsByTenderId(ctx, offset, limit, tenderId, stage)
 	if err != nil {
 		return nil, errors.New("can not get bids")
 	}
+
+	if tender.Reserve != nil {
+		for _, bid := range bids {
+			bid.ExceedsReserve = bid.Amount != nil && *bid.Amount > *tender.Reserve
+		}
+	}
 	return
 }
 
-func (service *BidService) GetBidById(id uuid.UUID) (bid *model.Bid, err error) {
-	bid, err = service.bidRepo.GetBidById(id)
+func (service *BidService) GetBidById(
+	ctx context.Context, id uuid.UUID,
+) (bid *model.Bid, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.GetBidById")
+	defer span.End()
+	bid, err = service.bidRepo.GetBidById(ctx, id)
 	if err != nil {
 		return nil, ErrorBidNotFound
 	}
@@ -101,8 +226,10 @@ func (service *BidService) GetBidById(id uuid.UUID) (bid *model.Bid, err error)
 }
 
 func (service *BidService) UpdateBidStatusById(
-	id uuid.UUID, status model.BidStatus,
+	ctx context.Context, id uuid.UUID, status model.BidStatus,
 ) (bid *model.Bid, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.UpdateBidStatusById")
+	defer span.End()
 	if status != model.CreatedBidStatus &&
 		status != model.PublishedBidStatus &&
 		status != model.CanceledBidStatus {
@@ -110,11 +237,11 @@ func (service *BidService) UpdateBidStatusById(
 		return
 	}
 
-	_, err = service.bidRepo.GetBidById(id)
+	_, err = service.bidRepo.GetBidById(ctx, id)
 	if err != nil {
 		return nil, ErrorBidNotFound
 	}
-	bid, err = service.bidRepo.UpdateBidStatusById(id, status)
+	bid, err = service.bidRepo.UpdateBidStatusById(ctx, id, status)
 	if err != nil {
 		return nil, errors.New("can not update bid")
 	}
@@ -122,13 +249,29 @@ func (service *BidService) UpdateBidStatusById(
 }
 
 func (service *BidService) EditBidById(
-	id uuid.UUID, name string, description string,
+	ctx context.Context, id uuid.UUID, name string, description string, amount *float64,
 ) (bid *model.Bid, err error) {
-	bid, err = service.bidRepo.GetBidById(id)
+	ctx, span := tracing.Start(ctx, "BidService.EditBidById")
+	defer span.End()
+	bid, err = service.bidRepo.GetBidById(ctx, id)
 	if err != nil {
 		return nil, ErrorBidNotFound
 	}
 
+	if amount == nil {
+		amount = bid.Amount
+	}
+
+	tender, err := service.tenderRepo.GetTenderById(ctx, bid.TenderId)
+	if err != nil {
+		return nil, ErrorTenderNotFound
+	}
+
+	err = checkBidAmount(tender, bid.Stage, amount)
+	if err != nil {
+		return nil, err
+	}
+
 	if name == "" {
 		name = bid.Name
 	}
@@ -137,17 +280,22 @@ func (service *BidService) EditBidById(
 		description = bid.Description
 	}
 
-	bid, err = service.bidRepo.EditBidById(id, name, description)
+	bid, err = service.bidRepo.EditBidById(ctx, id, name, description, amount)
 	if err != nil {
+		if interrupted := database.Interrupted(err); interrupted != nil {
+			return nil, interrupted
+		}
 		return nil, errors.New("can not edit bid")
 	}
 	return
 }
 
 func (service *BidService) SubmitDecisionById(
-	id uuid.UUID, decision string,
+	ctx context.Context, id uuid.UUID, username string, decision string, lotId uuid.NullUUID,
 ) (bid *model.Bid, err error) {
-	bid, err = service.bidRepo.GetBidById(id)
+	ctx, span := tracing.Start(ctx, "BidService.SubmitDecisionById")
+	defer span.End()
+	bid, err = service.bidRepo.GetBidById(ctx, id)
 	if err != nil {
 		return nil, ErrorBidNotFound
 	}
@@ -157,22 +305,49 @@ func (service *BidService) SubmitDecisionById(
 		return
 	}
 
+	if bid.Stage != model.CommercialBidStage {
+		return nil, ErrorBidIsNotCommercial
+	}
+
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+
+	err = service.checkReviewerConflict(ctx, bid, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
 	var orgId uuid.UUID
 	if bid.AuthorType == model.OrgBidAuthorType {
 		orgId = bid.AuthorId
 	} else {
-		orgs, err := service.orgRepo.GetOrganizationsByUserId(bid.AuthorId)
-		if err != nil || orgs != nil && len(orgs) == 0 {
+		orgs, err := service.orgRepo.GetOrganizationsByUserId(ctx, bid.AuthorId)
+		if err != nil || len(orgs) == 0 {
 			return nil, ErrorUserIsNotOrgResponsible
 		}
 		orgId = orgs[0].Id
 	}
-	usersId, err := service.orgRepo.GetResponsibleUsersId(orgId)
+	usersId, err := service.orgRepo.GetResponsibleUsersId(ctx, orgId)
 	if err != nil {
 		return nil, ErrorUserIsNotOrgResponsible
 	}
 
-	rejects, approves, err := service.bidRepo.GetDisicions(id)
+	if len(bid.LotIds) > 0 || lotId.Valid {
+		err = service.submitLotDecision(ctx, bid, lotId, decision, len(usersId))
+		return
+	}
+
+	lots, err := service.lotRepo.GetLotsByTenderId(ctx, bid.TenderId)
+	if err != nil {
+		return nil, errors.New("can not get tender lots")
+	}
+	if len(lots) > 0 {
+		return nil, ErrorIncorrectLots
+	}
+
+	rejects, approves, err := service.bidRepo.GetDisicions(ctx, id)
 	if err != nil {
 		return nil, ErrorBidNotFound
 	}
@@ -189,64 +364,428 @@ func (service *BidService) SubmitDecisionById(
 	if decision == "Approved" {
 		approves += 1
 		closeTender := approves >= quorum
-		err = service.bidRepo.UpdateApproves(id, approves, bid.TenderId, closeTender)
+		err = service.bidRepo.UpdateApproves(ctx, id, approves, bid.TenderId, closeTender)
 		if err != nil {
 			return nil, errors.New("can not approve bid")
 		}
+		if closeTender {
+			metrics.TenderStatusChanged(string(model.TenderStatusClosed))
+		}
 	} else {
 		rejects += 1
-		err = service.bidRepo.UpdateRejects(id, rejects)
+		err = service.bidRepo.UpdateRejects(ctx, id, rejects)
 		if err != nil {
 			return nil, errors.New("can not reject bid")
 		}
 	}
+	metrics.BidDecision(decision)
 
 	return
 }
 
+func (service *BidService) submitLotDecision(
+	ctx context.Context, bid *model.Bid, lotId uuid.NullUUID, decision string, responsibles int,
+) error {
+	if !lotId.Valid || !slices.Contains(bid.LotIds, lotId.UUID) {
+		return ErrorLotNotFound
+	}
+
+	lot, err := service.lotRepo.GetLotById(ctx, lotId.UUID)
+	if err != nil {
+		return ErrorLotNotFound
+	}
+	if lot.Status != model.OpenLotStatus {
+		return ErrorLotIsNotOpen
+	}
+
+	rejects, approves, err := service.lotRepo.GetDecisions(ctx, bid.Id, lot.Id)
+	if err != nil {
+		return ErrorLotNotFound
+	}
+
+	if rejects > 0 {
+		return errors.New("bid is already rejected for lot")
+	}
+
+	quorum := min(3, responsibles)
+	if approves > quorum {
+		return errors.New("bid is already approved for lot")
+	}
+
+	if decision == "Approved" {
+		approves += 1
+		award := approves >= quorum
+		err = service.bidRepo.UpdateLotApproves(
+			ctx, bid.Id, lot.Id, approves, bid.TenderId, award,
+		)
+		if err != nil {
+			return errors.New("can not approve bid for lot")
+		}
+		if award {
+			tender, err := service.tenderRepo.GetTenderById(ctx, bid.TenderId)
+			if err == nil && tender.Status == model.TenderStatusClosed {
+				metrics.TenderStatusChanged(string(model.TenderStatusClosed))
+			}
+		}
+	} else {
+		rejects += 1
+		err = service.lotRepo.UpdateRejects(ctx, bid.Id, lot.Id, rejects)
+		if err != nil {
+			return errors.New("can not reject bid for lot")
+		}
+	}
+	metrics.BidDecision(decision)
+	return nil
+}
+
+func (service *BidService) checkBidLots(
+	ctx context.Context, tenderId uuid.UUID, lotIds []uuid.UUID,
+) error {
+	lots, err := service.lotRepo.GetLotsByTenderId(ctx, tenderId)
+	if err != nil {
+		return errors.New("can not get tender lots")
+	}
+	if len(lots) == 0 {
+		if len(lotIds) > 0 {
+			return ErrorIncorrectLots
+		}
+		return nil
+	}
+	if len(lotIds) == 0 {
+		return ErrorIncorrectLots
+	}
+
+	for i, lotId := range lotIds {
+		if slices.Contains(lotIds[:i], lotId) {
+			return ErrorIncorrectLots
+		}
+		idx := slices.IndexFunc(lots, func(lot *model.Lot) bool {
+			return lot.Id == lotId
+		})
+		if idx < 0 {
+			return ErrorLotNotFound
+		}
+		if lots[idx].Status != model.OpenLotStatus {
+			return ErrorLotIsNotOpen
+		}
+	}
+	return nil
+}
+
+func checkBidAmount(
+	tender *model.Tender, stage model.BidStage, amount *float64,
+) error {
+	if stage != model.CommercialBidStage ||
+		!tender.StrictBudget || tender.Budget == nil {
+		return nil
+	}
+	if amount == nil {
+		return ErrorAmountIsRequired
+	}
+	if *amount > *tender.Budget {
+		return ErrorAmountExceedsBudget
+	}
+	return nil
+}
+
 func (service *BidService) CreateReviewById(
-	id uuid.UUID, description string,
+	ctx context.Context,
+	id uuid.UUID,
+	username string,
+	description string,
+	rating *int,
+	tags []model.ReviewTag,
 ) (bid *model.Bid, err error) {
-	_, err = service.bidRepo.GetBidById(id)
+	ctx, span := tracing.Start(ctx, "BidService.CreateReviewById")
+	defer span.End()
+	bid, err = service.bidRepo.GetBidById(ctx, id)
 	if err != nil {
 		return nil, ErrorBidNotFound
 	}
-	bid, err = service.bidRepo.CreateReviewById(id, description)
+
+	err = validateReview(rating, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.CheckRWRightsByUsername(ctx, bid.TenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	reviewer, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+
+	err = service.checkReviewerConflict(ctx, bid, reviewer.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err = service.bidRepo.CreateReviewById(
+		ctx, id, reviewer.Id, description, rating, tags,
+	)
 	if err != nil {
 		return nil, errors.New("can not create review")
 	}
 	return
 }
 
+func (service *BidService) EditReview(
+	ctx context.Context,
+	bidId uuid.UUID,
+	reviewId uuid.UUID,
+	username string,
+	description string,
+	rating *int,
+	tags []model.ReviewTag,
+) (review *model.Review, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.EditReview")
+	defer span.End()
+	review, err = service.getReviewByReviewer(ctx, bidId, reviewId, username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateReview(rating, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if description != "" {
+		review.Description = description
+	}
+	if rating != nil {
+		review.Rating = rating
+	}
+	if tags != nil {
+		review.Tags = tags
+	}
+
+	review, err = service.bidRepo.EditReview(ctx, review)
+	if err != nil {
+		return nil, errors.New("can not edit review")
+	}
+	return
+}
+
+func (service *BidService) DeleteReview(
+	ctx context.Context, bidId uuid.UUID, reviewId uuid.UUID, username string,
+) error {
+	ctx, span := tracing.Start(ctx, "BidService.DeleteReview")
+	defer span.End()
+	_, err := service.getReviewByReviewer(ctx, bidId, reviewId, username)
+	if err != nil {
+		return err
+	}
+
+	err = service.bidRepo.DeleteReview(ctx, reviewId)
+	if err != nil {
+		return errors.New("can not delete review")
+	}
+	return nil
+}
+
+func (service *BidService) ReplyToReview(
+	ctx context.Context, bidId uuid.UUID, reviewId uuid.UUID, username string, text string,
+) (reply *model.ReviewReply, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.ReplyToReview")
+	defer span.End()
+	bid, err := service.bidRepo.GetBidById(ctx, bidId)
+	if err != nil {
+		return nil, ErrorBidNotFound
+	}
+
+	review, err := service.bidRepo.GetReviewById(ctx, reviewId)
+	if err != nil || review.BidId != bidId {
+		return nil, ErrorReviewNotFound
+	}
+
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+
+	err = service.checkBidAuthor(ctx, bid, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err = service.bidRepo.CreateReply(ctx, reviewId, user.Id, text)
+	if err != nil {
+		return nil, errors.New("can not create reply")
+	}
+	return
+}
+
+func (service *BidService) getReviewByReviewer(
+	ctx context.Context, bidId uuid.UUID, reviewId uuid.UUID, username string,
+) (review *model.Review, err error) {
+	_, err = service.bidRepo.GetBidById(ctx, bidId)
+	if err != nil {
+		return nil, ErrorBidNotFound
+	}
+
+	review, err = service.bidRepo.GetReviewById(ctx, reviewId)
+	if err != nil || review.BidId != bidId {
+		return nil, ErrorReviewNotFound
+	}
+
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+
+	if review.ReviewerId == nil || *review.ReviewerId != user.Id {
+		return nil, ErrorUserIsNotReviewer
+	}
+	return
+}
+
+func (service *BidService) checkBidAuthor(
+	ctx context.Context, bid *model.Bid, userId uuid.UUID,
+) error {
+	if bid.AuthorType == model.UserBidAuthorType {
+		if bid.AuthorId == userId {
+			return nil
+		}
+		return ErrorUserIsNotBidAuthor
+	}
+
+	usersId, err := service.orgRepo.GetResponsibleUsersId(ctx, bid.AuthorId)
+	if err != nil {
+		return ErrorUserIsNotBidAuthor
+	}
+	for _, id := range usersId {
+		if id == userId {
+			return nil
+		}
+	}
+	return ErrorUserIsNotBidAuthor
+}
+
+func validateReview(rating *int, tags []model.ReviewTag) error {
+	if rating != nil && (*rating < 1 || *rating > 5) {
+		return ErrorIncorrectRating
+	}
+	for _, tag := range tags {
+		if !slices.Contains(model.ReviewTags, tag) {
+			return ErrorIncorrectReviewTag
+		}
+	}
+	return nil
+}
+
 func (service *BidService) RollbackById(
-	id uuid.UUID, version int32,
+	ctx context.Context, id uuid.UUID, version int32,
 ) (bid *model.Bid, err error) {
-	_, err = service.bidRepo.GetBidById(id)
+	ctx, span := tracing.Start(ctx, "BidService.RollbackById")
+	defer span.End()
+	current, err := service.bidRepo.GetBidById(ctx, id)
 	if err != nil {
 		return nil, ErrorBidNotFound
 	}
-	bid, err = service.bidRepo.RollbackById(id, version)
+
+	history, err := service.bidRepo.GetBidHistory(ctx, id)
+	if err != nil {
+		return nil, errors.New("can not get bid history")
+	}
+	idx := slices.IndexFunc(history, func(bid *model.Bid) bool {
+		return bid.Version == version
+	})
+	if idx >= 0 {
+		tender, err := service.tenderRepo.GetTenderById(ctx, current.TenderId)
+		if err != nil {
+			return nil, ErrorTenderNotFound
+		}
+		err = checkBidAmount(tender, current.Stage, history[idx].Amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bid, err = service.bidRepo.RollbackById(ctx, id, version)
 	if err != nil {
 		return nil, errors.New("can not rollback bid")
 	}
+	metrics.Rollback("bid")
+	return
+}
+
+func (service *BidService) GetBidHistory(
+	ctx context.Context, id uuid.UUID,
+) (bids []*model.Bid, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.GetBidHistory")
+	defer span.End()
+	bid, err := service.bidRepo.GetBidById(ctx, id)
+	if err != nil {
+		return nil, ErrorBidNotFound
+	}
+	bids, err = service.bidRepo.GetBidHistory(ctx, id)
+	if err != nil {
+		return nil, errors.New("can not get bid history")
+	}
+	bids = append(bids, bid)
 	return
 }
 
 func (service *BidService) GetReviews(
+	ctx context.Context,
 	offset int,
 	limit int,
 	authorUsername string,
 	tender_id uuid.UUID,
 ) (reviews []*model.Review, err error) {
-	_, err = service.tenderRepo.GetTenderById(tender_id)
+	ctx, span := tracing.Start(ctx, "BidService.GetReviews")
+	defer span.End()
+	_, err = service.tenderRepo.GetTenderById(ctx, tender_id)
 	if err != nil {
 		return nil, ErrorTenderNotFound
 	}
-	author, err := service.userRepo.GetUserByName(authorUsername)
+	author, err := service.userRepo.GetUserByName(ctx, authorUsername)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+	reviews, err = service.bidRepo.GetReviews(ctx, offset, limit, author.Id, tender_id)
+	if err != nil {
+		return nil, errors.New("can not get reviews")
+	}
+	return
+}
+
+func (service *BidService) GetReputation(
+	ctx context.Context,
+	authorType model.BidAuthorType,
+	authorId uuid.UUID,
+	username string,
+	reviewsLimit int,
+) (reputation *model.Reputation, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.GetReputation")
+	defer span.End()
+	_, err = service.userRepo.GetUserByName(ctx, username)
 	if err != nil {
 		return nil, ErrorUserNotFound
 	}
-	reviews, err = service.bidRepo.GetReviews(offset, limit, author.Id, tender_id)
+
+	switch authorType {
+	case model.OrgBidAuthorType:
+		_, err = service.orgRepo.GetOrganizationById(ctx, authorId)
+	case model.UserBidAuthorType:
+		_, err = service.userRepo.GetUserById(ctx, authorId)
+	default:
+		return nil, errors.New("not allowed author type")
+	}
+	if err != nil {
+		return nil, ErrorAuthorNotFound
+	}
+
+	reputation, err = service.bidRepo.GetReputation(ctx, authorType, authorId)
+	if err != nil {
+		return nil, errors.New("can not get reputation")
+	}
+	reputation.RecentReviews, err = service.bidRepo.GetRecentReviews(
+		ctx, authorType, authorId, reviewsLimit,
+	)
 	if err != nil {
 		return nil, errors.New("can not get reviews")
 	}
@@ -254,9 +793,11 @@ func (service *BidService) GetReviews(
 }
 
 func (service *BidService) CheckRWRightsByUsername(
-	tenderId uuid.UUID, username string,
+	ctx context.Context, tenderId uuid.UUID, username string,
 ) error {
-	tender, err := service.tenderRepo.GetTenderById(tenderId)
+	ctx, span := tracing.Start(ctx, "BidService.CheckRWRightsByUsername")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
 	if err != nil {
 		err = ErrorTenderNotFound
 		return err
@@ -266,14 +807,14 @@ func (service *BidService) CheckRWRightsByUsername(
 		return ErrorUserIsNotOrgResponsible
 	}
 
-	user, err := service.userRepo.GetUserByName(username)
+	user, err := service.userRepo.GetUserByName(ctx, username)
 	if err != nil {
 		err = ErrorUserNotFound
 		return err
 	}
 
 	usersId, err := service.orgRepo.GetResponsibleUsersId(
-		tender.OrganizationId,
+		ctx, tender.OrganizationId,
 	)
 
 	if err != nil {
@@ -289,12 +830,55 @@ func (service *BidService) CheckRWRightsByUsername(
 	return ErrorUserIsNotOrgResponsible
 }
 
+func (service *BidService) CheckAuthorRightByUsername(
+	ctx context.Context, bidId uuid.UUID, username string,
+) error {
+	ctx, span := tracing.Start(ctx, "BidService.CheckAuthorRightByUsername")
+	defer span.End()
+	bid, err := service.bidRepo.GetBidById(ctx, bidId)
+	if err != nil {
+		return ErrorBidNotFound
+	}
+
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return ErrorUserNotFound
+	}
+	return service.checkBidAuthor(ctx, bid, user.Id)
+}
+
+func (service *BidService) CheckReadRightByUsername(
+	ctx context.Context, bidId uuid.UUID, username string,
+) error {
+	ctx, span := tracing.Start(ctx, "BidService.CheckReadRightByUsername")
+	defer span.End()
+	bid, err := service.bidRepo.GetBidById(ctx, bidId)
+	if err != nil {
+		return ErrorBidNotFound
+	}
+
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return ErrorUserNotFound
+	}
+
+	err = service.checkBidAuthor(ctx, bid, user.Id)
+	if err == nil {
+		return nil
+	}
+	err = service.CheckRWRightsByUsername(ctx, bid.TenderId, username)
+	if errors.Is(err, ErrorUserIsNotOrgResponsible) {
+		return ErrorUserIsNotBidAuthor
+	}
+	return err
+}
+
 func NewService() (service *BidService, err error) {
 	tenderRerository, err := tender.NewRepo()
 	if err != nil {
 		return
 	}
-	bidRepository, err := bid.NewRepo()
+	bidRepository, err := bidRepo.NewRepo()
 	if err != nil {
 		return
 	}
@@ -306,12 +890,44 @@ func NewService() (service *BidService, err error) {
 	if err != nil {
 		return
 	}
-
-	service = &BidService{
-		tenderRepo: tenderRerository,
-		bidRepo:    bidRepository,
-		userRepo:   userRepository,
-		orgRepo:    organizationRepository,
+	invitationRepository, err := invitation.NewRepo()
+	if err != nil {
+		return
+	}
+	lotRepository, err := lot.NewRepo()
+	if err != nil {
+		return
+	}
+	shortlistRepository, err := shortlist.NewRepo()
+	if err != nil {
+		return
 	}
+
+	service = NewServiceWithRepositories(Repositories{
+		Tender:       tenderRerository,
+		Bid:          bidRepository,
+		User:         userRepository,
+		Organization: organizationRepository,
+		Invitation:   invitationRepository,
+		Lot:          lotRepository,
+		Shortlist:    shortlistRepository,
+	})
 	return
 }
+
+func Setup(cfg config.RateLimit) {
+	maxBidsPerAuthor.Store(int64(cfg.MaxBidsPerAuthor))
+}
+
+func NewServiceWithRepositories(repos Repositories) *BidService {
+	return &BidService{
+		maxBidsPerAuthor: int(maxBidsPerAuthor.Load()),
+		tenderRepo:       repos.Tender,
+		bidRepo:          repos.Bid,
+		userRepo:         repos.User,
+		orgRepo:          repos.Organization,
+		invitationRepo:   repos.Invitation,
+		lotRepo:          repos.Lot,
+		shortlistRepo:    repos.Shortlist,
+	}
+}
@@ -0,0 +1,378 @@
+package bid_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"avi/internal/config"
+	"avi/internal/model"
+	"avi/internal/repository/memory"
+	"avi/internal/service/bid"
+)
+
+var (
+	_ bid.TenderRepository       = (*memory.TenderRepo)(nil)
+	_ bid.BidRepository          = (*memory.BidRepo)(nil)
+	_ bid.UserRepository         = (*memory.UserRepo)(nil)
+	_ bid.OrganizationRepository = (*memory.OrganizationRepo)(nil)
+	_ bid.InvitationRepository   = (*memory.InvitationRepo)(nil)
+	_ bid.LotRepository          = (*memory.LotRepo)(nil)
+	_ bid.ShortlistRepository    = (*memory.ShortlistRepo)(nil)
+)
+
+type fixture struct {
+	store     *memory.Store
+	service   *bid.BidService
+	owner     model.User
+	bidder    model.User
+	reviewers []model.User
+	tender    *model.Tender
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	store := memory.NewStore()
+	f := &fixture{
+		store: store,
+		service: bid.NewServiceWithRepositories(bid.Repositories{
+			Tender:       store.Tenders(),
+			Bid:          store.Bids(),
+			User:         store.Users(),
+			Organization: store.Organizations(),
+			Invitation:   store.Invitations(),
+			Lot:          store.Lots(),
+			Shortlist:    store.Shortlists(),
+		}),
+		owner:  store.AddUser(model.User{Username: "owner"}),
+		bidder: store.AddUser(model.User{Username: "bidder"}),
+	}
+	for _, name := range []string{"reviewer1", "reviewer2"} {
+		f.reviewers = append(f.reviewers, store.AddUser(model.User{Username: name}))
+	}
+
+	org := store.AddOrganization(model.Organization{Name: "customer"}, f.owner.Id)
+	store.AddOrganization(
+		model.Organization{Name: "supplier"},
+		f.bidder.Id, store.AddUser(model.User{Username: "partner"}).Id,
+	)
+
+	tender, err := store.Tenders().CreateTender(context.Background(), &model.Tender{
+		Name:           "tender",
+		ServiceType:    "Delivery",
+		OrganizationId: org.Id,
+	}, f.owner.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.tender = tender
+	return f
+}
+
+func (f *fixture) createBid(t *testing.T, name string, lotIds ...uuid.UUID) *model.Bid {
+	t.Helper()
+
+	b, err := f.service.CreateBid(
+		context.Background(), name, "description", f.tender.Id,
+		model.UserBidAuthorType, f.bidder.Id, f.bidder.Username, lotIds, nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func (f *fixture) tenderStatus(t *testing.T) model.TenderStatus {
+	t.Helper()
+
+	tender, err := f.store.Tenders().GetTenderById(context.Background(), f.tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tender.Status
+}
+
+func TestCreateBidConflictOfInterest(t *testing.T) {
+	f := newFixture(t)
+
+	_, err := f.service.CreateBid(
+		context.Background(), "own bid", "description", f.tender.Id,
+		model.UserBidAuthorType, f.owner.Id, f.owner.Username, nil, nil,
+	)
+	var conflictErr *bid.ConflictOfInterestError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("got %v, want ConflictOfInterestError", err)
+	}
+}
+
+func TestCreateBidLimitPerAuthor(t *testing.T) {
+	bid.Setup(config.RateLimit{MaxBidsPerAuthor: 1})
+	t.Cleanup(func() { bid.Setup(config.RateLimit{}) })
+	f := newFixture(t)
+	ctx := context.Background()
+	first := f.createBid(t, "first")
+
+	_, err := f.service.CreateBid(
+		ctx, "second", "description", f.tender.Id,
+		model.UserBidAuthorType, f.bidder.Id, f.bidder.Username, nil, nil,
+	)
+	if !errors.Is(err, bid.ErrorBidLimitExceeded) {
+		t.Fatalf("got %v, want ErrorBidLimitExceeded", err)
+	}
+
+	_, err = f.service.UpdateBidStatusById(ctx, first.Id, model.CanceledBidStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.createBid(t, "second")
+}
+
+func TestBidAttachmentRights(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	b := f.createBid(t, "bid")
+	f.store.AddUser(model.User{Username: "stranger"})
+
+	tests := []struct {
+		username string
+		write    error
+		read     error
+	}{
+		{f.bidder.Username, nil, nil},
+		{f.owner.Username, bid.ErrorUserIsNotBidAuthor, nil},
+		{"stranger", bid.ErrorUserIsNotBidAuthor, bid.ErrorUserIsNotBidAuthor},
+		{"missing", bid.ErrorUserNotFound, bid.ErrorUserNotFound},
+	}
+	for _, test := range tests {
+		err := f.service.CheckAuthorRightByUsername(ctx, b.Id, test.username)
+		if !errors.Is(err, test.write) {
+			t.Errorf("%s write: got %v, want %v", test.username, err, test.write)
+		}
+		err = f.service.CheckReadRightByUsername(ctx, b.Id, test.username)
+		if !errors.Is(err, test.read) {
+			t.Errorf("%s read: got %v, want %v", test.username, err, test.read)
+		}
+	}
+}
+
+func TestSubmitDecisionQuorumClosesTender(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	b := f.createBid(t, "bid")
+
+	_, err := f.service.SubmitDecisionById(
+		ctx, b.Id, f.reviewers[0].Username, "Approved", uuid.NullUUID{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := f.tenderStatus(t); status == model.TenderStatusClosed {
+		t.Fatal("tender is closed before quorum")
+	}
+
+	_, err = f.service.SubmitDecisionById(
+		ctx, b.Id, f.reviewers[1].Username, "Approved", uuid.NullUUID{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := f.tenderStatus(t); status != model.TenderStatusClosed {
+		t.Fatalf("tender status %s after quorum, want Closed", status)
+	}
+
+	_, approves, err := f.store.Bids().GetDisicions(ctx, b.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approves != 2 {
+		t.Errorf("approves %d, want 2", approves)
+	}
+}
+
+func TestSubmitDecisionRejectsBidAuthor(t *testing.T) {
+	f := newFixture(t)
+	b := f.createBid(t, "bid")
+
+	_, err := f.service.SubmitDecisionById(
+		context.Background(), b.Id, f.bidder.Username, "Approved", uuid.NullUUID{},
+	)
+	var conflictErr *bid.ConflictOfInterestError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("got %v, want ConflictOfInterestError", err)
+	}
+}
+
+func TestSubmitDecisionOnBidOfUserWithoutOrganization(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	freelancer := f.store.AddUser(model.User{Username: "freelancer"})
+	b, err := f.service.CreateBid(
+		ctx, "freelance bid", "description", f.tender.Id,
+		model.UserBidAuthorType, freelancer.Id, freelancer.Username, nil, nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = f.service.SubmitDecisionById(
+		ctx, b.Id, f.reviewers[0].Username, "Approved", uuid.NullUUID{},
+	)
+	if !errors.Is(err, bid.ErrorUserIsNotOrgResponsible) {
+		t.Fatalf("got %v, want ErrorUserIsNotOrgResponsible", err)
+	}
+}
+
+func TestSubmitDecisionRollsBackOnRepositoryError(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	b := f.createBid(t, "bid")
+
+	_, err := f.service.SubmitDecisionById(
+		ctx, b.Id, f.reviewers[0].Username, "Approved", uuid.NullUUID{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.store.Fail("UpdateApproves", errors.New("connection reset"))
+	_, err = f.service.SubmitDecisionById(
+		ctx, b.Id, f.reviewers[1].Username, "Approved", uuid.NullUUID{},
+	)
+	if err == nil {
+		t.Fatal("expected repository error")
+	}
+
+	_, approves, err := f.store.Bids().GetDisicions(ctx, b.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approves != 1 {
+		t.Errorf("approves %d after failed decision, want 1", approves)
+	}
+	if status := f.tenderStatus(t); status == model.TenderStatusClosed {
+		t.Error("tender is closed after failed decision")
+	}
+}
+
+func TestSubmitLotDecisionClosesTenderWithNewVersion(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	lot := f.store.AddLot(model.Lot{TenderId: f.tender.Id, Name: "lot", Quantity: 1})
+	b := f.createBid(t, "bid", lot.Id)
+	lotId := uuid.NullUUID{UUID: lot.Id, Valid: true}
+
+	for _, reviewer := range f.reviewers {
+		_, err := f.service.SubmitDecisionById(ctx, b.Id, reviewer.Username, "Approved", lotId)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	awarded, err := f.store.Lots().GetLotById(ctx, lot.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if awarded.Status != model.AwardedLotStatus {
+		t.Errorf("lot status %s, want Awarded", awarded.Status)
+	}
+
+	tender, err := f.store.Tenders().GetTenderById(ctx, f.tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tender.Status != model.TenderStatusClosed || tender.Version != f.tender.Version+1 {
+		t.Errorf("tender %s version %d, want Closed version %d",
+			tender.Status, tender.Version, f.tender.Version+1)
+	}
+	history, err := f.store.Tenders().GetTenderHistory(ctx, f.tender.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Status != f.tender.Status {
+		t.Errorf("tender history %v, want previous version", history)
+	}
+}
+
+func TestSubmitDecisionRejectsLotlessBidOnTenderWithLots(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	b := f.createBid(t, "bid")
+	f.store.AddLot(model.Lot{TenderId: f.tender.Id, Name: "lot", Quantity: 1})
+
+	for _, reviewer := range f.reviewers {
+		_, err := f.service.SubmitDecisionById(ctx, b.Id, reviewer.Username, "Approved", uuid.NullUUID{})
+		if !errors.Is(err, bid.ErrorIncorrectLots) {
+			t.Fatalf("got %v, want ErrorIncorrectLots", err)
+		}
+	}
+	if status := f.tenderStatus(t); status == model.TenderStatusClosed {
+		t.Fatal("tender is closed while lots are open")
+	}
+}
+
+func TestRollbackBid(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	b := f.createBid(t, "bid")
+
+	_, err := f.service.EditBidById(ctx, b.Id, "edited", "edited description", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rolledBack, err := f.service.RollbackById(ctx, b.Id, b.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rolledBack.Name != b.Name || rolledBack.Version != b.Version+2 {
+		t.Errorf("rollback: %s version %d, want %s version %d",
+			rolledBack.Name, rolledBack.Version, b.Name, b.Version+2)
+	}
+
+	_, err = f.service.RollbackById(ctx, b.Id, 42)
+	if err == nil {
+		t.Error("rollback to missing version succeeded")
+	}
+}
+
+func TestRollbackBidChecksStrictBudget(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	amount, lowered := 500.0, 100.0
+	b, err := f.service.CreateBid(
+		ctx, "bid", "description", f.tender.Id,
+		model.UserBidAuthorType, f.bidder.Id, f.bidder.Username, nil, &amount,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.service.EditBidById(ctx, b.Id, "", "", &lowered)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	budget := 200.0
+	strict := *f.tender
+	strict.Budget = &budget
+	strict.StrictBudget = true
+	_, err = f.store.Tenders().UpdateTender(ctx, &strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = f.service.RollbackById(ctx, b.Id, b.Version)
+	if !errors.Is(err, bid.ErrorAmountExceedsBudget) {
+		t.Fatalf("got %v, want ErrorAmountExceedsBudget", err)
+	}
+	current, err := f.service.GetBidById(ctx, b.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Amount == nil || *current.Amount != lowered || current.Version != b.Version+1 {
+		t.Errorf("bid amount %v version %d after rejected rollback, want %v version %d",
+			current.Amount, current.Version, lowered, b.Version+1)
+	}
+}
@@ -0,0 +1,96 @@
+package bid
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type ConflictOfInterestError struct {
+	Reason string
+}
+
+func (err *ConflictOfInterestError) Error() string {
+	return "conflict of interest: " + err.Reason
+}
+
+func (service *BidService) checkBidderConflict(
+	ctx context.Context, tender *model.Tender, authorType model.BidAuthorType, authorId uuid.UUID,
+) error {
+	if authorType == model.OrgBidAuthorType {
+		if authorId == tender.OrganizationId {
+			return &ConflictOfInterestError{
+				Reason: "organization can not bid on its own tender",
+			}
+		}
+		return nil
+	}
+
+	orgsId, err := service.userOrganizationsId(ctx, authorId)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(orgsId, tender.OrganizationId) {
+		return &ConflictOfInterestError{
+			Reason: "member of tender organization can not bid on its tender",
+		}
+	}
+	return nil
+}
+
+func (service *BidService) checkReviewerConflict(
+	ctx context.Context, bid *model.Bid, userId uuid.UUID,
+) error {
+	if bid.AuthorType == model.OrgBidAuthorType {
+		usersId, err := service.orgRepo.GetResponsibleUsersId(ctx, bid.AuthorId)
+		if err != nil {
+			return ErrorConflictCheckFailed
+		}
+		if slices.Contains(usersId, userId) {
+			return &ConflictOfInterestError{
+				Reason: "member of bid organization can not evaluate its bid",
+			}
+		}
+		return nil
+	}
+
+	if bid.AuthorId == userId {
+		return &ConflictOfInterestError{
+			Reason: "bid author can not evaluate own bid",
+		}
+	}
+
+	authorOrgsId, err := service.userOrganizationsId(ctx, bid.AuthorId)
+	if err != nil {
+		return err
+	}
+	userOrgsId, err := service.userOrganizationsId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, orgId := range userOrgsId {
+		if slices.Contains(authorOrgsId, orgId) {
+			return &ConflictOfInterestError{
+				Reason: "co-member of bid author can not evaluate the bid",
+			}
+		}
+	}
+	return nil
+}
+
+func (service *BidService) userOrganizationsId(
+	ctx context.Context, userId uuid.UUID,
+) ([]uuid.UUID, error) {
+	orgs, err := service.orgRepo.GetOrganizationsByUserId(ctx, userId)
+	if err != nil {
+		return nil, ErrorConflictCheckFailed
+	}
+	orgsId := make([]uuid.UUID, 0, len(orgs))
+	for _, org := range orgs {
+		orgsId = append(orgsId, org.Id)
+	}
+	return orgsId, nil
+}
@@ -0,0 +1,118 @@
+package bid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type TenderRepository interface {
+	GetTenderById(ctx context.Context, id uuid.UUID) (*model.Tender, error)
+}
+
+type BidRepository interface {
+	CreateBid(
+		ctx context.Context,
+		name string,
+		description string,
+		tenderId uuid.UUID,
+		authorType model.BidAuthorType,
+		authorId uuid.UUID,
+		creatorUserId uuid.UUID,
+		lotIds []uuid.UUID,
+		amount *float64,
+		stage model.BidStage,
+		maxBids int,
+	) (*model.Bid, error)
+	GetBidsByUserId(
+		ctx context.Context, offset int, limit int, userId uuid.UUID,
+	) ([]*model.Bid, error)
+	GetBidsByTenderId(
+		ctx context.Context, offset int, limit int, tenderId uuid.UUID, stage model.BidStage,
+	) ([]*model.Bid, error)
+	GetBidById(ctx context.Context, id uuid.UUID) (*model.Bid, error)
+	GetBidHistory(ctx context.Context, id uuid.UUID) ([]*model.Bid, error)
+	UpdateBidStatusById(
+		ctx context.Context, id uuid.UUID, status model.BidStatus,
+	) (*model.Bid, error)
+	EditBidById(
+		ctx context.Context, id uuid.UUID, name string, description string, amount *float64,
+	) (*model.Bid, error)
+	RollbackById(ctx context.Context, id uuid.UUID, version int32) (*model.Bid, error)
+	CreateReviewById(
+		ctx context.Context,
+		id uuid.UUID,
+		reviewerId uuid.UUID,
+		description string,
+		rating *int,
+		tags []model.ReviewTag,
+	) (*model.Bid, error)
+	GetReviews(
+		ctx context.Context, offset int, limit int, authorId uuid.UUID, tenderId uuid.UUID,
+	) ([]*model.Review, error)
+	GetReviewById(ctx context.Context, id uuid.UUID) (*model.Review, error)
+	EditReview(ctx context.Context, reviewUpd *model.Review) (*model.Review, error)
+	DeleteReview(ctx context.Context, id uuid.UUID) error
+	CreateReply(
+		ctx context.Context, reviewId uuid.UUID, authorId uuid.UUID, text string,
+	) (*model.ReviewReply, error)
+	GetDisicions(ctx context.Context, id uuid.UUID) (rejects int, approves int, err error)
+	UpdateApproves(
+		ctx context.Context, bidId uuid.UUID, approves int, tenderId uuid.UUID, closeTender bool,
+	) error
+	UpdateLotApproves(
+		ctx context.Context, bidId uuid.UUID, lotId uuid.UUID, approves int,
+		tenderId uuid.UUID, award bool,
+	) error
+	UpdateRejects(ctx context.Context, bidId uuid.UUID, rejects int) error
+	GetReputation(
+		ctx context.Context, authorType model.BidAuthorType, authorId uuid.UUID,
+	) (*model.Reputation, error)
+	GetRecentReviews(
+		ctx context.Context, authorType model.BidAuthorType, authorId uuid.UUID, limit int,
+	) ([]*model.Review, error)
+}
+
+type UserRepository interface {
+	GetUserByName(ctx context.Context, name string) (*model.User, error)
+	GetUserById(ctx context.Context, id uuid.UUID) (*model.User, error)
+}
+
+type OrganizationRepository interface {
+	GetOrganizationById(ctx context.Context, id uuid.UUID) (*model.Organization, error)
+	GetOrganizationsByUserId(ctx context.Context, userId uuid.UUID) ([]*model.Organization, error)
+	GetResponsibleUsersId(ctx context.Context, orgId uuid.UUID) ([]uuid.UUID, error)
+}
+
+type InvitationRepository interface {
+	IsInvited(
+		ctx context.Context, tenderId uuid.UUID, inviteeType model.InviteeType, inviteeId uuid.UUID,
+	) (bool, error)
+}
+
+type LotRepository interface {
+	GetLotById(ctx context.Context, id uuid.UUID) (*model.Lot, error)
+	GetLotsByTenderId(ctx context.Context, tenderId uuid.UUID) ([]*model.Lot, error)
+	GetDecisions(
+		ctx context.Context, bidId uuid.UUID, lotId uuid.UUID,
+	) (rejects int, approves int, err error)
+	UpdateRejects(ctx context.Context, bidId uuid.UUID, lotId uuid.UUID, rejects int) error
+}
+
+type ShortlistRepository interface {
+	IsShortlisted(
+		ctx context.Context, tenderId uuid.UUID, authorType model.BidAuthorType, authorId uuid.UUID,
+	) (bool, error)
+}
+
+type Repositories struct {
+	Tender       TenderRepository
+	Bid          BidRepository
+	User         UserRepository
+	Organization OrganizationRepository
+	Invitation   InvitationRepository
+	Lot          LotRepository
+	Shortlist    ShortlistRepository
+}
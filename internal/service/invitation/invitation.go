@@ -0,0 +1,147 @@
+package invitation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+	"avi/internal/repository/invitation"
+	"avi/internal/repository/organization"
+	"avi/internal/repository/tender"
+	"avi/internal/repository/user"
+	"avi/internal/tracing"
+)
+
+var ErrorUserNotFound = errors.New("user does not exist")
+var ErrorUserIsNotOrgResponsible = errors.New("user is not organization responsible")
+var ErrorTenderNotFound = errors.New("tender does not exist")
+var ErrorInviteeNotFound = errors.New("invitee does not exist")
+var ErrorInvitationNotFound = errors.New("invitation does not exist")
+
+type InvitationService struct {
+	tenderRepo     *tender.TenderRepo
+	invitationRepo *invitation.InvitationRepo
+	orgRepo        *organization.OrganizationRepo
+	userRepo       *user.UserRepo
+}
+
+func (service *InvitationService) Invite(
+	ctx context.Context,
+	tenderId uuid.UUID,
+	username string,
+	inviteeType model.InviteeType,
+	inviteeId uuid.UUID,
+) (*model.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.Invite")
+	defer span.End()
+	user, err := service.checkOwner(ctx, tenderId, username)
+	if err != nil {
+		return nil, err
+	}
+
+	switch inviteeType {
+	case model.OrgInviteeType:
+		_, err = service.orgRepo.GetOrganizationById(ctx, inviteeId)
+	case model.UserInviteeType:
+		_, err = service.userRepo.GetUserById(ctx, inviteeId)
+	default:
+		return nil, errors.New("not allowed invitee type")
+	}
+	if err != nil {
+		return nil, ErrorInviteeNotFound
+	}
+
+	invitation, err := service.invitationRepo.CreateInvitation(
+		ctx, tenderId, inviteeType, inviteeId, user.Id,
+	)
+	if err != nil {
+		return nil, errors.New("can not create invitation")
+	}
+	return invitation, nil
+}
+
+func (service *InvitationService) GetInvitations(
+	ctx context.Context, tenderId uuid.UUID, username string,
+) ([]*model.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.GetInvitations")
+	defer span.End()
+	_, err := service.checkOwner(ctx, tenderId, username)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := service.invitationRepo.GetInvitations(ctx, tenderId)
+	if err != nil {
+		return nil, errors.New("can not get invitations")
+	}
+	return invitations, nil
+}
+
+func (service *InvitationService) Revoke(
+	ctx context.Context, tenderId uuid.UUID, invitationId uuid.UUID, username string,
+) error {
+	ctx, span := tracing.Start(ctx, "InvitationService.Revoke")
+	defer span.End()
+	_, err := service.checkOwner(ctx, tenderId, username)
+	if err != nil {
+		return err
+	}
+
+	err = service.invitationRepo.DeleteInvitation(ctx, tenderId, invitationId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrorInvitationNotFound
+	}
+	if err != nil {
+		return errors.New("can not revoke invitation")
+	}
+	return nil
+}
+
+func (service *InvitationService) checkOwner(
+	ctx context.Context, tenderId uuid.UUID, username string,
+) (*model.User, error) {
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
+	if err != nil {
+		return nil, ErrorTenderNotFound
+	}
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+	usersId, err := service.orgRepo.GetResponsibleUsersId(ctx, tender.OrganizationId)
+	if err != nil || !slices.Contains(usersId, user.Id) {
+		return nil, ErrorUserIsNotOrgResponsible
+	}
+	return user, nil
+}
+
+func NewService() (service *InvitationService, err error) {
+	tenderRepository, err := tender.NewRepo()
+	if err != nil {
+		return
+	}
+	invitationRepository, err := invitation.NewRepo()
+	if err != nil {
+		return
+	}
+	organizationRepository, err := organization.NewRepo()
+	if err != nil {
+		return
+	}
+	userRepository, err := user.NewRepo()
+	if err != nil {
+		return
+	}
+
+	service = &InvitationService{
+		tenderRepo:     tenderRepository,
+		invitationRepo: invitationRepository,
+		orgRepo:        organizationRepository,
+		userRepo:       userRepository,
+	}
+	return
+}
@@ -0,0 +1,106 @@
+package lot
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+	"avi/internal/repository/lot"
+	"avi/internal/repository/tender"
+	"avi/internal/tracing"
+)
+
+var ErrorTenderNotFound = errors.New("tender does not exist")
+var ErrorTenderIsClosed = errors.New("tender is closed")
+var ErrorTenderIsNotCreated = errors.New("lots can be added only before tender is published")
+var ErrorLotNotFound = errors.New("lot does not exist")
+var ErrorLotIsNotOpen = errors.New("lot is not open")
+
+type LotService struct {
+	tenderRepo *tender.TenderRepo
+	lotRepo    *lot.LotRepo
+}
+
+func (service *LotService) CreateLot(
+	ctx context.Context,
+	tenderId uuid.UUID,
+	name string,
+	description string,
+	quantity int,
+	budget *float64,
+) (*model.Lot, error) {
+	ctx, span := tracing.Start(ctx, "LotService.CreateLot")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
+	if err != nil {
+		return nil, ErrorTenderNotFound
+	}
+	if tender.Status == model.TenderStatusClosed {
+		return nil, ErrorTenderIsClosed
+	}
+	if tender.Status != model.TenderStatusCreated {
+		return nil, ErrorTenderIsNotCreated
+	}
+
+	lot, err := service.lotRepo.CreateLot(
+		ctx, tenderId, name, description, quantity, budget,
+	)
+	if err != nil {
+		return nil, errors.New("can not create lot")
+	}
+	return lot, nil
+}
+
+func (service *LotService) GetLots(ctx context.Context, tenderId uuid.UUID) ([]*model.Lot, error) {
+	ctx, span := tracing.Start(ctx, "LotService.GetLots")
+	defer span.End()
+	_, err := service.tenderRepo.GetTenderById(ctx, tenderId)
+	if err != nil {
+		return nil, ErrorTenderNotFound
+	}
+
+	lots, err := service.lotRepo.GetLotsByTenderId(ctx, tenderId)
+	if err != nil {
+		return nil, errors.New("can not get lots")
+	}
+	return lots, nil
+}
+
+func (service *LotService) CancelLot(
+	ctx context.Context, tenderId uuid.UUID, lotId uuid.UUID,
+) (*model.Lot, error) {
+	ctx, span := tracing.Start(ctx, "LotService.CancelLot")
+	defer span.End()
+	lot, err := service.lotRepo.GetLotById(ctx, lotId)
+	if err != nil || lot.TenderId != tenderId {
+		return nil, ErrorLotNotFound
+	}
+	if lot.Status != model.OpenLotStatus {
+		return nil, ErrorLotIsNotOpen
+	}
+
+	lot, err = service.lotRepo.CancelLot(ctx, lotId)
+	if err != nil {
+		return nil, errors.New("can not cancel lot")
+	}
+	return lot, nil
+}
+
+func NewService() (service *LotService, err error) {
+	tenderRepository, err := tender.NewRepo()
+	if err != nil {
+		return
+	}
+	lotRepository, err := lot.NewRepo()
+	if err != nil {
+		return
+	}
+
+	service = &LotService{
+		tenderRepo: tenderRepository,
+		lotRepo:    lotRepository,
+	}
+	return
+}
@@ -0,0 +1,221 @@
+package question
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+	"avi/internal/repository/invitation"
+	"avi/internal/repository/organization"
+	"avi/internal/repository/question"
+	"avi/internal/repository/tender"
+	"avi/internal/repository/user"
+	"avi/internal/tracing"
+)
+
+var ErrorUserNotFound = errors.New("user does not exist")
+var ErrorUserIsNotOrgResponsible = errors.New("user is not organization responsible")
+var ErrorUserIsOrgResponsible = errors.New("organization responsible can not ask questions on own tender")
+var ErrorTenderNotFound = errors.New("tender does not exist")
+var ErrorTenderIsNotPublished = errors.New("tender is not published")
+var ErrorQuestionNotFound = errors.New("question does not exist")
+var ErrorQuestionIsNotOpen = errors.New("question is not open")
+var ErrorUserIsNotInvited = errors.New("user is not invited to tender")
+
+type QuestionService struct {
+	tenderRepo     *tender.TenderRepo
+	questionRepo   *question.QuestionRepo
+	orgRepo        *organization.OrganizationRepo
+	userRepo       *user.UserRepo
+	invitationRepo *invitation.InvitationRepo
+}
+
+func (service *QuestionService) AskQuestion(
+	ctx context.Context, tenderId uuid.UUID, username string, text string,
+) (*model.Question, error) {
+	ctx, span := tracing.Start(ctx, "QuestionService.AskQuestion")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
+	if err != nil {
+		return nil, ErrorTenderNotFound
+	}
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+	if !tender.Status.IsOpen() {
+		return nil, ErrorTenderIsNotPublished
+	}
+
+	responsible, err := service.isResponsible(ctx, tender.OrganizationId, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if responsible {
+		return nil, ErrorUserIsOrgResponsible
+	}
+
+	err = service.checkInvitation(ctx, tender, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := service.questionRepo.CreateQuestion(ctx, tenderId, user.Id, text)
+	if err != nil {
+		return nil, errors.New("can not create question")
+	}
+	return question, nil
+}
+
+func (service *QuestionService) GetQuestions(
+	ctx context.Context, offset int, limit int, tenderId uuid.UUID, username string,
+) ([]*model.Question, error) {
+	ctx, span := tracing.Start(ctx, "QuestionService.GetQuestions")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
+	if err != nil {
+		return nil, ErrorTenderNotFound
+	}
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+
+	responsible, err := service.isResponsible(ctx, tender.OrganizationId, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var questions []*model.Question
+	if responsible {
+		questions, err = service.questionRepo.GetQuestionsByTenderId(
+			ctx, offset, limit, tenderId,
+		)
+	} else {
+		if tender.Status == model.TenderStatusCreated {
+			return nil, ErrorUserIsNotOrgResponsible
+		}
+		err = service.checkInvitation(ctx, tender, user.Id)
+		if err != nil {
+			return nil, err
+		}
+		questions, err = service.questionRepo.GetVisibleQuestions(
+			ctx, offset, limit, tenderId, user.Id,
+		)
+	}
+	if err != nil {
+		return nil, errors.New("can not get questions")
+	}
+	return questions, nil
+}
+
+func (service *QuestionService) AnswerQuestion(
+	ctx context.Context,
+	tenderId uuid.UUID,
+	questionId uuid.UUID,
+	username string,
+	answer string,
+	visibility model.AnswerVisibility,
+) (*model.Question, error) {
+	ctx, span := tracing.Start(ctx, "QuestionService.AnswerQuestion")
+	defer span.End()
+	if visibility != model.PublicAnswerVisibility &&
+		visibility != model.PrivateAnswerVisibility {
+		return nil, errors.New("not allowed visibility")
+	}
+
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
+	if err != nil {
+		return nil, ErrorTenderNotFound
+	}
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+
+	responsible, err := service.isResponsible(ctx, tender.OrganizationId, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !responsible {
+		return nil, ErrorUserIsNotOrgResponsible
+	}
+
+	question, err := service.questionRepo.GetQuestionById(ctx, questionId)
+	if err != nil || question.TenderId != tenderId {
+		return nil, ErrorQuestionNotFound
+	}
+	if question.Status != model.OpenQuestionStatus {
+		return nil, ErrorQuestionIsNotOpen
+	}
+
+	question, err = service.questionRepo.AnswerQuestion(
+		ctx, questionId, user.Id, answer, visibility,
+	)
+	if err != nil {
+		return nil, ErrorQuestionIsNotOpen
+	}
+	return question, nil
+}
+
+func (service *QuestionService) isResponsible(
+	ctx context.Context, orgId uuid.UUID, userId uuid.UUID,
+) (bool, error) {
+	usersId, err := service.orgRepo.GetResponsibleUsersId(ctx, orgId)
+	if err != nil {
+		return false, errors.New("can not get organization responsibles")
+	}
+	for _, id := range usersId {
+		if id == userId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (service *QuestionService) checkInvitation(
+	ctx context.Context, tender *model.Tender, userId uuid.UUID,
+) error {
+	if !tender.InviteOnly {
+		return nil
+	}
+	invited, err := service.invitationRepo.IsUserInvited(ctx, tender.Id, userId)
+	if err != nil || !invited {
+		return ErrorUserIsNotInvited
+	}
+	return nil
+}
+
+func NewService() (service *QuestionService, err error) {
+	tenderRepository, err := tender.NewRepo()
+	if err != nil {
+		return
+	}
+	questionRepository, err := question.NewRepo()
+	if err != nil {
+		return
+	}
+	organizationRepository, err := organization.NewRepo()
+	if err != nil {
+		return
+	}
+	userRepository, err := user.NewRepo()
+	if err != nil {
+		return
+	}
+	invitationRepository, err := invitation.NewRepo()
+	if err != nil {
+		return
+	}
+
+	service = &QuestionService{
+		tenderRepo:     tenderRepository,
+		questionRepo:   questionRepository,
+		orgRepo:        organizationRepository,
+		userRepo:       userRepository,
+		invitationRepo: invitationRepository,
+	}
+	return
+}
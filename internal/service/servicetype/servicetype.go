@@ -0,0 +1,214 @@
+package servicetype
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"slices"
+	"sync/atomic"
+
+	"avi/internal/config"
+	"avi/internal/model"
+	"avi/internal/repository/servicetype"
+	"avi/internal/repository/template"
+	"avi/internal/repository/tender"
+	"avi/internal/repository/user"
+	"avi/internal/tracing"
+)
+
+var ErrorUserNotFound = errors.New("user does not exist")
+var ErrorUserIsNotAdmin = errors.New("user is not administrator")
+var ErrorServiceTypeNotFound = errors.New("service type does not exist")
+var ErrorServiceTypeExists = errors.New("service type already exists")
+var ErrorServiceTypeIsUsed = errors.New("service type is used by subcategories or tenders")
+var ErrorIncorrectCode = errors.New("incorrect service type code")
+var ErrorIncorrectParent = errors.New("incorrect parent service type")
+
+var adminConfig atomic.Pointer[config.Admin]
+
+var codePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,49}$`)
+
+type ServiceTypeService struct {
+	serviceTypeRepo *servicetype.ServiceTypeRepo
+	userRepo        *user.UserRepo
+	admins          []string
+}
+
+func (service *ServiceTypeService) GetServiceTypes(
+	ctx context.Context, parentCode model.TenderServiceType,
+) ([]*model.ServiceType, error) {
+	ctx, span := tracing.Start(ctx, "ServiceTypeService.GetServiceTypes")
+	defer span.End()
+	if parentCode == "" {
+		serviceTypes, err := service.serviceTypeRepo.GetServiceTypes(ctx)
+		if err != nil {
+			return nil, errors.New("can not get service types")
+		}
+		return serviceTypes, nil
+	}
+
+	serviceTypes, err := service.serviceTypeRepo.GetSubtree(ctx, parentCode)
+	if err != nil {
+		return nil, errors.New("can not get service types")
+	}
+	if len(serviceTypes) == 0 {
+		return nil, ErrorServiceTypeNotFound
+	}
+	return serviceTypes, nil
+}
+
+func (service *ServiceTypeService) CreateServiceType(
+	ctx context.Context,
+	username string,
+	code model.TenderServiceType,
+	name string,
+	parentCode *model.TenderServiceType,
+) (*model.ServiceType, error) {
+	ctx, span := tracing.Start(ctx, "ServiceTypeService.CreateServiceType")
+	defer span.End()
+	err := service.checkAdmin(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if !codePattern.MatchString(string(code)) {
+		return nil, ErrorIncorrectCode
+	}
+	_, err = service.serviceTypeRepo.GetServiceTypeByCode(ctx, code)
+	if err == nil {
+		return nil, ErrorServiceTypeExists
+	}
+	if parentCode != nil {
+		_, err = service.serviceTypeRepo.GetServiceTypeByCode(ctx, *parentCode)
+		if err != nil {
+			return nil, ErrorIncorrectParent
+		}
+	}
+
+	serviceType, err := service.serviceTypeRepo.CreateServiceType(
+		ctx, &model.ServiceType{Code: code, Name: name, ParentCode: parentCode},
+	)
+	if err != nil {
+		return nil, errors.New("service type creation failed, check fields")
+	}
+	return serviceType, nil
+}
+
+func (service *ServiceTypeService) UpdateServiceType(
+	ctx context.Context,
+	username string,
+	code model.TenderServiceType,
+	name string,
+	parentCode *model.TenderServiceType,
+) (*model.ServiceType, error) {
+	ctx, span := tracing.Start(ctx, "ServiceTypeService.UpdateServiceType")
+	defer span.End()
+	err := service.checkAdmin(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	serviceType, err := service.serviceTypeRepo.GetServiceTypeByCode(ctx, code)
+	if err != nil {
+		return nil, ErrorServiceTypeNotFound
+	}
+	if name != "" {
+		serviceType.Name = name
+	}
+	if parentCode != nil {
+		if *parentCode == "" {
+			serviceType.ParentCode = nil
+		} else {
+			subtree, err := service.serviceTypeRepo.GetSubtree(ctx, code)
+			if err != nil {
+				return nil, errors.New("can not update service type")
+			}
+			if slices.ContainsFunc(subtree, func(child *model.ServiceType) bool {
+				return child.Code == *parentCode
+			}) {
+				return nil, ErrorIncorrectParent
+			}
+			_, err = service.serviceTypeRepo.GetServiceTypeByCode(ctx, *parentCode)
+			if err != nil {
+				return nil, ErrorIncorrectParent
+			}
+			serviceType.ParentCode = parentCode
+		}
+	}
+
+	serviceType, err = service.serviceTypeRepo.UpdateServiceType(ctx, serviceType)
+	if err != nil {
+		return nil, errors.New("can not update service type")
+	}
+	return serviceType, nil
+}
+
+func (service *ServiceTypeService) DeleteServiceType(
+	ctx context.Context, username string, code model.TenderServiceType,
+) error {
+	ctx, span := tracing.Start(ctx, "ServiceTypeService.DeleteServiceType")
+	defer span.End()
+	err := service.checkAdmin(ctx, username)
+	if err != nil {
+		return err
+	}
+	used, err := service.serviceTypeRepo.IsUsed(ctx, code)
+	if err != nil {
+		return errors.New("can not delete service type")
+	}
+	if used {
+		return ErrorServiceTypeIsUsed
+	}
+
+	err = service.serviceTypeRepo.DeleteServiceType(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrorServiceTypeNotFound
+	}
+	if err != nil {
+		return errors.New("can not delete service type")
+	}
+	return nil
+}
+
+func (service *ServiceTypeService) checkAdmin(ctx context.Context, username string) error {
+	_, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return ErrorUserNotFound
+	}
+	if !slices.Contains(service.admins, username) {
+		return ErrorUserIsNotAdmin
+	}
+	return nil
+}
+
+func Setup(cfg config.Admin) {
+	cfg.Usernames = slices.Clone(cfg.Usernames)
+	adminConfig.Store(&cfg)
+}
+
+func NewService() (service *ServiceTypeService, err error) {
+	serviceTypeRepository, err := servicetype.NewRepo()
+	if err != nil {
+		return
+	}
+	_, err = tender.NewRepo()
+	if err != nil {
+		return
+	}
+	_, err = template.NewRepo()
+	if err != nil {
+		return
+	}
+	userRepository, err := user.NewRepo()
+	if err != nil {
+		return
+	}
+
+	service = &ServiceTypeService{
+		serviceTypeRepo: serviceTypeRepository,
+		userRepo:        userRepository,
+	}
+	if cfg := adminConfig.Load(); cfg != nil {
+		service.admins = cfg.Usernames
+	}
+	return
+}
@@ -0,0 +1,155 @@
+package shortlist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+	"avi/internal/repository/bid"
+	"avi/internal/repository/organization"
+	"avi/internal/repository/shortlist"
+	"avi/internal/repository/tender"
+	"avi/internal/repository/user"
+	"avi/internal/tracing"
+)
+
+var ErrorUserNotFound = errors.New("user does not exist")
+var ErrorUserIsNotOrgResponsible = errors.New("user is not organization responsible")
+var ErrorTenderNotFound = errors.New("tender does not exist")
+var ErrorBidNotFound = errors.New("bid does not exist")
+var ErrorEntryNotFound = errors.New("shortlist entry does not exist")
+var ErrorTenderIsNotPrequalification = errors.New("tender is not in prequalification stage")
+var ErrorBidIsNotPrequalification = errors.New("bid is not a prequalification bid")
+
+type ShortlistService struct {
+	tenderRepo    *tender.TenderRepo
+	bidRepo       *bid.BidRepo
+	shortlistRepo *shortlist.ShortlistRepo
+	orgRepo       *organization.OrganizationRepo
+	userRepo      *user.UserRepo
+}
+
+func (service *ShortlistService) Shortlist(
+	ctx context.Context, tenderId uuid.UUID, username string, bidId uuid.UUID,
+) (*model.ShortlistEntry, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistService.Shortlist")
+	defer span.End()
+	tender, user, err := service.checkOwner(ctx, tenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status != model.TenderStatusPrequalification {
+		return nil, ErrorTenderIsNotPrequalification
+	}
+
+	bid, err := service.bidRepo.GetBidById(ctx, bidId)
+	if err != nil || bid.TenderId != tenderId {
+		return nil, ErrorBidNotFound
+	}
+	if bid.Stage != model.PrequalificationBidStage ||
+		bid.Status == model.CanceledBidStatus {
+		return nil, ErrorBidIsNotPrequalification
+	}
+
+	entry, err := service.shortlistRepo.CreateEntry(
+		ctx, tenderId, bid.Id, bid.AuthorType, bid.AuthorId, user.Id,
+	)
+	if err != nil {
+		return nil, errors.New("can not shortlist bid author")
+	}
+	return entry, nil
+}
+
+func (service *ShortlistService) GetShortlist(
+	ctx context.Context, tenderId uuid.UUID, username string,
+) ([]*model.ShortlistEntry, error) {
+	ctx, span := tracing.Start(ctx, "ShortlistService.GetShortlist")
+	defer span.End()
+	_, _, err := service.checkOwner(ctx, tenderId, username)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := service.shortlistRepo.GetEntries(ctx, tenderId)
+	if err != nil {
+		return nil, errors.New("can not get shortlist")
+	}
+	return entries, nil
+}
+
+func (service *ShortlistService) Remove(
+	ctx context.Context, tenderId uuid.UUID, entryId uuid.UUID, username string,
+) error {
+	ctx, span := tracing.Start(ctx, "ShortlistService.Remove")
+	defer span.End()
+	tender, _, err := service.checkOwner(ctx, tenderId, username)
+	if err != nil {
+		return err
+	}
+	if tender.Status != model.TenderStatusPrequalification {
+		return ErrorTenderIsNotPrequalification
+	}
+
+	err = service.shortlistRepo.DeleteEntry(ctx, tenderId, entryId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrorEntryNotFound
+	}
+	if err != nil {
+		return errors.New("can not remove shortlist entry")
+	}
+	return nil
+}
+
+func (service *ShortlistService) checkOwner(
+	ctx context.Context, tenderId uuid.UUID, username string,
+) (*model.Tender, *model.User, error) {
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
+	if err != nil {
+		return nil, nil, ErrorTenderNotFound
+	}
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, nil, ErrorUserNotFound
+	}
+	usersId, err := service.orgRepo.GetResponsibleUsersId(ctx, tender.OrganizationId)
+	if err != nil || !slices.Contains(usersId, user.Id) {
+		return nil, nil, ErrorUserIsNotOrgResponsible
+	}
+	return tender, user, nil
+}
+
+func NewService() (service *ShortlistService, err error) {
+	tenderRepository, err := tender.NewRepo()
+	if err != nil {
+		return
+	}
+	bidRepository, err := bid.NewRepo()
+	if err != nil {
+		return
+	}
+	shortlistRepository, err := shortlist.NewRepo()
+	if err != nil {
+		return
+	}
+	organizationRepository, err := organization.NewRepo()
+	if err != nil {
+		return
+	}
+	userRepository, err := user.NewRepo()
+	if err != nil {
+		return
+	}
+
+	service = &ShortlistService{
+		tenderRepo:    tenderRepository,
+		bidRepo:       bidRepository,
+		shortlistRepo: shortlistRepository,
+		orgRepo:       organizationRepository,
+		userRepo:      userRepository,
+	}
+	return
+}
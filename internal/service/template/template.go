@@ -0,0 +1,257 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"avi/internal/metrics"
+	"avi/internal/model"
+	"avi/internal/repository/organization"
+	"avi/internal/repository/servicetype"
+	"avi/internal/repository/template"
+	"avi/internal/repository/tender"
+	"avi/internal/repository/user"
+	"avi/internal/tracing"
+)
+
+var ErrorUserNotFound = errors.New("user does not exist")
+var ErrorUserIsNotOrgResponsible = errors.New("user is not organization responsible")
+var ErrorTemplateNotFound = errors.New("template does not exist")
+var ErrorServiceTypeNotAllowed = errors.New("not allowed service type")
+
+type TemplateService struct {
+	templateRepo    *template.TemplateRepo
+	tenderRepo      *tender.TenderRepo
+	orgRepo         *organization.OrganizationRepo
+	userRepo        *user.UserRepo
+	serviceTypeRepo *servicetype.ServiceTypeRepo
+}
+
+func (service *TemplateService) CreateTemplate(
+	ctx context.Context,
+	username string,
+	organizationId uuid.UUID,
+	name string,
+	description string,
+	serviceType model.TenderServiceType,
+	criteria []string,
+) (*model.TenderTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.CreateTemplate")
+	defer span.End()
+	user, err := service.checkResponsible(ctx, organizationId, username)
+	if err != nil {
+		return nil, err
+	}
+	_, err = service.serviceTypeRepo.GetServiceTypeByCode(ctx, serviceType)
+	if err != nil {
+		return nil, ErrorServiceTypeNotAllowed
+	}
+	if criteria == nil {
+		criteria = []string{}
+	}
+
+	template, err := service.templateRepo.CreateTemplate(
+		ctx, &model.TenderTemplate{
+			OrganizationId: organizationId,
+			Name:           name,
+			Description:    description,
+			ServiceType:    serviceType,
+			Criteria:       criteria,
+		},
+		user.Id,
+	)
+	if err != nil {
+		return nil, errors.New("template creation failed, check fields")
+	}
+	return template, nil
+}
+
+func (service *TemplateService) GetTemplates(
+	ctx context.Context, username string,
+) ([]*model.TenderTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.GetTemplates")
+	defer span.End()
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+	orgs, err := service.orgRepo.GetOrganizationsByUserId(ctx, user.Id)
+	if err != nil {
+		return nil, errors.New("organization does not exist")
+	}
+	orgsId := make([]uuid.UUID, 0, len(orgs))
+	for _, org := range orgs {
+		orgsId = append(orgsId, org.Id)
+	}
+
+	templates, err := service.templateRepo.GetTemplates(ctx, orgsId)
+	if err != nil {
+		return nil, errors.New("can not get templates")
+	}
+	return templates, nil
+}
+
+func (service *TemplateService) UpdateTemplate(
+	ctx context.Context,
+	id uuid.UUID,
+	username string,
+	name string,
+	description string,
+	serviceType model.TenderServiceType,
+	criteria []string,
+) (*model.TenderTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.UpdateTemplate")
+	defer span.End()
+	template, _, err := service.checkOwner(ctx, id, username)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		template.Name = name
+	}
+	if description != "" {
+		template.Description = description
+	}
+	if serviceType != "" {
+		_, err = service.serviceTypeRepo.GetServiceTypeByCode(ctx, serviceType)
+		if err != nil {
+			return nil, ErrorServiceTypeNotAllowed
+		}
+		template.ServiceType = serviceType
+	}
+	if criteria != nil {
+		template.Criteria = criteria
+	}
+
+	template, err = service.templateRepo.UpdateTemplate(ctx, template)
+	if err != nil {
+		return nil, errors.New("can not update template")
+	}
+	return template, nil
+}
+
+func (service *TemplateService) DeleteTemplate(
+	ctx context.Context, id uuid.UUID, username string,
+) error {
+	ctx, span := tracing.Start(ctx, "TemplateService.DeleteTemplate")
+	defer span.End()
+	_, _, err := service.checkOwner(ctx, id, username)
+	if err != nil {
+		return err
+	}
+	err = service.templateRepo.DeleteTemplate(ctx, id)
+	if err != nil {
+		return errors.New("can not delete template")
+	}
+	return nil
+}
+
+func (service *TemplateService) CreateTender(
+	ctx context.Context,
+	id uuid.UUID,
+	username string,
+	name string,
+	description string,
+	inviteOnly bool,
+) (*model.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.CreateTender")
+	defer span.End()
+	template, user, err := service.checkOwner(ctx, id, username)
+	if err != nil {
+		return nil, err
+	}
+	if description == "" {
+		description = template.Description
+	}
+
+	tender, err := service.tenderRepo.CreateTenderFromTemplate(
+		ctx,
+		template,
+		&model.Tender{
+			Name:           name,
+			Description:    description,
+			ServiceType:    template.ServiceType,
+			OrganizationId: template.OrganizationId,
+			InviteOnly:     inviteOnly,
+			Criteria:       template.Criteria,
+		},
+		user.Id,
+	)
+	if err != nil {
+		return nil, errors.New("tender creation failed, check fields")
+	}
+	metrics.TenderCreated()
+	return tender, nil
+}
+
+func (service *TemplateService) CheckRightByUsername(
+	ctx context.Context, id uuid.UUID, username string,
+) error {
+	ctx, span := tracing.Start(ctx, "TemplateService.CheckRightByUsername")
+	defer span.End()
+	_, _, err := service.checkOwner(ctx, id, username)
+	return err
+}
+
+func (service *TemplateService) checkOwner(
+	ctx context.Context, id uuid.UUID, username string,
+) (*model.TenderTemplate, *model.User, error) {
+	template, err := service.templateRepo.GetTemplateById(ctx, id)
+	if err != nil {
+		return nil, nil, ErrorTemplateNotFound
+	}
+	user, err := service.checkResponsible(ctx, template.OrganizationId, username)
+	if err != nil {
+		return nil, nil, err
+	}
+	return template, user, nil
+}
+
+func (service *TemplateService) checkResponsible(
+	ctx context.Context, organizationId uuid.UUID, username string,
+) (*model.User, error) {
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNotFound
+	}
+	usersId, err := service.orgRepo.GetResponsibleUsersId(ctx, organizationId)
+	if err != nil || !slices.Contains(usersId, user.Id) {
+		return nil, ErrorUserIsNotOrgResponsible
+	}
+	return user, nil
+}
+
+func NewService() (service *TemplateService, err error) {
+	tenderRepository, err := tender.NewRepo()
+	if err != nil {
+		return
+	}
+	templateRepository, err := template.NewRepo()
+	if err != nil {
+		return
+	}
+	organizationRepository, err := organization.NewRepo()
+	if err != nil {
+		return
+	}
+	userRepository, err := user.NewRepo()
+	if err != nil {
+		return
+	}
+	serviceTypeRepository, err := servicetype.NewRepo()
+	if err != nil {
+		return
+	}
+
+	service = &TemplateService{
+		templateRepo:    templateRepository,
+		tenderRepo:      tenderRepository,
+		orgRepo:         organizationRepository,
+		userRepo:        userRepository,
+		serviceTypeRepo: serviceTypeRepository,
+	}
+	return
+}
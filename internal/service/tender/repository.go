@@ -0,0 +1,59 @@
+package tender
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type TenderRepository interface {
+	CreateTender(ctx context.Context, tender *model.Tender, userId uuid.UUID) (*model.Tender, error)
+	CloneTender(
+		ctx context.Context, source *model.Tender, name string, userId uuid.UUID,
+	) (*model.Tender, error)
+	GetTenders(
+		ctx context.Context,
+		serviceTypesFlt []string,
+		offsetFlt string,
+		limitFlt string,
+		orgsIdFlt []uuid.UUID,
+		visibleToFlt *uuid.UUID,
+	) ([]*model.Tender, error)
+	GetTendersByUserId(
+		ctx context.Context, offset int, limit int, userId uuid.UUID,
+	) ([]*model.Tender, error)
+	GetTenderById(ctx context.Context, id uuid.UUID) (*model.Tender, error)
+	GetTenderHistory(ctx context.Context, id uuid.UUID) ([]*model.Tender, error)
+	UpdateTender(ctx context.Context, tenderUpd *model.Tender) (*model.Tender, error)
+	RollBackTender(ctx context.Context, id uuid.UUID, version int32) (*model.Tender, error)
+}
+
+type OrganizationRepository interface {
+	GetOrganizationsByUserId(ctx context.Context, userId uuid.UUID) ([]*model.Organization, error)
+	GetResponsibleUsersId(ctx context.Context, orgId uuid.UUID) ([]uuid.UUID, error)
+}
+
+type UserRepository interface {
+	GetUserByName(ctx context.Context, name string) (*model.User, error)
+}
+
+type InvitationRepository interface {
+	IsUserInvited(ctx context.Context, tenderId uuid.UUID, userId uuid.UUID) (bool, error)
+}
+
+type ServiceTypeRepository interface {
+	GetServiceTypeByCode(
+		ctx context.Context, code model.TenderServiceType,
+	) (*model.ServiceType, error)
+	GetSubtree(ctx context.Context, code model.TenderServiceType) ([]*model.ServiceType, error)
+}
+
+type Repositories struct {
+	Tender       TenderRepository
+	Organization OrganizationRepository
+	User         UserRepository
+	Invitation   InvitationRepository
+	ServiceType  ServiceTypeRepository
+}
@@ -1,41 +1,62 @@
 package tender
 
 import (
+	"context"
 	"errors"
-	"log/slog"
+	"slices"
 	"strconv"
 
 	"github.com/google/uuid"
 
+	"avi/internal/database"
+	"avi/internal/logging"
+	"avi/internal/metrics"
 	"avi/internal/model"
+	"avi/internal/repository/invitation"
+	"avi/internal/repository/lot"
 	"avi/internal/repository/organization"
+	"avi/internal/repository/servicetype"
 	"avi/internal/repository/tender"
 	"avi/internal/repository/user"
+	"avi/internal/tracing"
 )
 
 var ErrorUserNorFound = errors.New("user does not exist")
 var ErrorUserIsNotOrgResponsible = errors.New("user is not organization responsible")
 var ErrorTenderNorFound = errors.New("tender does not exist")
+var ErrorUserIsNotInvited = errors.New("user is not invited to tender")
+var ErrorIncorrectBudget = errors.New("incorrect tender budget")
+var ErrorIncorrectStage = errors.New("tender stage transition is not allowed")
+var ErrorTenderIsNotClosed = errors.New("tender is not closed")
+var ErrorServiceTypeNotAllowed = errors.New("not allowed service type")
 
 type TenderService struct {
-	tenderRepo *tender.TenderRepo
-	orgRepo    *organization.OrganizationRepo
-	userRepo   *user.UserRepo
+	tenderRepo      TenderRepository
+	orgRepo         OrganizationRepository
+	userRepo        UserRepository
+	invitationRepo  InvitationRepository
+	serviceTypeRepo ServiceTypeRepository
 }
 
 func (service *TenderService) CreateTender(
+	ctx context.Context,
 	name string,
 	description string,
 	serviceType model.TenderServiceType,
 	organizarionId uuid.UUID,
 	createUsername string,
+	inviteOnly bool,
+	criteria []string,
+	budget model.TenderBudget,
 ) (tender *model.Tender, err error) {
-	user, err := service.userRepo.GetUserByName(createUsername)
+	ctx, span := tracing.Start(ctx, "TenderService.CreateTender")
+	defer span.End()
+	user, err := service.userRepo.GetUserByName(ctx, createUsername)
 	if err != nil {
 		err = ErrorUserNorFound
 		return
 	}
-	orgs, err := service.orgRepo.GetOrganizationsByUserId(user.Id)
+	orgs, err := service.orgRepo.GetOrganizationsByUserId(ctx, user.Id)
 	if err != nil || orgs == nil {
 		err = ErrorUserIsNotOrgResponsible
 		return
@@ -52,30 +73,57 @@ func (service *TenderService) CreateTender(
 		return
 	}
 
+	_, err = service.serviceTypeRepo.GetServiceTypeByCode(ctx, serviceType)
+	if err != nil {
+		err = ErrorServiceTypeNotAllowed
+		return
+	}
+
+	err = validateBudget(&budget)
+	if err != nil {
+		return
+	}
+
+	if criteria == nil {
+		criteria = []string{}
+	}
 	tender, err = service.tenderRepo.CreateTender(
-		name, description, serviceType, organizarionId, user.Id,
+		ctx, &model.Tender{
+			Name:           name,
+			Description:    description,
+			ServiceType:    serviceType,
+			OrganizationId: organizarionId,
+			InviteOnly:     inviteOnly,
+			Criteria:       criteria,
+			TenderBudget:   budget,
+		},
+		user.Id,
 	)
 	if err != nil {
 		err = errors.New("tender creation failed, check fields")
 		return
 	}
+	metrics.TenderCreated()
 
 	return
 }
 
 func (service *TenderService) GetMyTenders(
+	ctx context.Context,
 	offset int,
 	limit int,
 	username string,
 ) ([]*model.Tender, error) {
-	user, err := service.userRepo.GetUserByName(username)
+	ctx, span := tracing.Start(ctx, "TenderService.GetMyTenders")
+	defer span.End()
+	user, err := service.userRepo.GetUserByName(ctx, username)
 	if err != nil {
 		err = ErrorUserNorFound
 		return nil, err
 	}
 
 	tenders, err := service.tenderRepo.GetTendersByUserId(
-		offset, limit, user.Id,
+		ctx, offset, limit, user.Id,
 	)
 	if err != nil {
 		err = errors.New("can not get tenders")
@@ -86,13 +134,21 @@ func (service *TenderService) GetMyTenders(
 }
 
 func (service *TenderService) GetTenders(
+	ctx context.Context,
 	serviceType model.TenderServiceType,
+	includeSubcategories bool,
 	offset int,
 	limit int,
 	username string,
+	viewerUsername string,
 ) ([]*model.Tender, error) {
-	var offsetFlt, limitFlt, serviceTypeFlt string
+	ctx, span := tracing.Start(ctx, "TenderService.GetTenders")
+	defer span.End()
+	var offsetFlt, limitFlt string
+	var serviceTypesFlt []string
 	var orgsId []uuid.UUID
+	var viewerOrgsId []uuid.UUID
+	visibleTo := &uuid.Nil
 
 	if offset > 0 {
 		offsetFlt = strconv.Itoa(offset)
@@ -101,13 +157,13 @@ func (service *TenderService) GetTenders(
 		limitFlt = strconv.Itoa(limit)
 	}
 	if username != "" {
-		user, err := service.userRepo.GetUserByName(username)
+		user, err := service.userRepo.GetUserByName(ctx, username)
 		if err != nil {
 			err = ErrorUserNorFound
 			return nil, err
 		}
 
-		orgs, err := service.orgRepo.GetOrganizationsByUserId(user.Id)
+		orgs, err := service.orgRepo.GetOrganizationsByUserId(ctx, user.Id)
 		if err != nil {
 			err = errors.New("organization does not exist")
 			return nil, err
@@ -117,32 +173,70 @@ func (service *TenderService) GetTenders(
 			orgsId = append(orgsId, org.Id)
 		}
 	}
-	if serviceType != "" &&
-		serviceType != model.TenderServiceTypeConstruction &&
-		serviceType != model.TenderServiceTypeDelivery &&
-		serviceType != model.TenderServiceTypeManufacture {
-		err := errors.New("not allowed serviceType")
-		return nil, err
+	if viewerUsername != "" {
+		viewer, err := service.userRepo.GetUserByName(ctx, viewerUsername)
+		if err != nil {
+			err = ErrorUserNorFound
+			return nil, err
+		}
+		visibleTo = &viewer.Id
+
+		orgs, err := service.orgRepo.GetOrganizationsByUserId(ctx, viewer.Id)
+		if err != nil {
+			err = errors.New("organization does not exist")
+			return nil, err
+		}
+		for _, org := range orgs {
+			viewerOrgsId = append(viewerOrgsId, org.Id)
+		}
+	}
+	if serviceType != "" {
+		serviceTypes, err := service.serviceTypeRepo.GetSubtree(ctx, serviceType)
+		if err != nil {
+			logging.FromContext(ctx).Info(err.Error())
+			return nil, errors.New("can not get service types")
+		}
+		if len(serviceTypes) == 0 {
+			return nil, ErrorServiceTypeNotAllowed
+		}
+		if includeSubcategories {
+			for _, serviceType := range serviceTypes {
+				serviceTypesFlt = append(serviceTypesFlt, string(serviceType.Code))
+			}
+		} else {
+			serviceTypesFlt = []string{string(serviceType)}
+		}
 	}
-	serviceTypeFlt = string(serviceType)
 
 	tenders, err := service.tenderRepo.GetTenders(
-		serviceTypeFlt,
+		ctx,
+		serviceTypesFlt,
 		offsetFlt,
 		limitFlt,
 		orgsId,
+		visibleTo,
 	)
 	if err != nil {
-		slog.Info(err.Error())
+		logging.FromContext(ctx).Info(err.Error())
 		err = errors.New("can not get tenders")
 		return nil, err
 	}
 
+	for _, tender := range tenders {
+		if !slices.Contains(viewerOrgsId, tender.OrganizationId) {
+			tender.Reserve = nil
+		}
+	}
+
 	return tenders, err
 }
 
-func (service *TenderService) GetTenderById(id uuid.UUID) (*model.Tender, error) {
-	tender, err := service.tenderRepo.GetTenderById(id)
+func (service *TenderService) GetTenderById(
+	ctx context.Context, id uuid.UUID,
+) (*model.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TenderService.GetTenderById")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, id)
 	if err != nil {
 		err = ErrorTenderNorFound
 		return nil, err
@@ -151,34 +245,72 @@ func (service *TenderService) GetTenderById(id uuid.UUID) (*model.Tender, error)
 }
 
 func (service *TenderService) UpdateTenderStatus(
-	id uuid.UUID, status model.TenderStatus,
+	ctx context.Context, id uuid.UUID, status model.TenderStatus,
 ) (*model.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TenderService.UpdateTenderStatus")
+	defer span.End()
 	if status != model.TenderStatusClosed &&
 		status != model.TenderStatusCreated &&
-		status != model.TenderStatusPublished {
+		status != model.TenderStatusPublished &&
+		status != model.TenderStatusPrequalification &&
+		status != model.TenderStatusCommercial {
 		return nil, errors.New("not allowed status")
 	}
 
-	tender, err := service.tenderRepo.GetTenderById(id)
+	tender, err := service.tenderRepo.GetTenderById(ctx, id)
 	if err != nil {
 		return tender, ErrorTenderNorFound
 	}
+
+	multiStage := tender.Status == model.TenderStatusPrequalification ||
+		tender.Status == model.TenderStatusCommercial
+	switch status {
+	case model.TenderStatusPublished:
+		if multiStage {
+			return nil, ErrorIncorrectStage
+		}
+	case model.TenderStatusPrequalification:
+		if tender.Status != model.TenderStatusCreated &&
+			tender.Status != model.TenderStatusPrequalification {
+			return nil, ErrorIncorrectStage
+		}
+	case model.TenderStatusCommercial:
+		if !multiStage {
+			return nil, ErrorIncorrectStage
+		}
+	}
+	previous := tender.Status
 	tender.Status = status
-	tenderUpd, err := service.tenderRepo.UpdateTender(tender)
+	tenderUpd, err := service.tenderRepo.UpdateTender(ctx, tender)
 	if err != nil {
+		if interrupted := database.Interrupted(err); interrupted != nil {
+			return nil, interrupted
+		}
 		return nil, errors.New("can not update tender")
 	}
+	if previous != status {
+		metrics.TenderStatusChanged(string(status))
+	}
 
 	return tenderUpd, nil
 }
 
 func (service *TenderService) UpdateTender(
+	ctx context.Context,
 	id uuid.UUID,
 	name string,
 	description string,
 	serviceType model.TenderServiceType,
+	inviteOnly *bool,
+	criteria []string,
+	budget *float64,
+	reserve *float64,
+	currency string,
+	strictBudget *bool,
 ) (*model.Tender, error) {
-	tender, err := service.tenderRepo.GetTenderById(id)
+	ctx, span := tracing.Start(ctx, "TenderService.UpdateTender")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, id)
 	if err != nil {
 		return tender, ErrorTenderNorFound
 	}
@@ -189,42 +321,121 @@ func (service *TenderService) UpdateTender(
 		tender.Description = description
 	}
 	if serviceType != "" {
-		if serviceType != model.TenderServiceTypeConstruction &&
-			serviceType != model.TenderServiceTypeDelivery &&
-			serviceType != model.TenderServiceTypeManufacture {
-			err = errors.New("not allowed service type")
-			return nil, err
+		_, err = service.serviceTypeRepo.GetServiceTypeByCode(ctx, serviceType)
+		if err != nil {
+			return nil, ErrorServiceTypeNotAllowed
 		}
 		tender.ServiceType = serviceType
 	}
-	tenderUpd, err := service.tenderRepo.UpdateTender(tender)
+	if inviteOnly != nil {
+		tender.InviteOnly = *inviteOnly
+	}
+	if criteria != nil {
+		tender.Criteria = criteria
+	}
+	if budget != nil {
+		tender.Budget = budget
+	}
+	if reserve != nil {
+		tender.Reserve = reserve
+	}
+	if currency != "" {
+		tender.Currency = currency
+	}
+	if strictBudget != nil {
+		tender.StrictBudget = *strictBudget
+	}
+	err = validateBudget(&tender.TenderBudget)
+	if err != nil {
+		return nil, err
+	}
+	tenderUpd, err := service.tenderRepo.UpdateTender(ctx, tender)
 	if err != nil {
+		if interrupted := database.Interrupted(err); interrupted != nil {
+			return nil, interrupted
+		}
 		return nil, errors.New("can not update tender")
 	}
 	return tenderUpd, err
 }
 
-func (service *TenderService) RollBackTender(id uuid.UUID, version int32) (*model.Tender, error) {
-	tender, err := service.tenderRepo.GetTenderById(id)
+func (service *TenderService) CloneTender(
+	ctx context.Context, id uuid.UUID, username string, name string,
+) (*model.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TenderService.CloneTender")
+	defer span.End()
+	user, err := service.userRepo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, ErrorUserNorFound
+	}
+	source, err := service.tenderRepo.GetTenderById(ctx, id)
+	if err != nil {
+		return nil, ErrorTenderNorFound
+	}
+	if source.Status != model.TenderStatusClosed {
+		return nil, ErrorTenderIsNotClosed
+	}
+	if name == "" {
+		name = source.Name + " (copy)"
+	}
+
+	tender, err := service.tenderRepo.CloneTender(ctx, source, name, user.Id)
+	if err != nil {
+		logging.FromContext(ctx).Error(err.Error())
+		return nil, errors.New("tender clone failed, check name")
+	}
+	metrics.TenderCreated()
+	return tender, nil
+}
+
+func (service *TenderService) RollBackTender(
+	ctx context.Context, id uuid.UUID, version int32,
+) (*model.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TenderService.RollBackTender")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, id)
 	if err != nil {
 		return nil, ErrorTenderNorFound
 	}
-	tender, err = service.tenderRepo.RollBackTender(tender.Id, version)
+	tender, err = service.tenderRepo.RollBackTender(ctx, tender.Id, version)
 	if err != nil {
+		if interrupted := database.Interrupted(err); interrupted != nil {
+			return nil, interrupted
+		}
 		return nil, errors.New("cat not rollback tender")
 	}
+	metrics.Rollback("tender")
 	return tender, nil
 }
 
+func (service *TenderService) GetTenderHistory(
+	ctx context.Context, id uuid.UUID,
+) ([]*model.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TenderService.GetTenderHistory")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, id)
+	if err != nil {
+		return nil, ErrorTenderNorFound
+	}
+	history, err := service.tenderRepo.GetTenderHistory(ctx, id)
+	if err != nil {
+		return nil, errors.New("can not get tender history")
+	}
+	history = append(history, tender)
+	return history, nil
+}
+
 func (service *TenderService) CheckReadRightByUsername(
-	tenderId uuid.UUID, username string,
+	ctx context.Context, tenderId uuid.UUID, username string,
 ) error {
-	tender, err := service.tenderRepo.GetTenderById(tenderId)
+	ctx, span := tracing.Start(ctx, "TenderService.CheckReadRightByUsername")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
 	if err != nil {
 		err = ErrorTenderNorFound
 		return err
 	}
-	if tender.Status == model.TenderStatusPublished {
+	if tender.Status.IsOpen() && !tender.InviteOnly {
 		return nil
 	}
 
@@ -232,14 +443,14 @@ func (service *TenderService) CheckReadRightByUsername(
 		return ErrorUserIsNotOrgResponsible
 	}
 
-	user, err := service.userRepo.GetUserByName(username)
+	user, err := service.userRepo.GetUserByName(ctx, username)
 	if err != nil {
 		err = ErrorUserNorFound
 		return err
 	}
 
 	usersId, err := service.orgRepo.GetResponsibleUsersId(
-		tender.OrganizationId,
+		ctx, tender.OrganizationId,
 	)
 
 	if err != nil {
@@ -252,13 +463,24 @@ func (service *TenderService) CheckReadRightByUsername(
 			return nil
 		}
 	}
-	return ErrorUserIsNotOrgResponsible
+
+	if !tender.Status.IsOpen() {
+		return ErrorUserIsNotOrgResponsible
+	}
+
+	invited, err := service.invitationRepo.IsUserInvited(ctx, tenderId, user.Id)
+	if err != nil || !invited {
+		return ErrorUserIsNotInvited
+	}
+	return nil
 }
 
 func (service *TenderService) CheckWriteRightByUsername(
-	tenderId uuid.UUID, username string,
+	ctx context.Context, tenderId uuid.UUID, username string,
 ) error {
-	tender, err := service.tenderRepo.GetTenderById(tenderId)
+	ctx, span := tracing.Start(ctx, "TenderService.CheckWriteRightByUsername")
+	defer span.End()
+	tender, err := service.tenderRepo.GetTenderById(ctx, tenderId)
 	if err != nil {
 		err = ErrorTenderNorFound
 		return err
@@ -268,14 +490,14 @@ func (service *TenderService) CheckWriteRightByUsername(
 		return ErrorUserIsNotOrgResponsible
 	}
 
-	user, err := service.userRepo.GetUserByName(username)
+	user, err := service.userRepo.GetUserByName(ctx, username)
 	if err != nil {
 		err = ErrorUserNorFound
 		return err
 	}
 
 	usersId, err := service.orgRepo.GetResponsibleUsersId(
-		tender.OrganizationId,
+		ctx, tender.OrganizationId,
 	)
 
 	if err != nil {
@@ -291,6 +513,26 @@ func (service *TenderService) CheckWriteRightByUsername(
 	return ErrorUserIsNotOrgResponsible
 }
 
+func validateBudget(budget *model.TenderBudget) error {
+	if budget.Budget != nil && *budget.Budget <= 0 ||
+		budget.Reserve != nil && *budget.Reserve <= 0 {
+		return ErrorIncorrectBudget
+	}
+	if budget.Budget != nil && budget.Reserve != nil &&
+		*budget.Reserve > *budget.Budget {
+		return ErrorIncorrectBudget
+	}
+	if budget.StrictBudget && budget.Budget == nil {
+		return ErrorIncorrectBudget
+	}
+	if budget.Budget == nil && budget.Reserve == nil {
+		budget.Currency = ""
+	} else if budget.Currency == "" {
+		return ErrorIncorrectBudget
+	}
+	return nil
+}
+
 func NewService() (service *TenderService, err error) {
 	tenderRerository, err := tender.NewRepo()
 	if err != nil {
@@ -301,11 +543,38 @@ func NewService() (service *TenderService, err error) {
 		return
 	}
 	userRepository, err := user.NewRepo()
-
-	service = &TenderService{
-		tenderRepo: tenderRerository,
-		orgRepo:    organizationRepository,
-		userRepo:   userRepository,
+	if err != nil {
+		return
 	}
+	invitationRepository, err := invitation.NewRepo()
+	if err != nil {
+		return
+	}
+	_, err = lot.NewRepo()
+	if err != nil {
+		return
+	}
+	serviceTypeRepository, err := servicetype.NewRepo()
+	if err != nil {
+		return
+	}
+
+	service = NewServiceWithRepositories(Repositories{
+		Tender:       tenderRerository,
+		Organization: organizationRepository,
+		User:         userRepository,
+		Invitation:   invitationRepository,
+		ServiceType:  serviceTypeRepository,
+	})
 	return
 }
+
+func NewServiceWithRepositories(repos Repositories) *TenderService {
+	return &TenderService{
+		tenderRepo:      repos.Tender,
+		orgRepo:         repos.Organization,
+		userRepo:        repos.User,
+		invitationRepo:  repos.Invitation,
+		serviceTypeRepo: repos.ServiceType,
+	}
+}
@@ -0,0 +1,140 @@
+package tender_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"avi/internal/model"
+	"avi/internal/repository/memory"
+	"avi/internal/service/tender"
+)
+
+var (
+	_ tender.TenderRepository       = (*memory.TenderRepo)(nil)
+	_ tender.OrganizationRepository = (*memory.OrganizationRepo)(nil)
+	_ tender.UserRepository         = (*memory.UserRepo)(nil)
+	_ tender.InvitationRepository   = (*memory.InvitationRepo)(nil)
+	_ tender.ServiceTypeRepository  = (*memory.ServiceTypeRepo)(nil)
+)
+
+func newService(t *testing.T) (*memory.Store, *tender.TenderService, *model.Tender) {
+	t.Helper()
+
+	store := memory.NewStore()
+	service := tender.NewServiceWithRepositories(tender.Repositories{
+		Tender:       store.Tenders(),
+		Organization: store.Organizations(),
+		User:         store.Users(),
+		Invitation:   store.Invitations(),
+		ServiceType:  store.ServiceTypes(),
+	})
+
+	owner := store.AddUser(model.User{Username: "owner"})
+	org := store.AddOrganization(model.Organization{Name: "customer"}, owner.Id)
+	created, err := service.CreateTender(
+		context.Background(), "tender", "description", "Delivery",
+		org.Id, owner.Username, false, nil, model.TenderBudget{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, service, created
+}
+
+func TestCreateTenderRequiresOrgResponsible(t *testing.T) {
+	store, service, created := newService(t)
+	store.AddUser(model.User{Username: "stranger"})
+
+	_, err := service.CreateTender(
+		context.Background(), "other", "description", "Delivery",
+		created.OrganizationId, "stranger", false, nil, model.TenderBudget{},
+	)
+	if !errors.Is(err, tender.ErrorUserIsNotOrgResponsible) {
+		t.Fatalf("got %v, want ErrorUserIsNotOrgResponsible", err)
+	}
+}
+
+func TestUpdateTenderStatusStages(t *testing.T) {
+	_, service, created := newService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		status model.TenderStatus
+		err    error
+	}{
+		{model.TenderStatusCommercial, tender.ErrorIncorrectStage},
+		{model.TenderStatusPrequalification, nil},
+		{model.TenderStatusPublished, tender.ErrorIncorrectStage},
+		{model.TenderStatusCommercial, nil},
+		{model.TenderStatusClosed, nil},
+	}
+	for _, test := range tests {
+		updated, err := service.UpdateTenderStatus(ctx, created.Id, test.status)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("%s: got %v, want %v", test.status, err, test.err)
+		}
+		if err == nil && updated.Status != test.status {
+			t.Fatalf("%s: status %s", test.status, updated.Status)
+		}
+	}
+}
+
+func TestRollBackTender(t *testing.T) {
+	_, service, created := newService(t)
+	ctx := context.Background()
+
+	_, err := service.UpdateTenderStatus(ctx, created.Id, model.TenderStatusPublished)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rolledBack, err := service.RollBackTender(ctx, created.Id, created.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rolledBack.Status != model.TenderStatusCreated || rolledBack.Version != created.Version+2 {
+		t.Errorf("rollback: %s version %d, want Created version %d",
+			rolledBack.Status, rolledBack.Version, created.Version+2)
+	}
+
+	history, err := service.GetTenderHistory(ctx, created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Errorf("history has %d versions, want 3", len(history))
+	}
+}
+
+func TestRollBackTenderKeepsStateOnRepositoryError(t *testing.T) {
+	store, service, created := newService(t)
+	ctx := context.Background()
+
+	_, err := service.UpdateTenderStatus(ctx, created.Id, model.TenderStatusPublished)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.Fail("RollBackTender", errors.New("connection reset"))
+	_, err = service.RollBackTender(ctx, created.Id, created.Version)
+	if err == nil {
+		t.Fatal("expected repository error")
+	}
+
+	current, err := service.GetTenderById(ctx, created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Status != model.TenderStatusPublished || current.Version != created.Version+1 {
+		t.Errorf("tender %s version %d after failed rollback, want Published version %d",
+			current.Status, current.Version, created.Version+1)
+	}
+	history, err := service.GetTenderHistory(ctx, created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Errorf("history has %d versions after failed rollback, want 2", len(history))
+	}
+}
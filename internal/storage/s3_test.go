@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"avi/internal/config"
+)
+
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func TestSignV4Vectors(t *testing.T) {
+	tests := []struct {
+		name             string
+		url              string
+		headers          map[string]string
+		signedHeaders    []string
+		secretKey        string
+		region           string
+		service          string
+		time             string
+		canonicalRequest string
+		stringToSign     string
+		signature        string
+	}{
+		{
+			name:          "aws4 test suite get-vanilla",
+			url:           "https://example.amazonaws.com/",
+			headers:       map[string]string{"X-Amz-Date": "20150830T123600Z"},
+			signedHeaders: []string{"host", "x-amz-date"},
+			secretKey:     "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+			region:        "us-east-1",
+			service:       "service",
+			time:          "20150830T123600Z",
+			canonicalRequest: "GET\n/\n\n" +
+				"host:example.amazonaws.com\n" +
+				"x-amz-date:20150830T123600Z\n\n" +
+				"host;x-amz-date\n" +
+				emptyPayloadHash,
+			stringToSign: "AWS4-HMAC-SHA256\n20150830T123600Z\n" +
+				"20150830/us-east-1/service/aws4_request\n" +
+				"bb579772317eb040ac9ed261061d46c1f17a8133879d6129b6e1c25292927e63",
+			signature: "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name: "s3 get object example",
+			url:  "https://examplebucket.s3.amazonaws.com/test.txt",
+			headers: map[string]string{
+				"Range":                "bytes=0-9",
+				"X-Amz-Content-Sha256": emptyPayloadHash,
+				"X-Amz-Date":           "20130524T000000Z",
+			},
+			signedHeaders: []string{"host", "range", "x-amz-content-sha256", "x-amz-date"},
+			secretKey:     "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY",
+			region:        "us-east-1",
+			service:       "s3",
+			time:          "20130524T000000Z",
+			canonicalRequest: "GET\n/test.txt\n\n" +
+				"host:examplebucket.s3.amazonaws.com\n" +
+				"range:bytes=0-9\n" +
+				"x-amz-content-sha256:" + emptyPayloadHash + "\n" +
+				"x-amz-date:20130524T000000Z\n\n" +
+				"host;range;x-amz-content-sha256;x-amz-date\n" +
+				emptyPayloadHash,
+			stringToSign: "AWS4-HMAC-SHA256\n20130524T000000Z\n" +
+				"20130524/us-east-1/s3/aws4_request\n" +
+				"7344ae5b7ee6c3e7e6b0fe0640412a37625d1fbfff95c48bbb2dc43964946972",
+			signature: "f0e8bdb87c964420e857bd35b5d6ed310bd44f0170aba48dd91039c6036bdb41",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+			now, err := time.Parse(amzDateFormat, test.time)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			canonical := canonicalRequest(req, test.signedHeaders, emptyPayloadHash)
+			if canonical != test.canonicalRequest {
+				t.Errorf("canonical request:\n%s\nwant:\n%s", canonical, test.canonicalRequest)
+			}
+			scope, signature := signV4(test.secretKey, test.region, test.service, now, canonical)
+			if got := stringToSign(now, scope, canonical); got != test.stringToSign {
+				t.Errorf("string to sign:\n%s\nwant:\n%s", got, test.stringToSign)
+			}
+			if signature != test.signature {
+				t.Errorf("signature %s, want %s", signature, test.signature)
+			}
+		})
+	}
+}
+
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3(t *testing.T, cfg config.S3) *httptest.Server {
+	t.Helper()
+
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !fake.authorized(r, cfg) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fake.serve(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (fake *fakeS3) authorized(r *http.Request, cfg config.S3) bool {
+	now, err := time.Parse(amzDateFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+	req := r.Clone(r.Context())
+	req.URL.Host = r.Host
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	scope, signature := signV4(
+		cfg.SecretKey, cfg.Region, "s3", now,
+		canonicalRequest(req, signedHeaders, r.Header.Get("X-Amz-Content-Sha256")),
+	)
+	want := fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKey, scope, strings.Join(signedHeaders, ";"), signature,
+	)
+	return r.Header.Get("Authorization") == want
+}
+
+func (fake *fakeS3) serve(w http.ResponseWriter, r *http.Request) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fake.objects[r.URL.Path] = body
+		fake.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := fake.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", fake.types[r.URL.Path])
+		w.Write(body)
+	case http.MethodDelete:
+		if _, ok := fake.objects[r.URL.Path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(fake.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestS3(t *testing.T) *S3Storage {
+	t.Helper()
+
+	cfg := config.S3{
+		Region:    "eu-central-1",
+		Bucket:    "attachments",
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	cfg.Endpoint = newFakeS3(t, cfg).URL
+
+	s, err := NewS3(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestS3PutGetDelete(t *testing.T) {
+	s := newTestS3(t)
+	ctx := context.Background()
+	key := "tenders/attachment with spaces+plus.txt"
+	content := []byte("attachment content")
+
+	err := s.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "text/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("get: %q, want %q", got, content)
+	}
+
+	err = s.Delete(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Get(ctx, key)
+	if !errors.Is(err, ErrorObjectNotFound) {
+		t.Errorf("get after delete: got %v, want ErrorObjectNotFound", err)
+	}
+	err = s.Delete(ctx, key)
+	if err != nil {
+		t.Errorf("delete missing object: %v", err)
+	}
+}
+
+func TestS3RejectsWrongCredentials(t *testing.T) {
+	s := newTestS3(t)
+	s.secretKey = "wrong"
+
+	_, err := s.Get(context.Background(), "missing")
+	if err == nil || errors.Is(err, ErrorObjectNotFound) {
+		t.Fatalf("got %v, want forbidden error", err)
+	}
+}
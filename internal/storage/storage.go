@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"avi/internal/config"
+)
+
+var ErrorObjectNotFound = errors.New("object does not exist")
+
+type BlobStorage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func New(cfg config.Storage) (BlobStorage, error) {
+	switch cfg.Type {
+	case "", "local":
+		dir := cfg.LocalDir
+		if dir == "" {
+			dir = "attachments"
+		}
+		return NewLocal(dir)
+	case "s3":
+		return NewS3(cfg.S3)
+	}
+	return nil, fmt.Errorf("unknown storage type %q", cfg.Type)
+}
@@ -0,0 +1,220 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type AttachmentContent struct {
+	io.ReadCloser
+	FileName    string
+	ContentType string
+	Checksum    string
+}
+
+func (c *Client) UploadTenderAttachment(
+	ctx context.Context, tenderId uuid.UUID, username string, fileName string, content io.Reader,
+) (*model.Attachment, error) {
+	q := url.Values{"username": {username}}
+	return c.upload(ctx, tenderPath(tenderId, "attachments"), q, fileName, content)
+}
+
+func (c *Client) GetTenderAttachments(
+	ctx context.Context, tenderId uuid.UUID, version int32, username string,
+) ([]*model.Attachment, error) {
+	q := attachmentsQuery(version, username)
+
+	var attachments []*model.Attachment
+	err := c.do(ctx, http.MethodGet, tenderPath(tenderId, "attachments"), q, nil, &attachments)
+	return attachments, err
+}
+
+func (c *Client) DownloadTenderAttachment(
+	ctx context.Context, tenderId uuid.UUID, attachmentId uuid.UUID, username string,
+) (*AttachmentContent, error) {
+	q := attachmentsQuery(0, username)
+	path := tenderPath(tenderId, "attachments", attachmentId.String())
+	return c.download(ctx, path, q)
+}
+
+func (c *Client) DeleteTenderAttachment(
+	ctx context.Context, tenderId uuid.UUID, attachmentId uuid.UUID, username string,
+) (*model.Tender, error) {
+	q := url.Values{"username": {username}}
+	path := tenderPath(tenderId, "attachments", attachmentId.String())
+
+	var res model.Tender
+	err := c.do(ctx, http.MethodDelete, path, q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) UploadBidAttachment(
+	ctx context.Context, bidId uuid.UUID, username string, fileName string, content io.Reader,
+) (*model.Attachment, error) {
+	q := url.Values{"username": {username}}
+	return c.upload(ctx, bidPath(bidId, "attachments"), q, fileName, content)
+}
+
+func (c *Client) GetBidAttachments(
+	ctx context.Context, bidId uuid.UUID, version int32, username string,
+) ([]*model.Attachment, error) {
+	q := attachmentsQuery(version, username)
+
+	var attachments []*model.Attachment
+	err := c.do(ctx, http.MethodGet, bidPath(bidId, "attachments"), q, nil, &attachments)
+	return attachments, err
+}
+
+func (c *Client) DownloadBidAttachment(
+	ctx context.Context, bidId uuid.UUID, attachmentId uuid.UUID, username string,
+) (*AttachmentContent, error) {
+	q := attachmentsQuery(0, username)
+	path := bidPath(bidId, "attachments", attachmentId.String())
+	return c.download(ctx, path, q)
+}
+
+func (c *Client) DeleteBidAttachment(
+	ctx context.Context, bidId uuid.UUID, attachmentId uuid.UUID, username string,
+) (*model.Bid, error) {
+	q := url.Values{"username": {username}}
+	path := bidPath(bidId, "attachments", attachmentId.String())
+
+	var res model.Bid
+	err := c.do(ctx, http.MethodDelete, path, q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func attachmentsQuery(version int32, username string) url.Values {
+	q := url.Values{}
+	if version > 0 {
+		q.Set("version", strconv.Itoa(int(version)))
+	}
+	if username != "" {
+		q.Set("username", username)
+	}
+	return q
+}
+
+func (c *Client) upload(
+	ctx context.Context, path string, query url.Values, fileName string, content io.Reader,
+) (*model.Attachment, error) {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("file", fileName)
+		if err == nil {
+			_, err = io.Copy(part, content)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), pr)
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, errorFromBody(res.StatusCode, data)
+	}
+
+	var attachment model.Attachment
+	err = json.Unmarshal(data, &attachment)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (c *Client) download(
+	ctx context.Context, path string, query url.Values,
+) (*AttachmentContent, error) {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	var err error
+	for attempt := 0; attempt <= max(c.maxRetries, 0); attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(c.retryDelay * time.Duration(1<<(attempt-1)))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var res *http.Response
+		res, err = c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			continue
+		}
+		if res.StatusCode >= http.StatusBadRequest {
+			data, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			err = errorFromBody(res.StatusCode, data)
+			if isRetryable(res.StatusCode) {
+				continue
+			}
+			return nil, err
+		}
+
+		content := &AttachmentContent{
+			ReadCloser:  res.Body,
+			ContentType: res.Header.Get("Content-Type"),
+			Checksum:    res.Header.Get("X-Checksum-Sha256"),
+		}
+		_, params, parseErr := mime.ParseMediaType(res.Header.Get("Content-Disposition"))
+		if parseErr == nil {
+			content.FileName = params["filename"]
+		}
+		return content, nil
+	}
+	return nil, err
+}
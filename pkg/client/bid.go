@@ -0,0 +1,305 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type Decision string
+
+const (
+	DecisionApproved Decision = "Approved"
+	DecisionRejected Decision = "Rejected"
+)
+
+type NewBid struct {
+	Name            string              `json:"name"`
+	Description     string              `json:"description"`
+	TenderId        uuid.UUID           `json:"tenderId"`
+	AuthorType      model.BidAuthorType `json:"authorType"`
+	AuthorId        uuid.UUID           `json:"authorID"`
+	CreatorUsername string              `json:"creatorUsername"`
+	LotIds          []uuid.UUID         `json:"lotIds,omitempty"`
+	Amount          *float64            `json:"amount,omitempty"`
+}
+
+type Feedback struct {
+	Description string
+	Rating      *int
+	Tags        []model.ReviewTag
+}
+
+type ReviewUpdate struct {
+	Description string            `json:"description,omitempty"`
+	Rating      *int              `json:"rating,omitempty"`
+	Tags        []model.ReviewTag `json:"tags,omitempty"`
+}
+
+type BidUpdate struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Amount      *float64 `json:"amount,omitempty"`
+}
+
+func (c *Client) CreateBid(ctx context.Context, bid NewBid) (*model.Bid, error) {
+	var res model.Bid
+	err := c.do(ctx, http.MethodPost, "/api/bids/new", nil, bid, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetMyBids(
+	ctx context.Context, username string, page Page,
+) ([]*model.Bid, error) {
+	q := url.Values{"username": {username}}
+	page.apply(q)
+
+	var bids []*model.Bid
+	err := c.do(ctx, http.MethodGet, "/api/bids/my", q, nil, &bids)
+	return bids, err
+}
+
+func (c *Client) GetBidsForTender(
+	ctx context.Context, tenderId uuid.UUID, username string, page Page,
+) ([]*model.Bid, error) {
+	q := url.Values{"username": {username}}
+	page.apply(q)
+
+	var bids []*model.Bid
+	err := c.do(ctx, http.MethodGet, bidPath(tenderId, "list"), q, nil, &bids)
+	return bids, err
+}
+
+func (c *Client) GetBidsForTenderStage(
+	ctx context.Context,
+	tenderId uuid.UUID,
+	stage model.BidStage,
+	username string,
+	page Page,
+) ([]*model.Bid, error) {
+	q := url.Values{"stage": {string(stage)}, "username": {username}}
+	page.apply(q)
+
+	var bids []*model.Bid
+	err := c.do(ctx, http.MethodGet, bidPath(tenderId, "list"), q, nil, &bids)
+	return bids, err
+}
+
+func (c *Client) GetBidStatus(
+	ctx context.Context, bidId uuid.UUID, username string,
+) (model.BidStatus, error) {
+	q := url.Values{"username": {username}}
+
+	var status model.BidStatus
+	err := c.do(ctx, http.MethodGet, bidPath(bidId, "status"), q, nil, &status)
+	return status, err
+}
+
+func (c *Client) UpdateBidStatus(
+	ctx context.Context, bidId uuid.UUID, status model.BidStatus, username string,
+) (*model.Bid, error) {
+	q := url.Values{"status": {string(status)}, "username": {username}}
+
+	var res model.Bid
+	err := c.do(ctx, http.MethodPut, bidPath(bidId, "status"), q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) EditBid(
+	ctx context.Context, bidId uuid.UUID, username string, update BidUpdate,
+) (*model.Bid, error) {
+	q := url.Values{"username": {username}}
+
+	var res model.Bid
+	err := c.do(ctx, http.MethodPatch, bidPath(bidId, "edit"), q, update, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) SubmitBidDecision(
+	ctx context.Context, bidId uuid.UUID, decision Decision, username string,
+) (*model.Bid, error) {
+	q := url.Values{"decision": {string(decision)}, "username": {username}}
+
+	var res model.Bid
+	err := c.do(ctx, http.MethodPut, bidPath(bidId, "submit_decision"), q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) SubmitBidLotDecision(
+	ctx context.Context,
+	bidId uuid.UUID,
+	lotId uuid.UUID,
+	decision Decision,
+	username string,
+) (*model.Bid, error) {
+	q := url.Values{
+		"decision": {string(decision)},
+		"lotId":    {lotId.String()},
+		"username": {username},
+	}
+
+	var res model.Bid
+	err := c.do(ctx, http.MethodPut, bidPath(bidId, "submit_decision"), q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) SubmitBidFeedback(
+	ctx context.Context, bidId uuid.UUID, feedback Feedback, username string,
+) (*model.Bid, error) {
+	q := url.Values{"bidFeedback": {feedback.Description}, "username": {username}}
+	if feedback.Rating != nil {
+		q.Set("rating", strconv.Itoa(*feedback.Rating))
+	}
+	if len(feedback.Tags) > 0 {
+		tags := make([]string, 0, len(feedback.Tags))
+		for _, tag := range feedback.Tags {
+			tags = append(tags, string(tag))
+		}
+		q.Set("tags", strings.Join(tags, ","))
+	}
+
+	var res model.Bid
+	err := c.do(ctx, http.MethodPut, bidPath(bidId, "feedback"), q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) EditBidReview(
+	ctx context.Context,
+	bidId uuid.UUID,
+	reviewId uuid.UUID,
+	update ReviewUpdate,
+	username string,
+) (*model.Review, error) {
+	q := url.Values{"username": {username}}
+	path := bidPath(bidId, "reviews", reviewId.String())
+
+	var res model.Review
+	err := c.do(ctx, http.MethodPatch, path, q, update, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) DeleteBidReview(
+	ctx context.Context, bidId uuid.UUID, reviewId uuid.UUID, username string,
+) error {
+	q := url.Values{"username": {username}}
+	path := bidPath(bidId, "reviews", reviewId.String())
+	return c.do(ctx, http.MethodDelete, path, q, nil, nil)
+}
+
+func (c *Client) ReplyToBidReview(
+	ctx context.Context,
+	bidId uuid.UUID,
+	reviewId uuid.UUID,
+	text string,
+	username string,
+) (*model.ReviewReply, error) {
+	q := url.Values{"username": {username}}
+	path := bidPath(bidId, "reviews", reviewId.String(), "replies")
+	body := map[string]string{"text": text}
+
+	var res model.ReviewReply
+	err := c.do(ctx, http.MethodPost, path, q, body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) RollbackBid(
+	ctx context.Context, bidId uuid.UUID, version int32, username string,
+) (*model.Bid, error) {
+	q := url.Values{"username": {username}}
+	path := bidPath(bidId, "rollback", strconv.Itoa(int(version)))
+
+	var res model.Bid
+	err := c.do(ctx, http.MethodPut, path, q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetBidHistory(
+	ctx context.Context, bidId uuid.UUID, username string,
+) ([]*model.Bid, error) {
+	q := url.Values{"username": {username}}
+
+	var bids []*model.Bid
+	err := c.do(ctx, http.MethodGet, bidPath(bidId, "history"), q, nil, &bids)
+	return bids, err
+}
+
+func (c *Client) GetBidReviews(
+	ctx context.Context,
+	tenderId uuid.UUID,
+	authorUsername string,
+	requesterUsername string,
+	page Page,
+) ([]*model.Review, error) {
+	q := url.Values{
+		"authorUsername":    {authorUsername},
+		"requesterUsername": {requesterUsername},
+	}
+	page.apply(q)
+
+	var reviews []*model.Review
+	err := c.do(ctx, http.MethodGet, bidPath(tenderId, "reviews"), q, nil, &reviews)
+	return reviews, err
+}
+
+func bidPath(id uuid.UUID, parts ...string) string {
+	path := "/api/bids/" + id.String()
+	for _, part := range parts {
+		path += "/" + url.PathEscape(part)
+	}
+	return path
+}
+
+func (c *Client) GetBidderReputation(
+	ctx context.Context,
+	authorType model.BidAuthorType,
+	authorId uuid.UUID,
+	reviewsLimit int,
+	username string,
+) (*model.Reputation, error) {
+	q := url.Values{
+		"authorType":   {string(authorType)},
+		"reviewsLimit": {strconv.Itoa(reviewsLimit)},
+		"username":     {username},
+	}
+	path := "/api/bids/reputation/" + authorId.String()
+
+	var res model.Reputation
+	err := c.do(ctx, http.MethodGet, path, q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
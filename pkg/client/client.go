@@ -0,0 +1,242 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrBadRequest = errors.New("bad request")
+var ErrConflict = errors.New("conflict")
+var ErrUnauthorized = errors.New("user does not exist")
+var ErrForbidden = errors.New("not enough rights")
+var ErrNotFound = errors.New("object does not exist")
+var ErrTooManyRequests = errors.New("too many requests")
+var ErrServer = errors.New("server error")
+
+type Error struct {
+	StatusCode int
+	Reason     string
+}
+
+func (e *Error) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("tender api: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("tender api: %d %s", e.StatusCode, e.Reason)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrTooManyRequests:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+type idempotencyKeyContext struct{}
+
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContext{}, key)
+}
+
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContext{}).(string)
+	return key
+}
+
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+func (p Page) apply(q url.Values) {
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+}
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	maxRetries int
+	retryDelay time.Duration
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func WithRetries(maxRetries int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = delay
+	}
+}
+
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, errors.New("base url must be absolute")
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		maxRetries: 3,
+		retryDelay: 200 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/api/ping", nil, nil, nil)
+}
+
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	body any,
+	out any,
+) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	u := *c.baseURL
+	u.Path += path
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+
+	attempts := 1
+	if isIdempotent(method) || idempotencyKey(ctx) != "" {
+		attempts += max(c.maxRetries, 0)
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(c.retryDelay * time.Duration(1<<(attempt-1)))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		var retry bool
+		retry, err = c.send(ctx, method, u.String(), payload, out)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *Client) send(
+	ctx context.Context,
+	method string,
+	target string,
+	payload []byte,
+	out any,
+) (retry bool, err error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reqBody)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if key := idempotencyKey(ctx); key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return true, err
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return isRetryable(res.StatusCode), errorFromBody(res.StatusCode, data)
+	}
+
+	if out == nil {
+		return false, nil
+	}
+	err = json.Unmarshal(data, out)
+	return false, err
+}
+
+func errorFromBody(statusCode int, data []byte) *Error {
+	apiErr := &Error{StatusCode: statusCode}
+	var errRes struct {
+		Reason string `json:"reason"`
+	}
+	if json.Unmarshal(data, &errRes) == nil {
+		apiErr.Reason = errRes.Reason
+	}
+	return apiErr
+}
+
+func isRetryable(statusCode int) bool {
+	return statusCode >= http.StatusInternalServerError ||
+		statusCode == http.StatusTooManyRequests
+}
+
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
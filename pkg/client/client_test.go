@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"avi/internal/api/router"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	requests map[string]int
+	keys     []string
+}
+
+func (rec *recorder) count(method string) int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.requests[method]
+}
+
+func newTestClient(t *testing.T, handler http.Handler) (*Client, *recorder) {
+	t.Helper()
+
+	rec := &recorder{requests: map[string]int{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		rec.requests[r.Method]++
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			rec.keys = append(rec.keys, key)
+		}
+		rec.mu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, WithRetries(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, rec
+}
+
+func newTender() NewTender {
+	return NewTender{
+		Name:            "tender",
+		Description:     "description",
+		ServiceType:     "Delivery",
+		OrganizationId:  uuid.New(),
+		CreatorUsername: "user",
+	}
+}
+
+func TestPing(t *testing.T) {
+	c, _ := newTestClient(t, router.New())
+
+	err := c.Ping(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	c, _ := newTestClient(t, router.New())
+	ctx := context.Background()
+
+	_, err := c.CreateTender(ctx, NewTender{})
+	if !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("invalid tender: got %v, want ErrBadRequest", err)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Reason == "" {
+		t.Fatalf("invalid tender: got %#v, want 400 with reason", apiErr)
+	}
+
+	_, err = c.GetTenders(ctx, "", Page{})
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("tenders without database: got %v, want ErrServer", err)
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	tests := []struct {
+		status int
+		target error
+	}{
+		{http.StatusBadRequest, ErrBadRequest},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusTooManyRequests, ErrTooManyRequests},
+		{http.StatusInternalServerError, ErrServer},
+		{http.StatusServiceUnavailable, ErrServer},
+	}
+	targets := []error{
+		ErrBadRequest, ErrUnauthorized, ErrForbidden, ErrNotFound,
+		ErrConflict, ErrTooManyRequests, ErrServer,
+	}
+	for _, test := range tests {
+		err := error(&Error{StatusCode: test.status})
+		for _, target := range targets {
+			if got, want := errors.Is(err, target), target == test.target; got != want {
+				t.Errorf("status %d: errors.Is(%v) = %v, want %v", test.status, target, got, want)
+			}
+		}
+	}
+}
+
+func TestRetriesOnlyIdempotentRequests(t *testing.T) {
+	c, rec := newTestClient(t, router.New())
+	ctx := context.Background()
+
+	_, err := c.GetTenders(ctx, "", Page{})
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("get: got %v, want ErrServer", err)
+	}
+	if got := rec.count(http.MethodGet); got != 3 {
+		t.Errorf("get: %d attempts, want 3", got)
+	}
+
+	_, err = c.CreateTender(ctx, newTender())
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("post: got %v, want ErrServer", err)
+	}
+	if got := rec.count(http.MethodPost); got != 1 {
+		t.Errorf("post: %d attempts, want 1", got)
+	}
+
+	_, err = c.UpdateTenderStatus(ctx, uuid.New(), "Published", "user")
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("put: got %v, want ErrServer", err)
+	}
+	if got := rec.count(http.MethodPut); got != 1 {
+		t.Errorf("put: %d attempts, want 1", got)
+	}
+}
+
+func TestRetriesRequestsWithIdempotencyKey(t *testing.T) {
+	c, rec := newTestClient(t, router.New())
+	ctx := WithIdempotencyKey(context.Background(), "key")
+
+	_, err := c.CreateTender(ctx, newTender())
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("post: got %v, want ErrServer", err)
+	}
+	if got := rec.count(http.MethodPost); got != 3 {
+		t.Errorf("post: %d attempts, want 3", got)
+	}
+	if len(rec.keys) != 3 {
+		t.Errorf("Idempotency-Key header is sent %d times, want 3", len(rec.keys))
+	}
+	for _, key := range rec.keys {
+		if key != "key" {
+			t.Errorf("Idempotency-Key header %q, want %q", key, "key")
+		}
+	}
+}
+
+func TestRetriesHead(t *testing.T) {
+	c, rec := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	err := c.do(context.Background(), http.MethodHead, "/api/ping", nil, nil, nil)
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("head: got %v, want ErrServer", err)
+	}
+	if got := rec.count(http.MethodHead); got != 3 {
+		t.Errorf("head: %d attempts, want 3", got)
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+func (c *Client) InviteToTender(
+	ctx context.Context,
+	tenderId uuid.UUID,
+	username string,
+	inviteeType model.InviteeType,
+	inviteeId uuid.UUID,
+) (*model.Invitation, error) {
+	q := url.Values{"username": {username}}
+	body := struct {
+		InviteeType model.InviteeType `json:"inviteeType"`
+		InviteeId   uuid.UUID         `json:"inviteeId"`
+	}{InviteeType: inviteeType, InviteeId: inviteeId}
+
+	var res model.Invitation
+	err := c.do(ctx, http.MethodPost, tenderPath(tenderId, "invitations"), q, body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetTenderInvitations(
+	ctx context.Context, tenderId uuid.UUID, username string,
+) ([]*model.Invitation, error) {
+	q := url.Values{"username": {username}}
+
+	var invitations []*model.Invitation
+	err := c.do(ctx, http.MethodGet, tenderPath(tenderId, "invitations"), q, nil, &invitations)
+	return invitations, err
+}
+
+func (c *Client) RevokeTenderInvitation(
+	ctx context.Context, tenderId uuid.UUID, invitationId uuid.UUID, username string,
+) error {
+	q := url.Values{"username": {username}}
+	path := tenderPath(tenderId, "invitations", invitationId.String())
+	return c.do(ctx, http.MethodDelete, path, q, nil, nil)
+}
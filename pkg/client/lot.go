@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type NewLot struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Quantity    int      `json:"quantity"`
+	Budget      *float64 `json:"budget,omitempty"`
+}
+
+func (c *Client) CreateLot(
+	ctx context.Context, tenderId uuid.UUID, username string, lot NewLot,
+) (*model.Lot, error) {
+	q := url.Values{"username": {username}}
+
+	var res model.Lot
+	err := c.do(ctx, http.MethodPost, tenderPath(tenderId, "lots"), q, lot, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetLots(
+	ctx context.Context, tenderId uuid.UUID, username string,
+) ([]*model.Lot, error) {
+	q := url.Values{}
+	if username != "" {
+		q.Set("username", username)
+	}
+
+	var lots []*model.Lot
+	err := c.do(ctx, http.MethodGet, tenderPath(tenderId, "lots"), q, nil, &lots)
+	return lots, err
+}
+
+func (c *Client) CancelLot(
+	ctx context.Context, tenderId uuid.UUID, lotId uuid.UUID, username string,
+) (*model.Lot, error) {
+	q := url.Values{"username": {username}}
+
+	var res model.Lot
+	path := tenderPath(tenderId, "lots", lotId.String(), "cancel")
+	err := c.do(ctx, http.MethodPut, path, q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type Answer struct {
+	Text       string                 `json:"text"`
+	Visibility model.AnswerVisibility `json:"visibility"`
+}
+
+func (c *Client) AskQuestion(
+	ctx context.Context, tenderId uuid.UUID, username string, text string,
+) (*model.Question, error) {
+	q := url.Values{"username": {username}}
+	body := struct {
+		Text string `json:"text"`
+	}{Text: text}
+
+	var res model.Question
+	err := c.do(ctx, http.MethodPost, tenderPath(tenderId, "questions"), q, body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetQuestions(
+	ctx context.Context, tenderId uuid.UUID, username string, page Page,
+) ([]*model.Question, error) {
+	q := url.Values{"username": {username}}
+	page.apply(q)
+
+	var questions []*model.Question
+	err := c.do(ctx, http.MethodGet, tenderPath(tenderId, "questions"), q, nil, &questions)
+	return questions, err
+}
+
+func (c *Client) AnswerQuestion(
+	ctx context.Context, tenderId uuid.UUID, questionId uuid.UUID, username string, answer Answer,
+) (*model.Question, error) {
+	q := url.Values{"username": {username}}
+	path := tenderPath(tenderId, "questions", questionId.String(), "answer")
+
+	var res model.Question
+	err := c.do(ctx, http.MethodPut, path, q, answer, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
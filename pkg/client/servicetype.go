@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"avi/internal/model"
+)
+
+type NewServiceType struct {
+	Code       model.TenderServiceType  `json:"code"`
+	Name       string                   `json:"name"`
+	ParentCode *model.TenderServiceType `json:"parentCode,omitempty"`
+}
+
+type ServiceTypeUpdate struct {
+	Name       string                   `json:"name,omitempty"`
+	ParentCode *model.TenderServiceType `json:"parentCode,omitempty"`
+}
+
+func (c *Client) GetServiceTypes(
+	ctx context.Context, parentCode model.TenderServiceType,
+) ([]*model.ServiceType, error) {
+	q := url.Values{}
+	if parentCode != "" {
+		q.Set("parentCode", string(parentCode))
+	}
+
+	var serviceTypes []*model.ServiceType
+	err := c.do(ctx, http.MethodGet, "/api/service_types", q, nil, &serviceTypes)
+	return serviceTypes, err
+}
+
+func (c *Client) CreateServiceType(
+	ctx context.Context, username string, serviceType NewServiceType,
+) (*model.ServiceType, error) {
+	q := url.Values{"username": {username}}
+
+	var res model.ServiceType
+	err := c.do(ctx, http.MethodPost, "/api/service_types/new", q, serviceType, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) EditServiceType(
+	ctx context.Context, code model.TenderServiceType, username string, update ServiceTypeUpdate,
+) (*model.ServiceType, error) {
+	q := url.Values{"username": {username}}
+	path := "/api/service_types/" + url.PathEscape(string(code)) + "/edit"
+
+	var res model.ServiceType
+	err := c.do(ctx, http.MethodPatch, path, q, update, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) DeleteServiceType(
+	ctx context.Context, code model.TenderServiceType, username string,
+) error {
+	q := url.Values{"username": {username}}
+	path := "/api/service_types/" + url.PathEscape(string(code))
+	return c.do(ctx, http.MethodDelete, path, q, nil, nil)
+}
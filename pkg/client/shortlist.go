@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+func (c *Client) ShortlistBidAuthor(
+	ctx context.Context, tenderId uuid.UUID, bidId uuid.UUID, username string,
+) (*model.ShortlistEntry, error) {
+	q := url.Values{"username": {username}}
+	body := struct {
+		BidId uuid.UUID `json:"bidId"`
+	}{BidId: bidId}
+
+	var res model.ShortlistEntry
+	err := c.do(ctx, http.MethodPost, tenderPath(tenderId, "shortlist"), q, body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetShortlist(
+	ctx context.Context, tenderId uuid.UUID, username string,
+) ([]*model.ShortlistEntry, error) {
+	q := url.Values{"username": {username}}
+
+	var entries []*model.ShortlistEntry
+	err := c.do(ctx, http.MethodGet, tenderPath(tenderId, "shortlist"), q, nil, &entries)
+	return entries, err
+}
+
+func (c *Client) RemoveFromShortlist(
+	ctx context.Context, tenderId uuid.UUID, entryId uuid.UUID, username string,
+) error {
+	q := url.Values{"username": {username}}
+	path := tenderPath(tenderId, "shortlist", entryId.String())
+	return c.do(ctx, http.MethodDelete, path, q, nil, nil)
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type NewTemplate struct {
+	Name           string                  `json:"name"`
+	Description    string                  `json:"description"`
+	ServiceType    model.TenderServiceType `json:"serviceType"`
+	OrganizationId uuid.UUID               `json:"organizationId"`
+	Criteria       []string                `json:"criteria,omitempty"`
+}
+
+type TemplateUpdate struct {
+	Name        string                  `json:"name,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	ServiceType model.TenderServiceType `json:"serviceType,omitempty"`
+	Criteria    []string                `json:"criteria,omitempty"`
+}
+
+type TemplateTender struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InviteOnly  bool   `json:"inviteOnly,omitempty"`
+}
+
+func (c *Client) CreateTemplate(
+	ctx context.Context, username string, template NewTemplate,
+) (*model.TenderTemplate, error) {
+	q := url.Values{"username": {username}}
+
+	var res model.TenderTemplate
+	err := c.do(ctx, http.MethodPost, "/api/templates/new", q, template, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetTemplates(
+	ctx context.Context, username string,
+) ([]*model.TenderTemplate, error) {
+	q := url.Values{"username": {username}}
+
+	var templates []*model.TenderTemplate
+	err := c.do(ctx, http.MethodGet, "/api/templates", q, nil, &templates)
+	return templates, err
+}
+
+func (c *Client) EditTemplate(
+	ctx context.Context, templateId uuid.UUID, username string, update TemplateUpdate,
+) (*model.TenderTemplate, error) {
+	q := url.Values{"username": {username}}
+
+	var res model.TenderTemplate
+	err := c.do(ctx, http.MethodPatch, templatePath(templateId, "edit"), q, update, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) DeleteTemplate(
+	ctx context.Context, templateId uuid.UUID, username string,
+) error {
+	q := url.Values{"username": {username}}
+	return c.do(ctx, http.MethodDelete, templatePath(templateId), q, nil, nil)
+}
+
+func (c *Client) CreateTenderFromTemplate(
+	ctx context.Context, templateId uuid.UUID, username string, tender TemplateTender,
+) (*model.Tender, error) {
+	q := url.Values{"username": {username}}
+
+	var res model.Tender
+	err := c.do(ctx, http.MethodPost, templatePath(templateId, "tenders"), q, tender, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) UploadTemplateAttachment(
+	ctx context.Context, templateId uuid.UUID, username string, fileName string, content io.Reader,
+) (*model.Attachment, error) {
+	q := url.Values{"username": {username}}
+	return c.upload(ctx, templatePath(templateId, "attachments"), q, fileName, content)
+}
+
+func (c *Client) GetTemplateAttachments(
+	ctx context.Context, templateId uuid.UUID, username string,
+) ([]*model.Attachment, error) {
+	q := url.Values{"username": {username}}
+
+	var attachments []*model.Attachment
+	err := c.do(ctx, http.MethodGet, templatePath(templateId, "attachments"), q, nil, &attachments)
+	return attachments, err
+}
+
+func (c *Client) DownloadTemplateAttachment(
+	ctx context.Context, templateId uuid.UUID, attachmentId uuid.UUID, username string,
+) (*AttachmentContent, error) {
+	q := url.Values{"username": {username}}
+	path := templatePath(templateId, "attachments", attachmentId.String())
+	return c.download(ctx, path, q)
+}
+
+func (c *Client) DeleteTemplateAttachment(
+	ctx context.Context, templateId uuid.UUID, attachmentId uuid.UUID, username string,
+) (*model.TenderTemplate, error) {
+	q := url.Values{"username": {username}}
+	path := templatePath(templateId, "attachments", attachmentId.String())
+
+	var res model.TenderTemplate
+	err := c.do(ctx, http.MethodDelete, path, q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func templatePath(templateId uuid.UUID, parts ...string) string {
+	path := "/api/templates/" + templateId.String()
+	for _, part := range parts {
+		path += "/" + url.PathEscape(part)
+	}
+	return path
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"avi/internal/model"
+)
+
+type NewTender struct {
+	Name            string                  `json:"name"`
+	Description     string                  `json:"description"`
+	ServiceType     model.TenderServiceType `json:"serviceType"`
+	OrganizationId  uuid.UUID               `json:"organizationId"`
+	CreatorUsername string                  `json:"creatorUsername"`
+	InviteOnly      bool                    `json:"inviteOnly,omitempty"`
+	Criteria        []string                `json:"criteria,omitempty"`
+	Budget          *float64                `json:"budget,omitempty"`
+	Reserve         *float64                `json:"reserve,omitempty"`
+	Currency        string                  `json:"currency,omitempty"`
+	StrictBudget    bool                    `json:"strictBudget,omitempty"`
+}
+
+type TenderUpdate struct {
+	Name         string                  `json:"name,omitempty"`
+	Description  string                  `json:"description,omitempty"`
+	ServiceType  model.TenderServiceType `json:"serviceType,omitempty"`
+	InviteOnly   *bool                   `json:"inviteOnly,omitempty"`
+	Criteria     []string                `json:"criteria,omitempty"`
+	Budget       *float64                `json:"budget,omitempty"`
+	Reserve      *float64                `json:"reserve,omitempty"`
+	Currency     string                  `json:"currency,omitempty"`
+	StrictBudget *bool                   `json:"strictBudget,omitempty"`
+}
+
+func (c *Client) GetTenders(
+	ctx context.Context, serviceType model.TenderServiceType, page Page,
+) ([]*model.Tender, error) {
+	q := url.Values{}
+	if serviceType != "" {
+		q.Set("serviceType", string(serviceType))
+	}
+	page.apply(q)
+
+	var tenders []*model.Tender
+	err := c.do(ctx, http.MethodGet, "/api/tenders", q, nil, &tenders)
+	return tenders, err
+}
+
+func (c *Client) GetTendersInCategory(
+	ctx context.Context, serviceType model.TenderServiceType, page Page,
+) ([]*model.Tender, error) {
+	q := url.Values{
+		"serviceType":          {string(serviceType)},
+		"includeSubcategories": {"true"},
+	}
+	page.apply(q)
+
+	var tenders []*model.Tender
+	err := c.do(ctx, http.MethodGet, "/api/tenders", q, nil, &tenders)
+	return tenders, err
+}
+
+func (c *Client) GetVisibleTenders(
+	ctx context.Context, serviceType model.TenderServiceType, username string, page Page,
+) ([]*model.Tender, error) {
+	q := url.Values{"username": {username}}
+	if serviceType != "" {
+		q.Set("serviceType", string(serviceType))
+	}
+	page.apply(q)
+
+	var tenders []*model.Tender
+	err := c.do(ctx, http.MethodGet, "/api/tenders", q, nil, &tenders)
+	return tenders, err
+}
+
+func (c *Client) CreateTender(ctx context.Context, tender NewTender) (*model.Tender, error) {
+	var res model.Tender
+	err := c.do(ctx, http.MethodPost, "/api/tenders/new", nil, tender, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetMyTenders(
+	ctx context.Context, username string, page Page,
+) ([]*model.Tender, error) {
+	q := url.Values{"username": {username}}
+	page.apply(q)
+
+	var tenders []*model.Tender
+	err := c.do(ctx, http.MethodGet, "/api/tenders/my", q, nil, &tenders)
+	return tenders, err
+}
+
+func (c *Client) EditTender(
+	ctx context.Context, tenderId uuid.UUID, username string, update TenderUpdate,
+) (*model.Tender, error) {
+	q := url.Values{"username": {username}}
+
+	var res model.Tender
+	err := c.do(ctx, http.MethodPatch, tenderPath(tenderId, "edit"), q, update, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetTenderStatus(
+	ctx context.Context, tenderId uuid.UUID, username string,
+) (model.TenderStatus, error) {
+	q := url.Values{}
+	if username != "" {
+		q.Set("username", username)
+	}
+
+	var status model.TenderStatus
+	err := c.do(ctx, http.MethodGet, tenderPath(tenderId, "status"), q, nil, &status)
+	return status, err
+}
+
+func (c *Client) UpdateTenderStatus(
+	ctx context.Context, tenderId uuid.UUID, status model.TenderStatus, username string,
+) (*model.Tender, error) {
+	q := url.Values{"status": {string(status)}, "username": {username}}
+
+	var res model.Tender
+	err := c.do(ctx, http.MethodPut, tenderPath(tenderId, "status"), q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) RollbackTender(
+	ctx context.Context, tenderId uuid.UUID, version int32, username string,
+) (*model.Tender, error) {
+	q := url.Values{"username": {username}}
+	path := tenderPath(tenderId, "rollback", strconv.Itoa(int(version)))
+
+	var res model.Tender
+	err := c.do(ctx, http.MethodPut, path, q, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetTenderHistory(
+	ctx context.Context, tenderId uuid.UUID, username string,
+) ([]*model.Tender, error) {
+	q := url.Values{"username": {username}}
+
+	var tenders []*model.Tender
+	err := c.do(ctx, http.MethodGet, tenderPath(tenderId, "history"), q, nil, &tenders)
+	return tenders, err
+}
+
+func (c *Client) CloneTender(
+	ctx context.Context, tenderId uuid.UUID, username string, name string,
+) (*model.Tender, error) {
+	q := url.Values{"username": {username}}
+	body := struct {
+		Name string `json:"name,omitempty"`
+	}{Name: name}
+
+	var res model.Tender
+	err := c.do(ctx, http.MethodPost, tenderPath(tenderId, "clone"), q, body, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func tenderPath(tenderId uuid.UUID, parts ...string) string {
+	path := "/api/tenders/" + tenderId.String()
+	for _, part := range parts {
+		path += "/" + url.PathEscape(part)
+	}
+	return path
+}